@@ -0,0 +1,111 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+)
+
+// ActorStats holds the inbound social graph counts for an account. A field that
+// failed to load is -1 and has its error in Errors.
+type ActorStats struct {
+	Followers    int `json:"followers"`
+	Blockers     int `json:"blockers"`     // Accounts blocking this one
+	Lists        int `json:"lists"`        // List items referencing this account
+	StarterPacks int `json:"starterPacks"` // Starter packs built on those lists
+
+	// Errors maps field names to the error that prevented loading them
+	Errors map[string]error `json:"-"`
+}
+
+// Err returns the combined errors of the fields that failed, or nil
+func (s *ActorStats) Err() error {
+	var errs []error
+	for _, field := range []string{"Followers", "Blockers", "Lists", "StarterPacks"} {
+		if err := s.Errors[field]; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ActorStats fetches follower, block, list membership, and starter pack counts
+// for the account did concurrently. Failed fields are reported in
+// ActorStats.Errors; an error is returned only if every field failed.
+func (c *Client) ActorStats(ctx context.Context, did string) (*ActorStats, error) {
+	stats := &ActorStats{Followers: -1, Blockers: -1, Lists: -1, StarterPacks: -1}
+	fields := []string{"Followers", "Blockers", "Lists", "StarterPacks"}
+	errs := fanOut(
+		func() (err error) {
+			stats.Followers, err = c.FollowerCount(ctx, did)
+			return err
+		},
+		func() (err error) {
+			stats.Blockers, err = c.BlockerCount(ctx, did)
+			return err
+		},
+		func() error {
+			count, err := c.GetLinksCountContext(ctx, listItemsParams(did))
+			if err == nil {
+				stats.Lists = count.Total
+			}
+			return err
+		},
+		func() (err error) {
+			stats.StarterPacks, err = c.starterPackCount(ctx, did)
+			return err
+		},
+	)
+
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if stats.Errors == nil {
+			stats.Errors = make(map[string]error)
+		}
+		stats.Errors[fields[i]] = err
+	}
+	if len(stats.Errors) == len(fields) {
+		return nil, stats.Err()
+	}
+	return stats, nil
+}
+
+// starterPackCount counts the starter packs including the account did, using the
+// counts endpoint for each list containing it. Like a count by paging, the list
+// item scan stops at the count scan limit, making the result a lower bound.
+func (c *Client) starterPackCount(ctx context.Context, did string) (int, error) {
+	limit := c.countScanLimit()
+	items, err := c.ListsContaining(ctx, did, PaginateOptions{MaxRecords: limit})
+	if err != nil {
+		return -1, err
+	}
+	if len(items) >= limit {
+		c.warn("too many lists to scan for starter packs; result is a lower bound",
+			"did", did, "limit", limit)
+	}
+	lists, err := c.listURIs(ctx, items)
+	if err != nil {
+		return -1, err
+	}
+
+	counts := make([]int, len(lists))
+	errs := make([]error, len(lists))
+	parallelEach(len(lists), DefaultParallelism, func(i int) {
+		count, err := c.GetLinksCountContext(ctx, LinksParams{Target: lists[i], Collection: CollectionStarterPack, Path: PathList})
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		counts[i] = count.Total
+	})
+
+	total := 0
+	for i := range lists {
+		if errs[i] != nil {
+			return -1, errs[i]
+		}
+		total += counts[i]
+	}
+	return total, nil
+}
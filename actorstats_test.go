@@ -0,0 +1,100 @@
+package constellation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestActorStats tests the concurrent fan-out with a failing field
+func TestActorStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("collection") {
+		case "app.bsky.graph.follow":
+			w.Write([]byte(`{"total": 42}`))
+		case "app.bsky.graph.block":
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+		case "app.bsky.graph.listitem":
+			if r.URL.Path == "/links/count" {
+				w.Write([]byte(`{"total": 2}`))
+				return
+			}
+			w.Write([]byte(`{"total": 2, "linking_records": [
+				{"did": "did:plc:owner", "collection": "app.bsky.graph.listitem", "rkey": "1", "value": {"list": "at://did:plc:owner/app.bsky.graph.list/a"}},
+				{"did": "did:plc:owner", "collection": "app.bsky.graph.listitem", "rkey": "2", "value": {"list": "at://did:plc:owner/app.bsky.graph.list/b"}}
+			]}`))
+		case "app.bsky.graph.starterpack":
+			w.Write([]byte(`{"total": 1}`))
+		}
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	stats, err := client.ActorStats(context.Background(), "did:plc:alice")
+	if err != nil {
+		t.Fatalf("Expected partial results, got %v", err)
+	}
+
+	if stats.Followers != 42 || stats.Lists != 2 || stats.StarterPacks != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.Blockers != -1 || stats.Errors["Blockers"] == nil {
+		t.Errorf("Expected Blockers to fail, got %d, %v", stats.Blockers, stats.Errors)
+	}
+	if len(stats.Errors) != 1 || stats.Err() == nil {
+		t.Errorf("Expected exactly one field error, got %v", stats.Errors)
+	}
+}
+
+// TestActorStatsAllFailed tests that an error is returned when no field loads
+func TestActorStatsAllFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	if stats, err := client.ActorStats(context.Background(), "did:plc:alice"); err == nil {
+		t.Errorf("Expected an error, got %+v", stats)
+	}
+}
+
+// TestActorStatsStarterPackLimit tests that the list item scan behind the starter
+// pack count stops at the count scan limit
+func TestActorStatsStarterPackLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch query.Get("collection") {
+		case "app.bsky.graph.listitem":
+			w.Write([]byte(`{"total": 3, "linking_records": [
+				{"did": "did:plc:owner", "collection": "app.bsky.graph.listitem", "rkey": "1", "value": {"list": "at://did:plc:owner/app.bsky.graph.list/a"}},
+				{"did": "did:plc:owner", "collection": "app.bsky.graph.listitem", "rkey": "2", "value": {"list": "at://did:plc:owner/app.bsky.graph.list/b"}},
+				{"did": "did:plc:owner", "collection": "app.bsky.graph.listitem", "rkey": "3", "value": {"list": "at://did:plc:owner/app.bsky.graph.list/c"}}
+			]}`))
+		case "app.bsky.graph.starterpack":
+			if strings.HasSuffix(query.Get("target"), "/a") {
+				w.Write([]byte(`{"total": 1}`))
+			} else {
+				w.Write([]byte(`{"total": 10}`))
+			}
+		default:
+			w.Write([]byte(`{"total": 0}`))
+		}
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.CountScanLimit = 2
+	stats, err := client.ActorStats(context.Background(), "did:plc:alice")
+	if err != nil {
+		t.Fatalf("ActorStats failed: %v", err)
+	}
+	if stats.StarterPacks != 11 {
+		t.Errorf("Expected the starter packs of the first two lists, got %d", stats.StarterPacks)
+	}
+}
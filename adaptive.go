@@ -0,0 +1,81 @@
+package constellation
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+const (
+	// DefaultAdaptiveFactor multiplies the p99 latency to derive a request timeout
+	DefaultAdaptiveFactor = 3.0
+	// DefaultAdaptiveMinSamples is the number of successful requests to an endpoint
+	// needed before its timeout adapts
+	DefaultAdaptiveMinSamples = 20
+)
+
+// AdaptiveTimeout derives per-endpoint request timeouts from observed latency
+// (p99 × Factor) instead of a fixed value, reducing both premature timeouts on
+// slow endpoints and long hangs on fast ones. Derived timeouts are clamped to
+// [Min, Max]; the HTTP client's own Timeout still applies as an outer bound.
+type AdaptiveTimeout struct {
+	Factor     float64       // Defaults to DefaultAdaptiveFactor
+	Min        time.Duration // Lower bound on derived timeouts
+	Max        time.Duration // Upper bound on derived timeouts; zero for none
+	MinSamples int           // Defaults to DefaultAdaptiveMinSamples
+}
+
+// EnableAdaptiveTimeout turns on adaptive request timeouts. Until an endpoint has
+// enough samples, its requests use only the HTTP client's Timeout.
+func (c *Client) EnableAdaptiveTimeout(config AdaptiveTimeout) *Client {
+	if config.Factor <= 0 {
+		config.Factor = DefaultAdaptiveFactor
+	}
+	if config.MinSamples <= 0 {
+		config.MinSamples = DefaultAdaptiveMinSamples
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.adaptive = &config
+	return c
+}
+
+// RequestTimeout returns the adaptive timeout currently derived for endpoint, or
+// false if adaptive timeouts are off or the endpoint lacks samples
+func (c *Client) RequestTimeout(endpoint string) (time.Duration, bool) {
+	c.mu.Lock()
+	config := c.adaptive
+	c.mu.Unlock()
+
+	if config == nil {
+		return 0, false
+	}
+
+	latencies := c.metrics.latencies(endpoint)
+	if len(latencies) < config.MinSamples {
+		return 0, false
+	}
+
+	timeout := time.Duration(float64(percentile(latencies, 99)) * config.Factor)
+	if timeout < config.Min {
+		timeout = config.Min
+	}
+	if config.Max > 0 && timeout > config.Max {
+		timeout = config.Max
+	}
+	return timeout, true
+}
+
+// cancelBody cancels a request's timeout context when its body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
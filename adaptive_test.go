@@ -0,0 +1,48 @@
+package constellation_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestAdaptiveTimeout tests timeouts derived from observed latency
+func TestAdaptiveTimeout(t *testing.T) {
+	var slow atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if slow.Load() {
+			time.Sleep(500 * time.Millisecond)
+		}
+		w.Write([]byte(`{"total": 1}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.EnableAdaptiveTimeout(constellation.AdaptiveTimeout{Min: 20 * time.Millisecond, Max: time.Second, MinSamples: 5})
+	params := constellation.LinksParams{Target: "did:plc:example"}
+
+	if _, ok := client.RequestTimeout("/links/count"); ok {
+		t.Fatal("Expected no adaptive timeout before samples are collected")
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetLinksCount(params); err != nil {
+			t.Fatalf("Failed to get count: %v", err)
+		}
+	}
+
+	timeout, ok := client.RequestTimeout("/links/count")
+	if !ok || timeout < 20*time.Millisecond || timeout > 200*time.Millisecond {
+		t.Fatalf("Expected a short adaptive timeout, got %v, %v", timeout, ok)
+	}
+
+	slow.Store(true)
+	if _, err := client.GetLinksCount(params); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the slow request to time out, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+// Package agg provides composable single-pass aggregations over streams of link
+// records, so analytics over very large result sets or exports don't hold the
+// records in memory. Memory grows only with the number of groups and buckets.
+//
+//	perHour := agg.TimeBucket(time.Hour, agg.Count)
+//	perDID := agg.GroupBy(agg.DID, agg.Count)
+//	err := agg.Run(client.Links(ctx, params, opts), perHour, perDID)
+//
+// Run needs Go 1.23 iterators; on older toolchains, pass Each to GetLinksEach.
+package agg
+
+import (
+	"sort"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// Aggregator consumes records one at a time
+type Aggregator interface {
+	Add(record constellation.LinkRecord)
+}
+
+// Each returns a callback feeding every record to each aggregator, for
+// Client.GetLinksEach and VisitLinks on toolchains without iterators:
+//
+//	err := client.GetLinksEach(ctx, params, opts, agg.Each(perHour, perDID))
+func Each(aggregators ...Aggregator) func(constellation.LinkRecord) error {
+	return func(record constellation.LinkRecord) error {
+		for _, aggregator := range aggregators {
+			aggregator.Add(record)
+		}
+		return nil
+	}
+}
+
+// Counter counts records
+type Counter struct {
+	N int
+}
+
+// Count returns a new Counter
+func Count() *Counter {
+	return &Counter{}
+}
+
+// Add implements Aggregator
+func (c *Counter) Add(constellation.LinkRecord) {
+	c.N++
+}
+
+// KeyFunc extracts a grouping key from a record
+type KeyFunc func(record constellation.LinkRecord) string
+
+// Common grouping keys
+var (
+	DID        KeyFunc = func(record constellation.LinkRecord) string { return record.DID }
+	Collection KeyFunc = func(record constellation.LinkRecord) string { return record.Collection }
+)
+
+// Groups aggregates records separately per key
+type Groups[A Aggregator] struct {
+	Groups map[string]A
+
+	key      KeyFunc
+	newInner func() A
+}
+
+// GroupBy returns an aggregator that feeds each record to a per-key aggregator
+// created by inner
+func GroupBy[A Aggregator](key KeyFunc, inner func() A) *Groups[A] {
+	return &Groups[A]{Groups: make(map[string]A), key: key, newInner: inner}
+}
+
+// Add implements Aggregator
+func (g *Groups[A]) Add(record constellation.LinkRecord) {
+	key := g.key(record)
+	inner, ok := g.Groups[key]
+	if !ok {
+		inner = g.newInner()
+		g.Groups[key] = inner
+	}
+	inner.Add(record)
+}
+
+// Keys returns the group keys in sorted order
+func (g *Groups[A]) Keys() []string {
+	keys := make([]string, 0, len(g.Groups))
+	for key := range g.Groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Buckets aggregates records separately per time window
+type Buckets[A Aggregator] struct {
+	Buckets map[time.Time]A // Keyed by window start, in UTC
+	Undated int             // Records with no usable timestamp
+
+	width    time.Duration
+	newInner func() A
+}
+
+// TimeBucket returns an aggregator that feeds each record to an aggregator for
+// its width-sized time window, using the record's indexed time or TID time
+func TimeBucket[A Aggregator](width time.Duration, inner func() A) *Buckets[A] {
+	return &Buckets[A]{Buckets: make(map[time.Time]A), width: width, newInner: inner}
+}
+
+// Add implements Aggregator
+func (b *Buckets[A]) Add(record constellation.LinkRecord) {
+	t, ok := record.Time()
+	if !ok {
+		b.Undated++
+		return
+	}
+
+	start := t.UTC().Truncate(b.width)
+	inner, ok := b.Buckets[start]
+	if !ok {
+		inner = b.newInner()
+		b.Buckets[start] = inner
+	}
+	inner.Add(record)
+}
+
+// Starts returns the window start times in chronological order
+func (b *Buckets[A]) Starts() []time.Time {
+	starts := make([]time.Time, 0, len(b.Buckets))
+	for start := range b.Buckets {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	return starts
+}
@@ -0,0 +1,28 @@
+package agg_test
+
+import (
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+	"github.com/tanner-caffrey/constellation-go/agg"
+)
+
+// TestEach tests feeding aggregators through a callback
+func TestEach(t *testing.T) {
+	total := agg.Count()
+	perDID := agg.GroupBy(agg.DID, agg.Count)
+	add := agg.Each(total, perDID)
+
+	for _, did := range []string{"did:plc:alice", "did:plc:bob", "did:plc:alice"} {
+		if err := add(constellation.LinkRecord{DID: did}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if total.N != 3 {
+		t.Errorf("Expected 3 records, got %d", total.N)
+	}
+	if alice := perDID.Groups["did:plc:alice"]; alice == nil || alice.N != 2 {
+		t.Errorf("Expected 2 records from alice, got %+v", alice)
+	}
+}
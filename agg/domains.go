@@ -0,0 +1,78 @@
+package agg
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// ExternalDomain keys posts by the domain of their external link card, from
+// .embed.external.uri or, on posts quoting a record alongside a link card,
+// .embed.media.external.uri. A leading "www." is dropped. Records without a link
+// card or without a value have an empty key.
+var ExternalDomain KeyFunc = func(record constellation.LinkRecord) string {
+	embed, _ := record.Value["embed"].(map[string]any)
+	if media, ok := embed["media"].(map[string]any); ok {
+		embed = media
+	}
+	external, _ := embed["external"].(map[string]any)
+	uri, _ := external["uri"].(string)
+	if uri == "" {
+		return ""
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Hostname() == "" {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+}
+
+// DomainCount is the number of posts linking to a domain
+type DomainCount struct {
+	Domain string
+	Posts  int
+}
+
+// DomainCounter counts posts per external link domain, for tracking which sites
+// a community shares most
+type DomainCounter struct {
+	Counts  map[string]int
+	Skipped int // Posts without a link card, or whose value wasn't included
+}
+
+// Domains returns a new DomainCounter
+func Domains() *DomainCounter {
+	return &DomainCounter{Counts: make(map[string]int)}
+}
+
+// Add implements Aggregator
+func (d *DomainCounter) Add(record constellation.LinkRecord) {
+	domain := ExternalDomain(record)
+	if domain == "" {
+		d.Skipped++
+		return
+	}
+	d.Counts[domain]++
+}
+
+// Top returns the n most linked domains, most posts first with ties ordered by
+// domain. All domains are returned when n is zero or negative.
+func (d *DomainCounter) Top(n int) []DomainCount {
+	top := make([]DomainCount, 0, len(d.Counts))
+	for domain, posts := range d.Counts {
+		top = append(top, DomainCount{Domain: domain, Posts: posts})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Posts != top[j].Posts {
+			return top[i].Posts > top[j].Posts
+		}
+		return top[i].Domain < top[j].Domain
+	})
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
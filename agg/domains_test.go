@@ -0,0 +1,43 @@
+package agg_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+	"github.com/tanner-caffrey/constellation-go/agg"
+)
+
+// linkCard returns a post record with an external link card to uri
+func linkCard(uri string) constellation.LinkRecord {
+	return constellation.LinkRecord{Value: map[string]any{
+		"embed": map[string]any{"external": map[string]any{"uri": uri}},
+	}}
+}
+
+// TestDomains tests counting posts per link card domain
+func TestDomains(t *testing.T) {
+	domains := agg.Domains()
+	add := agg.Each(domains)
+
+	add(linkCard("https://www.example.com/a"))
+	add(linkCard("https://EXAMPLE.com/b"))
+	add(linkCard("https://news.test/story"))
+	add(constellation.LinkRecord{Value: map[string]any{
+		"embed": map[string]any{"media": map[string]any{"external": map[string]any{"uri": "https://news.test/other"}}},
+	}})
+	add(constellation.LinkRecord{Value: map[string]any{"text": "no card"}})
+	add(constellation.LinkRecord{})
+	add(linkCard("not a url"))
+
+	want := []agg.DomainCount{{Domain: "example.com", Posts: 2}, {Domain: "news.test", Posts: 2}}
+	if got := domains.Top(0); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if got := domains.Top(1); len(got) != 1 || got[0].Domain != "example.com" {
+		t.Errorf("Expected the top domain only, got %+v", got)
+	}
+	if domains.Skipped != 3 {
+		t.Errorf("Expected 3 posts without a usable card, got %d", domains.Skipped)
+	}
+}
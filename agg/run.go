@@ -0,0 +1,22 @@
+//go:build go1.23
+
+package agg
+
+import (
+	"iter"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// Run feeds every record from seq to each aggregator in a single pass, stopping
+// at the first error
+func Run(seq iter.Seq2[constellation.LinkRecord, error], aggregators ...Aggregator) error {
+	add := Each(aggregators...)
+	for record, err := range seq {
+		if err != nil {
+			return err
+		}
+		add(record)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+//go:build go1.23
+
+package agg_test
+
+import (
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+	"github.com/tanner-caffrey/constellation-go/agg"
+)
+
+// records returns an iterator over records, optionally ending with err
+func records(err error, recs ...constellation.LinkRecord) iter.Seq2[constellation.LinkRecord, error] {
+	return func(yield func(constellation.LinkRecord, error) bool) {
+		for _, record := range recs {
+			if !yield(record, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(constellation.LinkRecord{}, err)
+		}
+	}
+}
+
+// TestRun tests composed aggregations in a single pass
+func TestRun(t *testing.T) {
+	seq := records(nil,
+		constellation.LinkRecord{DID: "did:plc:alice", IndexedAt: "2025-01-01T10:15:00Z"},
+		constellation.LinkRecord{DID: "did:plc:bob", IndexedAt: "2025-01-01T10:45:00Z"},
+		constellation.LinkRecord{DID: "did:plc:alice", IndexedAt: "2025-01-01T11:05:00Z"},
+		constellation.LinkRecord{DID: "did:plc:carol"},
+	)
+
+	total := agg.Count()
+	perDID := agg.GroupBy(agg.DID, agg.Count)
+	perHour := agg.TimeBucket(time.Hour, func() *agg.Groups[*agg.Counter] { return agg.GroupBy(agg.DID, agg.Count) })
+	if err := agg.Run(seq, total, perDID, perHour); err != nil {
+		t.Fatalf("Failed to aggregate: %v", err)
+	}
+
+	if total.N != 4 {
+		t.Errorf("Expected 4 records, got %d", total.N)
+	}
+	if perDID.Groups["did:plc:alice"].N != 2 || len(perDID.Keys()) != 3 {
+		t.Errorf("Unexpected per-DID counts: %v", perDID.Keys())
+	}
+
+	starts := perHour.Starts()
+	if len(starts) != 2 || perHour.Undated != 1 {
+		t.Fatalf("Expected 2 hourly buckets and 1 undated record, got %v and %d", starts, perHour.Undated)
+	}
+	if first := perHour.Buckets[starts[0]]; len(first.Groups) != 2 || !starts[0].Equal(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 2 DIDs in the 10:00 bucket, got %v at %v", first.Keys(), starts[0])
+	}
+}
+
+// TestRunError tests that iterator errors stop aggregation
+func TestRunError(t *testing.T) {
+	boom := errors.New("boom")
+	total := agg.Count()
+	if err := agg.Run(records(boom, constellation.LinkRecord{}), total); !errors.Is(err, boom) {
+		t.Errorf("Expected iterator error, got %v", err)
+	}
+}
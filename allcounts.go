@@ -0,0 +1,79 @@
+package constellation
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+// LinkCounts holds the links to a target grouped by collection, then by the path
+// within the linking records, e.g.
+//
+//	counts["app.bsky.feed.like"][".subject.uri"]
+type LinkCounts map[string]map[string]int
+
+// Get returns the count of links from collection at path
+func (lc LinkCounts) Get(collection, path string) int {
+	return lc[collection][path]
+}
+
+// CollectionTotal returns the count of links from collection across all paths
+func (lc LinkCounts) CollectionTotal(collection string) int {
+	total := 0
+	for _, count := range lc[collection] {
+		total += count
+	}
+	return total
+}
+
+// Total returns the count of links across all collections and paths
+func (lc LinkCounts) Total() int {
+	total := 0
+	for collection := range lc {
+		total += lc.CollectionTotal(collection)
+	}
+	return total
+}
+
+// Collections returns the linking collections in sorted order
+func (lc LinkCounts) Collections() []string {
+	collections := make([]string, 0, len(lc))
+	for collection := range lc {
+		collections = append(collections, collection)
+	}
+	sort.Strings(collections)
+	return collections
+}
+
+// allCountsResponse is the response from the grouped count endpoint
+type allCountsResponse struct {
+	Links LinkCounts `json:"links"`
+}
+
+// GetAllLinkCounts retrieves the count of links to target from every collection
+// and path in one request, so callers needn't know in advance what links to it
+// Endpoint: GET /links/all/count
+func (c *Client) GetAllLinkCounts(ctx context.Context, target string) (LinkCounts, error) {
+	if target == "" {
+		return nil, fmt.Errorf("target parameter is required")
+	}
+
+	params := url.Values{}
+	params.Add("target", queryTarget(target))
+
+	resp, err := c.makeRequestContext(ctx, "/links/all/count", params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var countsResp allCountsResponse
+	if err := c.decodeResponse(resp, &countsResp); err != nil {
+		return nil, fmt.Errorf("failed to decode grouped count response: %w", err)
+	}
+	if countsResp.Links == nil {
+		countsResp.Links = LinkCounts{}
+	}
+	return countsResp.Links, nil
+}
@@ -0,0 +1,74 @@
+package constellation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestGetAllLinkCounts tests decoding grouped counts and summing them
+func TestGetAllLinkCounts(t *testing.T) {
+	var gotTarget string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/links/all/count" {
+			http.NotFound(w, r)
+			return
+		}
+		gotTarget = r.URL.Query().Get("target")
+		w.Write([]byte(`{"links": {
+			"app.bsky.feed.like": {".subject.uri": 12},
+			"app.bsky.feed.post": {".reply.parent.uri": 3, ".embed.record.uri": 2}
+		}}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, constellation.DefaultTimeout)
+	counts, err := client.GetAllLinkCounts(context.Background(), "AT://did:plc:A/app.bsky.feed.post/1")
+	if err != nil {
+		t.Fatalf("Failed to get counts: %v", err)
+	}
+
+	if gotTarget != "at://did:plc:a/app.bsky.feed.post/1" {
+		t.Errorf("Expected a normalized target, got %q", gotTarget)
+	}
+	if got := counts.Get("app.bsky.feed.post", ".embed.record.uri"); got != 2 {
+		t.Errorf("Expected 2 quotes, got %d", got)
+	}
+	if got := counts.Get("app.bsky.feed.repost", ".subject.uri"); got != 0 {
+		t.Errorf("Expected 0 for a missing group, got %d", got)
+	}
+	if got := counts.CollectionTotal("app.bsky.feed.post"); got != 5 {
+		t.Errorf("Expected 5 posts, got %d", got)
+	}
+	if got := counts.Total(); got != 17 {
+		t.Errorf("Expected 17 links, got %d", got)
+	}
+	if got := counts.Collections(); !reflect.DeepEqual(got, []string{"app.bsky.feed.like", "app.bsky.feed.post"}) {
+		t.Errorf("Unexpected collections %v", got)
+	}
+}
+
+// TestGetAllLinkCountsEmpty tests that a target with no links yields an empty map
+func TestGetAllLinkCountsEmpty(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"links": {}}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, constellation.DefaultTimeout)
+	counts, err := client.GetAllLinkCounts(context.Background(), "did:plc:a")
+	if err != nil {
+		t.Fatalf("Failed to get counts: %v", err)
+	}
+	if counts == nil || counts.Total() != 0 {
+		t.Errorf("Expected empty counts, got %v", counts)
+	}
+
+	if _, err := client.GetAllLinkCounts(context.Background(), ""); err == nil {
+		t.Error("Expected an error for an empty target")
+	}
+}
@@ -0,0 +1,318 @@
+// Package analysis provides a go/analysis Analyzer that detects common misuse of
+// the constellation client in consumer code: discarding pagination cursors,
+// calling context-free methods where a context is available, and paginating
+// without limits.
+//
+// Checks use type information, so they only match calls into the client
+// package, whatever it's imported as. Run the Analyzer with go vet through the
+// constellation-vet command, or add it to a multichecker:
+//
+//	go install github.com/tanner-caffrey/constellation-go/analysis/cmd/constellation-vet@latest
+//	go vet -vettool=$(which constellation-vet) ./...
+//
+// This package is its own module so the client doesn't depend on x/tools.
+package analysis
+
+import (
+	"go/ast"
+	"go/types"
+
+	goanalysis "golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// ImportPath is the import path of the constellation client
+const ImportPath = "github.com/tanner-caffrey/constellation-go"
+
+// Check names, reported as the Category of each diagnostic
+const (
+	CheckCursor    = "cursor"    // A page is fetched but its Cursor is never read
+	CheckContext   = "context"   // A context-free method is called where a context is in scope
+	CheckUnbounded = "unbounded" // Pagination runs with PaginateOptions that set no limit
+)
+
+// Analyzer reports misuse of the constellation client
+var Analyzer = &goanalysis.Analyzer{
+	Name: "constellation",
+	Doc: `report misuse of the constellation client
+
+Reports pages fetched with GetLinks or GetDistinctDIDs whose Cursor is never
+read, context-free methods called where a context.Context is in scope, and
+pagination with PaginateOptions that set none of MaxRecords, MaxPages,
+MaxDuration, or Budget.`,
+	URL: "https://pkg.go.dev/github.com/tanner-caffrey/constellation-go/analysis",
+	Run: run,
+}
+
+// limitFields are the PaginateOptions fields that bound a pagination
+var limitFields = map[string]bool{
+	"MaxRecords":  true,
+	"MaxPages":    true,
+	"MaxDuration": true,
+	"Budget":      true,
+}
+
+func run(pass *goanalysis.Pass) (any, error) {
+	// The client itself is exempt, as are packages that can't call it
+	if pass.Pkg.Path() == ImportPath || !importsClient(pass.Pkg) {
+		return nil, nil
+	}
+
+	uses := collectUses(pass)
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				checkFunc(pass, uses, fn.Type, fn.Body, false)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// importsClient reports whether pkg imports the client package directly
+func importsClient(pkg *types.Package) bool {
+	for _, imp := range pkg.Imports() {
+		if imp.Path() == ImportPath {
+			return true
+		}
+	}
+	return false
+}
+
+// varUses summarizes how a package uses each local variable
+type varUses struct {
+	cursorRead map[*types.Var]bool // The variable's Cursor field is read
+	escapes    map[*types.Var]bool // The variable is used other than by selecting a field or method
+}
+
+// collectUses records, for every variable in the package, whether its Cursor is
+// read and whether it escapes. Assigning to a variable counts as neither.
+func collectUses(pass *goanalysis.Pass) varUses {
+	uses := varUses{cursorRead: map[*types.Var]bool{}, escapes: map[*types.Var]bool{}}
+	selected := map[*ast.Ident]bool{}
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				if id, ok := ast.Unparen(n.X).(*ast.Ident); ok {
+					selected[id] = true
+					if v, ok := pass.TypesInfo.Uses[id].(*types.Var); ok && n.Sel.Name == "Cursor" {
+						uses.cursorRead[v] = true
+					}
+				}
+			case *ast.AssignStmt:
+				for _, lhs := range n.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						selected[id] = true
+					}
+				}
+			}
+			return true
+		})
+	}
+	for id, obj := range pass.TypesInfo.Uses {
+		if v, ok := obj.(*types.Var); ok && !selected[id] {
+			uses.escapes[v] = true
+		}
+	}
+	return uses
+}
+
+// checkFunc checks one function body. Closures are checked as functions of
+// their own, with the contexts of enclosing functions in scope.
+func checkFunc(pass *goanalysis.Pass, uses varUses, typ *ast.FuncType, body *ast.BlockStmt, outerContext bool) {
+	hasContext := outerContext || hasContextParam(pass, typ)
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			checkFunc(pass, uses, n.Type, n.Body, hasContext)
+			return false
+		case *ast.ExprStmt:
+			if call, ok := ast.Unparen(n.X).(*ast.CallExpr); ok {
+				checkPage(pass, uses, call, nil)
+			}
+		case *ast.AssignStmt:
+			if len(n.Rhs) == 1 && len(n.Lhs) > 0 {
+				if call, ok := ast.Unparen(n.Rhs[0]).(*ast.CallExpr); ok {
+					checkPage(pass, uses, call, n.Lhs[0])
+				}
+			}
+		case *ast.ValueSpec:
+			if len(n.Values) == 1 && len(n.Names) > 0 {
+				if call, ok := ast.Unparen(n.Values[0]).(*ast.CallExpr); ok {
+					checkPage(pass, uses, call, n.Names[0])
+				}
+			}
+		case *ast.CallExpr:
+			checkCall(pass, n, hasContext)
+		}
+		return true
+	})
+}
+
+// clientFunc returns the client function or method a call invokes, if any
+func clientFunc(pass *goanalysis.Pass, call *ast.CallExpr) *types.Func {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != ImportPath {
+		return nil
+	}
+	return fn
+}
+
+// checkPage reports a call returning a single page whose result, assigned to
+// lhs or discarded if lhs is nil, never has its Cursor read
+func checkPage(pass *goanalysis.Pass, uses varUses, call *ast.CallExpr, lhs ast.Expr) {
+	fn := clientFunc(pass, call)
+	if fn == nil || !returnsPage(fn) {
+		return
+	}
+
+	if lhs != nil {
+		id, ok := lhs.(*ast.Ident)
+		if !ok {
+			return
+		}
+		if id.Name != "_" {
+			v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var)
+			if !ok || uses.cursorRead[v] || uses.escapes[v] {
+				return
+			}
+		}
+	}
+
+	pass.Report(goanalysis.Diagnostic{
+		Pos:      call.Pos(),
+		End:      call.End(),
+		Category: CheckCursor,
+		Message:  fn.Name() + " returns one page but its Cursor is never read; use GetAllLinks or a Paginator to fetch every page",
+	})
+}
+
+// returnsPage reports whether fn's first result points to a client struct with
+// a string Cursor field
+func returnsPage(fn *types.Func) bool {
+	results := fn.Type().(*types.Signature).Results()
+	if results.Len() == 0 {
+		return false
+	}
+	ptr, ok := results.At(0).Type().(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != ImportPath {
+		return false
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if f := st.Field(i); f.Name() == "Cursor" && types.Identical(f.Type(), types.Typ[types.String]) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCall reports context-free calls with a context in scope and unbounded
+// PaginateOptions arguments
+func checkCall(pass *goanalysis.Pass, call *ast.CallExpr, hasContext bool) {
+	fn := clientFunc(pass, call)
+	if fn == nil {
+		return
+	}
+
+	if hasContext && !takesContext(fn) {
+		if variant := contextVariant(fn); variant != "" {
+			pass.Report(goanalysis.Diagnostic{
+				Pos:      call.Pos(),
+				End:      call.End(),
+				Category: CheckContext,
+				Message:  fn.Name() + " ignores the context in scope; use " + variant,
+			})
+		}
+	}
+
+	for _, arg := range call.Args {
+		if lit, ok := ast.Unparen(arg).(*ast.CompositeLit); ok && isOptions(pass.TypesInfo.TypeOf(lit)) && !bounded(lit) {
+			pass.Report(goanalysis.Diagnostic{
+				Pos:      call.Pos(),
+				End:      call.End(),
+				Category: CheckUnbounded,
+				Message:  fn.Name() + " paginates without limits; set MaxRecords, MaxPages, MaxDuration, or Budget",
+			})
+		}
+	}
+}
+
+// contextVariant returns the name of the context-aware variant of fn, a method
+// or function named fn.Name()+"Context" taking a context first, if one exists
+func contextVariant(fn *types.Func) string {
+	name := fn.Name() + "Context"
+	var variant types.Object
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		variant, _, _ = types.LookupFieldOrMethod(recv.Type(), true, fn.Pkg(), name)
+	} else {
+		variant = fn.Pkg().Scope().Lookup(name)
+	}
+	if v, ok := variant.(*types.Func); ok && takesContext(v) {
+		return name
+	}
+	return ""
+}
+
+// takesContext reports whether fn's first parameter is a context.Context
+func takesContext(fn *types.Func) bool {
+	params := fn.Type().(*types.Signature).Params()
+	return params.Len() > 0 && isContext(params.At(0).Type())
+}
+
+// hasContextParam reports whether a function takes a context.Context
+func hasContextParam(pass *goanalysis.Pass, typ *ast.FuncType) bool {
+	if typ.Params == nil {
+		return false
+	}
+	for _, field := range typ.Params.List {
+		if isContext(pass.TypesInfo.TypeOf(field.Type)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isContext reports whether t is context.Context
+func isContext(t types.Type) bool {
+	return isNamed(t, "context", "Context")
+}
+
+// isOptions reports whether t is the client's PaginateOptions
+func isOptions(t types.Type) bool {
+	return isNamed(t, ImportPath, "PaginateOptions")
+}
+
+// isNamed reports whether t is the named type pkg.name
+func isNamed(t types.Type, pkg, name string) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkg && obj.Name() == name
+}
+
+// bounded reports whether a PaginateOptions literal sets a limit. Positional
+// literals are assumed to.
+func bounded(lit *ast.CompositeLit) bool {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok && limitFields[key.Name] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/tanner-caffrey/constellation-go/analysis"
+)
+
+// TestAnalyzer tests each check against a sample consumer package
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), analysis.Analyzer, "example")
+}
@@ -0,0 +1,17 @@
+// Command constellation-vet reports misuse of the constellation client, such as
+// discarded cursors, ignored contexts, and unbounded pagination. It runs as a
+// go vet tool, so it can run in CI beside the standard checks:
+//
+//	go install github.com/tanner-caffrey/constellation-go/analysis/cmd/constellation-vet@latest
+//	go vet -vettool=$(which constellation-vet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"github.com/tanner-caffrey/constellation-go/analysis"
+)
+
+func main() {
+	unitchecker.Main(analysis.Analyzer)
+}
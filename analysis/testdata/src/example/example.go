@@ -0,0 +1,67 @@
+package example
+
+import (
+	"context"
+
+	cg "github.com/tanner-caffrey/constellation-go"
+)
+
+func firstPage(ctx context.Context, client *cg.Client, params cg.LinksParams) {
+	client.GetLinks(params) // want `GetLinks ignores the context in scope; use GetLinksContext` `GetLinks returns one page but its Cursor is never read`
+}
+
+func discarded(client *cg.Client, params cg.LinksParams) int {
+	_, err := client.GetLinks(params)  // want `GetLinks returns one page but its Cursor is never read`
+	page, _ := client.GetLinks(params) // want `GetLinks returns one page but its Cursor is never read`
+	if err != nil {
+		return 0
+	}
+	return page.Total
+}
+
+func allPages(ctx context.Context, client *cg.Client, params cg.LinksParams) {
+	for {
+		page, _ := client.GetLinksContext(ctx, params)
+		if page.Cursor == "" {
+			return
+		}
+		params.Cursor = page.Cursor
+	}
+}
+
+// Pages handed to other code may have their Cursor read there
+func returned(ctx context.Context, client *cg.Client, params cg.LinksParams) (*cg.LinksResponse, error) {
+	page, err := client.GetLinksContext(ctx, params)
+	return page, err
+}
+
+// Counts have no cursor to follow
+func count(client *cg.Client, params cg.LinksParams) {
+	client.GetLinksCount(params)
+}
+
+// Closures see the enclosing function's context
+func closure(ctx context.Context, client *cg.Client, params cg.LinksParams) {
+	func() {
+		client.GetLinksCount(params) // want `GetLinksCount ignores the context in scope; use GetLinksCountContext`
+	}()
+}
+
+func everything(ctx context.Context, client *cg.Client, params cg.LinksParams) {
+	client.GetAllLinks(ctx, params, cg.PaginateOptions{})               // want `GetAllLinks paginates without limits`
+	client.GetAllLinks(ctx, params, cg.PaginateOptions{Parallelism: 4}) // want `GetAllLinks paginates without limits`
+	client.GetAllLinks(ctx, params, cg.PaginateOptions{MaxRecords: 1000})
+	client.LikersOf(ctx, "at://did:plc:x/app.bsky.feed.post/1", cg.PaginateOptions{}) // want `LikersOf paginates without limits`
+}
+
+// Unrelated types with the client's method names aren't reported
+type other struct{}
+
+func (other) GetLinks(params cg.LinksParams) (*cg.LinksResponse, error) { return nil, nil }
+
+func (other) GetAllLinks(ctx context.Context, params cg.LinksParams, opts cg.PaginateOptions) {}
+
+func lookalikes(ctx context.Context, o other, params cg.LinksParams) {
+	o.GetLinks(params)
+	o.GetAllLinks(ctx, params, cg.PaginateOptions{})
+}
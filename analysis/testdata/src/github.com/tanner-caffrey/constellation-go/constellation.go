@@ -0,0 +1,55 @@
+// Package constellation is a stub of the client's API for analyzer tests
+package constellation
+
+import (
+	"context"
+	"time"
+)
+
+type Client struct{}
+
+type LinksParams struct {
+	Target string
+	Cursor string
+}
+
+type LinksResponse struct {
+	Total  int
+	Cursor string
+}
+
+type CountResponse struct {
+	Total int
+}
+
+type Budget struct{}
+
+type PaginateOptions struct {
+	MaxRecords  int
+	MaxPages    int
+	MaxDuration time.Duration
+	Budget      *Budget
+	Parallelism int
+}
+
+type Record struct{}
+
+func (c *Client) GetLinks(params LinksParams) (*LinksResponse, error) { return nil, nil }
+
+func (c *Client) GetLinksContext(ctx context.Context, params LinksParams) (*LinksResponse, error) {
+	return nil, nil
+}
+
+func (c *Client) GetLinksCount(params LinksParams) (*CountResponse, error) { return nil, nil }
+
+func (c *Client) GetLinksCountContext(ctx context.Context, params LinksParams) (*CountResponse, error) {
+	return nil, nil
+}
+
+func (c *Client) GetAllLinks(ctx context.Context, params LinksParams, opts PaginateOptions) ([]Record, error) {
+	return nil, nil
+}
+
+func (c *Client) LikersOf(ctx context.Context, uri string, opts PaginateOptions) ([]string, error) {
+	return nil, nil
+}
@@ -0,0 +1,134 @@
+package constellation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditEntry is a single line of the audit log
+type AuditEntry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Endpoint   string    `json:"endpoint"`
+	ParamsHash string    `json:"params_hash"` // SHA-256 of the encoded query string
+	Status     int       `json:"status"`      // HTTP status code; zero if no response was received
+	Bytes      int64     `json:"bytes"`       // Response body bytes read by the client
+	DurationMS int64     `json:"duration_ms"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// AuditLog is an append-only log of every request made by a client, written as
+// JSON lines to a file or writer, or as one JSON value per entry to a KVStore.
+//
+// Entries record a hash of each query rather than the query itself, so the log
+// holds no DIDs and the retention helpers don't scrub it. The hash of a known
+// query can still be recomputed, so expire entries by their Timestamp within
+// your retention period.
+type AuditLog struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	store  KVStore
+	seq    uint64
+}
+
+// NewAuditLog creates an audit log writing JSON lines to w
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// OpenAuditLog opens (or creates) an append-only audit log file at path
+func OpenAuditLog(path string) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &AuditLog{w: file, closer: file}, nil
+}
+
+// NewKVAuditLog creates an audit log storing each entry in store under an
+// "audit/" key that sorts by time, so the log can share a backend with the
+// caches and checkpoints
+func NewKVAuditLog(store KVStore) *AuditLog {
+	return &AuditLog{store: store}
+}
+
+// auditKey returns the store key an entry is kept under by NewKVAuditLog. seq
+// distinguishes entries written in the same nanosecond.
+func auditKey(entry AuditEntry, seq uint64) string {
+	return fmt.Sprintf("audit/%020d-%010d", entry.Timestamp.UnixNano(), seq)
+}
+
+// Close closes the underlying file if the log was opened with OpenAuditLog
+func (a *AuditLog) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// write appends an entry to the log. Write failures are ignored so that auditing
+// never breaks the request path.
+func (a *AuditLog) write(entry AuditEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	if a.store == nil {
+		defer a.mu.Unlock()
+		a.w.Write(append(line, '\n'))
+		return
+	}
+	a.seq++
+	key := auditKey(entry, a.seq)
+	a.mu.Unlock()
+	a.store.Put(context.Background(), key, line)
+}
+
+// UseAuditLog records every request made by the client to log
+func (c *Client) UseAuditLog(log *AuditLog) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.auditLog = log
+	return c
+}
+
+// hashParams returns the hex SHA-256 of the encoded query parameters
+func hashParams(params url.Values) string {
+	sum := sha256.Sum256([]byte(params.Encode()))
+	return hex.EncodeToString(sum[:])
+}
+
+// auditedBody counts the bytes read from a response body and writes the audit
+// entry when the body is closed
+type auditedBody struct {
+	io.ReadCloser
+	log   *AuditLog
+	entry AuditEntry
+	start time.Time
+	once  sync.Once
+}
+
+func (b *auditedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.entry.Bytes += int64(n)
+	return n, err
+}
+
+func (b *auditedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.entry.DurationMS = time.Since(b.start).Milliseconds()
+		b.log.write(b.entry)
+	})
+	return err
+}
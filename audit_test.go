@@ -0,0 +1,116 @@
+package constellation_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestAuditLog tests that requests are written to the audit log
+func TestAuditLog(t *testing.T) {
+	body := `{"total": 5}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/links/distinct-dids" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.UseAuditLog(constellation.NewAuditLog(&buf))
+
+	params := constellation.LinksParams{Target: "did:plc:example"}
+	if _, err := client.GetLinksCount(params); err != nil {
+		t.Fatalf("Failed to get links count: %v", err)
+	}
+	client.GetDistinctDIDs(params)
+
+	var entries []constellation.AuditEntry
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var entry constellation.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to decode audit entry: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 audit entries, got %d", len(entries))
+	}
+	if entries[0].Endpoint != "/links/count" || entries[0].Status != 200 {
+		t.Errorf("Unexpected first entry: %+v", entries[0])
+	}
+	if entries[0].Bytes < int64(len(body)) {
+		t.Errorf("Expected at least %d bytes, got %d", len(body), entries[0].Bytes)
+	}
+	if entries[0].ParamsHash == "" {
+		t.Error("Expected params hash to be set")
+	}
+	if entries[1].Status != http.StatusNotFound {
+		t.Errorf("Expected 404 status in second entry, got %d", entries[1].Status)
+	}
+}
+
+// recordingKV is a MemoryKV that remembers the keys put into it
+type recordingKV struct {
+	*constellation.MemoryKV
+	mu   sync.Mutex
+	keys []string
+}
+
+func (r *recordingKV) Put(ctx context.Context, key string, value []byte) error {
+	r.mu.Lock()
+	r.keys = append(r.keys, key)
+	r.mu.Unlock()
+	return r.MemoryKV.Put(ctx, key, value)
+}
+
+// TestKVAuditLog tests that audit entries are stored under time-ordered keys
+func TestKVAuditLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total": 5}`))
+	}))
+	defer server.Close()
+
+	store := &recordingKV{MemoryKV: constellation.NewMemoryKV()}
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.UseAuditLog(constellation.NewKVAuditLog(store))
+
+	params := constellation.LinksParams{Target: "did:plc:example"}
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetLinksCount(params); err != nil {
+			t.Fatalf("Failed to get links count: %v", err)
+		}
+	}
+
+	if len(store.keys) != 2 || !sort.StringsAreSorted(store.keys) || store.keys[0] == store.keys[1] {
+		t.Fatalf("Expected 2 distinct, ordered keys, got %v", store.keys)
+	}
+	for _, key := range store.keys {
+		if !strings.HasPrefix(key, "audit/") {
+			t.Errorf("Expected an audit/ key, got %q", key)
+		}
+		data, err := store.Get(context.Background(), key)
+		if err != nil {
+			t.Fatalf("Failed to get %q: %v", key, err)
+		}
+		var entry constellation.AuditEntry
+		if err := json.Unmarshal(data, &entry); err != nil || entry.Endpoint != "/links/count" {
+			t.Errorf("Expected a /links/count entry, got %+v, %v", entry, err)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// BatchCountEndpoint is the server-side batch count endpoint. Batch counts use it
+// when the instance advertises it in its root response, sending each query as a
+// "q" parameter and reading {"counts": [{"total": n} or {"error": "..."}, ...]}
+// in query order.
+const BatchCountEndpoint = "/links/count/batch"
+
+// DefaultBatchSize is the number of queries sent per server-side batch request
+const DefaultBatchSize = 50
+
+// ErrCountOnly is returned for a CountOnly batch query that could only be
+// answered by fetching records, such as one with filters the instance can't
+// apply or on an instance without count endpoints
+var ErrCountOnly = errors.New("query can't be counted without fetching records")
+
+// BatchOptions controls a batch query
+type BatchOptions struct {
+	// Parallelism is the number of queries run concurrently. Defaults to
+	// DefaultParallelism; the client's concurrency limit still applies.
+	Parallelism int
+
+	// CountOnly answers every query from the count endpoints alone, never
+	// fetching records, for dashboards needing many counts with minimal
+	// payloads. Queries that would need records to count fail with
+	// ErrCountOnly, and BatchGetLinks returns only each query's Total.
+	CountOnly bool
+
+	// Budget, if set, caps the requests of the whole batch. Queries attempted
+	// after it's exceeded fail with a BudgetExceededError.
+	Budget *Budget
+}
+
+// BatchCountResult is the outcome of one query in a batch count
+type BatchCountResult struct {
+	Params LinksParams
+	Count  *CountResponse // Nil if the query failed
+	Err    error
+}
+
+// BatchLinksResult is the outcome of one query in a batch records fetch
+type BatchLinksResult struct {
+	Params LinksParams
+	Links  *LinksResponse // Nil if the query failed
+	Err    error
+}
+
+// BatchGetLinksCount counts links for every query concurrently, returning a
+// result per query in the same order. A failed query doesn't stop the others;
+// the returned error is non-nil if any failed and joins their errors, which are
+// also on their results. When the instance advertises BatchCountEndpoint, queries
+// are sent DefaultBatchSize at a time instead, falling back to one request per
+// query if a batch request fails.
+func (c *Client) BatchGetLinksCount(ctx context.Context, queries []LinksParams, opts BatchOptions) ([]BatchCountResult, error) {
+	ctx = WithBudget(ctx, opts.Budget)
+	results := make([]BatchCountResult, len(queries))
+	pending := make([]int, 0, len(queries))
+	for i, params := range queries {
+		results[i].Params = params
+		pending = append(pending, i)
+	}
+
+	if c.useBatchEndpoint() {
+		pending = c.batchCountServerSide(ctx, results)
+	}
+
+	parallelEach(len(pending), batchWorkers(opts), func(j int) {
+		result := &results[pending[j]]
+		if opts.CountOnly {
+			result.Count, result.Err = c.countOnly(ctx, result.Params)
+		} else {
+			result.Count, result.Err = c.GetLinksCountContext(ctx, result.Params)
+		}
+	})
+
+	errs := make([]error, len(results))
+	for i, result := range results {
+		errs[i] = result.Err
+	}
+	return results, c.batchError(queries, errs)
+}
+
+// BatchGetLinks fetches a page of linking records for every query concurrently,
+// as GetLinksContext would, returning a result per query in the same order.
+// Failures are reported as by BatchGetLinksCount. With opts.CountOnly, queries
+// are counted as by BatchGetLinksCount and each Links holds only the Total.
+func (c *Client) BatchGetLinks(ctx context.Context, queries []LinksParams, opts BatchOptions) ([]BatchLinksResult, error) {
+	ctx = WithBudget(ctx, opts.Budget)
+	results := make([]BatchLinksResult, len(queries))
+	if opts.CountOnly {
+		counts, err := c.BatchGetLinksCount(ctx, queries, opts)
+		for i, count := range counts {
+			results[i] = BatchLinksResult{Params: count.Params, Err: count.Err}
+			if count.Count != nil {
+				results[i].Links = &LinksResponse{Total: count.Count.Total}
+			}
+		}
+		return results, err
+	}
+
+	errs := make([]error, len(queries))
+	parallelEach(len(queries), batchWorkers(opts), func(i int) {
+		links, err := c.GetLinksContext(ctx, queries[i])
+		results[i] = BatchLinksResult{Params: queries[i], Links: links, Err: err}
+		errs[i] = err
+	})
+	return results, c.batchError(queries, errs)
+}
+
+// batchWorkers returns the parallelism for a batch
+func batchWorkers(opts BatchOptions) int {
+	if opts.Parallelism > 0 {
+		return opts.Parallelism
+	}
+	return DefaultParallelism
+}
+
+// batchError joins the errors of failed queries, naming their redacted targets,
+// or returns nil if none failed
+func (c *Client) batchError(queries []LinksParams, errs []error) error {
+	var failed []error
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", c.Redact(queries[i].Target), err))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d queries failed: %w", len(failed), len(queries), errors.Join(failed...))
+}
+
+// countOnly counts links for params from the count endpoint or count cache,
+// failing with ErrCountOnly rather than paging through records
+func (c *Client) countOnly(ctx context.Context, params LinksParams) (*CountResponse, error) {
+	if params.Target == "" {
+		return nil, fmt.Errorf("target parameter is required")
+	}
+	if c.needsClientFilter(params) {
+		return nil, fmt.Errorf("%w: the instance doesn't support the query's filters", ErrCountOnly)
+	}
+
+	var countResp *CountResponse
+	var err error
+	if cache := c.getCountCache(); cache != nil {
+		var total int
+		total, err = cachedCount(ctx, cache, CountKey(params, false))
+		countResp = &CountResponse{Total: total}
+	} else {
+		countResp, err = c.fetchLinksCount(ctx, params)
+	}
+	if countUnsupported(err) {
+		return nil, fmt.Errorf("%w: %v", ErrCountOnly, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return countResp, nil
+}
+
+// useBatchEndpoint reports whether batch counts should use BatchCountEndpoint.
+// The endpoint must be advertised explicitly, not merely assumed, and a count
+// cache takes precedence since it serves queries one at a time.
+func (c *Client) useBatchEndpoint() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.capabilities == nil || c.countCache != nil {
+		return false
+	}
+	for _, endpoint := range c.capabilities.Endpoints {
+		if endpoint == BatchCountEndpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// batchCountServerSide counts the queries the server can answer in batches,
+// filling in their results, and returns the indexes of the remaining queries
+func (c *Client) batchCountServerSide(ctx context.Context, results []BatchCountResult) []int {
+	var eligible, rest []int
+	for i, result := range results {
+		if result.Params.Target == "" || c.needsClientFilter(result.Params) {
+			rest = append(rest, i)
+		} else {
+			eligible = append(eligible, i)
+		}
+	}
+
+	for start := 0; start < len(eligible); start += DefaultBatchSize {
+		chunk := eligible[start:minOf(start+DefaultBatchSize, len(eligible))]
+		if err := c.fetchCountBatch(ctx, results, chunk); err != nil {
+			c.debug("batch count request failed; counting individually", "queries", len(chunk), "error", err)
+			rest = append(rest, chunk...)
+		}
+	}
+	return rest
+}
+
+// batchCountResponse is the response from BatchCountEndpoint
+type batchCountResponse struct {
+	Counts []struct {
+		Total *int   `json:"total"`
+		Error string `json:"error"`
+	} `json:"counts"`
+}
+
+// fetchCountBatch counts the queries at indexes with one request
+func (c *Client) fetchCountBatch(ctx context.Context, results []BatchCountResult, indexes []int) error {
+	params := url.Values{}
+	for _, i := range indexes {
+		query := c.applyCapabilities(results[i].Params).queryValues(false)
+		params.Add("q", query.Encode())
+	}
+
+	resp, err := c.makeRequestContext(ctx, BatchCountEndpoint, params)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var batchResp batchCountResponse
+	if err := c.decodeResponse(resp, &batchResp); err != nil {
+		return fmt.Errorf("failed to decode batch count response: %w", err)
+	}
+	if len(batchResp.Counts) != len(indexes) {
+		return fmt.Errorf("batch count response has %d counts for %d queries", len(batchResp.Counts), len(indexes))
+	}
+
+	for j, i := range indexes {
+		count := batchResp.Counts[j]
+		switch {
+		case count.Error != "":
+			results[i].Err = fmt.Errorf("batch count failed: %s", count.Error)
+		case count.Total == nil:
+			results[i].Err = fmt.Errorf("batch count response has no total")
+		default:
+			results[i].Count = &CountResponse{Total: *count.Total}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package constellation_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// newBatchServer serves counts and pages for targets "at://.../<n>" with n links,
+// failing targets ending in "/fail". If batch is set, it also serves the batch
+// count endpoint. It records the requests made to each path.
+func newBatchServer(t *testing.T, batch bool) (*httptest.Server, map[string]int) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	countFor := func(target string) (int, bool) {
+		n := target[strings.LastIndex(target, "/")+1:]
+		if n == "fail" {
+			return 0, false
+		}
+		return len(n), true
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/links/count":
+			total, ok := countFor(r.URL.Query().Get("target"))
+			if !ok {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]int{"total": total})
+		case "/links":
+			total, ok := countFor(r.URL.Query().Get("target"))
+			if !ok {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{"total": total, "linking_records": []map[string]string{{"did": "did:plc:a"}}})
+		case constellation.BatchCountEndpoint:
+			if !batch {
+				http.NotFound(w, r)
+				return
+			}
+			counts := []map[string]any{}
+			for _, q := range r.URL.Query()["q"] {
+				query, _ := url.ParseQuery(q)
+				if total, ok := countFor(query.Get("target")); ok {
+					counts = append(counts, map[string]any{"total": total})
+				} else {
+					counts = append(counts, map[string]any{"error": "boom"})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]any{"counts": counts})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+// batchQueries returns like queries for the given target suffixes
+func batchQueries(suffixes ...string) []constellation.LinksParams {
+	queries := make([]constellation.LinksParams, len(suffixes))
+	for i, suffix := range suffixes {
+		queries[i] = constellation.LinksParams{
+			Target:     "at://did:plc:a/app.bsky.feed.post/" + suffix,
+			Collection: constellation.CollectionLike,
+		}
+	}
+	return queries
+}
+
+// checkBatchCounts checks results for queries from batchQueries("a", "fail", "abc")
+func checkBatchCounts(t *testing.T, results []constellation.BatchCountResult, err error) {
+	t.Helper()
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 queries failed") {
+		t.Errorf("Expected a partial failure, got %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if results[0].Err != nil || results[0].Count.Total != 1 {
+		t.Errorf("Unexpected first result %+v", results[0])
+	}
+	if results[1].Err == nil || results[1].Count != nil {
+		t.Errorf("Expected the second query to fail, got %+v", results[1])
+	}
+	if results[2].Err != nil || results[2].Count.Total != 3 || !strings.HasSuffix(results[2].Params.Target, "/abc") {
+		t.Errorf("Unexpected third result %+v", results[2])
+	}
+}
+
+// TestBatchGetLinksCount tests concurrent counts with a partial failure
+func TestBatchGetLinksCount(t *testing.T) {
+	server, requests := newBatchServer(t, false)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+
+	results, err := client.BatchGetLinksCount(context.Background(), batchQueries("a", "fail", "abc"), constellation.BatchOptions{Parallelism: 2})
+	checkBatchCounts(t, results, err)
+	if requests["/links/count"] != 3 {
+		t.Errorf("Expected a request per query, got %d", requests["/links/count"])
+	}
+}
+
+// TestBatchGetLinksCountServerSide tests switching to an advertised batch endpoint
+func TestBatchGetLinksCountServerSide(t *testing.T) {
+	server, requests := newBatchServer(t, true)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.UseCapabilities(&constellation.Capabilities{
+		Endpoints: []string{"/links", "/links/count", constellation.BatchCountEndpoint},
+	})
+
+	results, err := client.BatchGetLinksCount(context.Background(), batchQueries("a", "fail", "abc"), constellation.BatchOptions{})
+	checkBatchCounts(t, results, err)
+	if requests[constellation.BatchCountEndpoint] != 1 || requests["/links/count"] != 0 {
+		t.Errorf("Expected one batch request, got %v", requests)
+	}
+}
+
+// TestBatchGetLinksCountBatchFallback tests counting individually when a batch
+// request fails
+func TestBatchGetLinksCountBatchFallback(t *testing.T) {
+	server, requests := newBatchServer(t, false)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.UseCapabilities(&constellation.Capabilities{
+		Endpoints: []string{"/links", "/links/count", constellation.BatchCountEndpoint},
+	})
+
+	results, err := client.BatchGetLinksCount(context.Background(), batchQueries("a", "fail", "abc"), constellation.BatchOptions{})
+	checkBatchCounts(t, results, err)
+	if requests[constellation.BatchCountEndpoint] != 1 || requests["/links/count"] != 3 {
+		t.Errorf("Expected a failed batch request and individual counts, got %v", requests)
+	}
+}
+
+// TestBatchGetLinks tests fetching pages for several queries
+func TestBatchGetLinks(t *testing.T) {
+	server, _ := newBatchServer(t, false)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+
+	results, err := client.BatchGetLinks(context.Background(), batchQueries("ab", "fail"), constellation.BatchOptions{})
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 queries failed") {
+		t.Errorf("Expected a partial failure, got %v", err)
+	}
+	if results[0].Err != nil || results[0].Links.Total != 2 || len(results[0].Links.LinkingRecords) != 1 {
+		t.Errorf("Unexpected first result %+v", results[0])
+	}
+	if results[1].Err == nil || results[1].Links != nil {
+		t.Errorf("Expected the second query to fail, got %+v", results[1])
+	}
+
+	if _, err := client.BatchGetLinks(context.Background(), batchQueries("ab"), constellation.BatchOptions{}); err != nil {
+		t.Errorf("Expected no error when every query succeeds, got %v", err)
+	}
+}
+
+// TestBatchCountOnly tests answering batch queries from count endpoints alone
+func TestBatchCountOnly(t *testing.T) {
+	server, requests := newBatchServer(t, true)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.UseCapabilities(&constellation.Capabilities{
+		Endpoints: []string{"/links", "/links/count", constellation.BatchCountEndpoint},
+	})
+
+	queries := batchQueries("ab", "abc")
+	filtered := queries[0]
+	filtered.FromDID = "did:plc:a"
+	queries = append(queries, filtered)
+
+	results, err := client.BatchGetLinks(context.Background(), queries, constellation.BatchOptions{CountOnly: true})
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 queries failed") {
+		t.Errorf("Expected the filtered query to fail, got %v", err)
+	}
+	if results[0].Err != nil || results[0].Links.Total != 2 || len(results[0].Links.LinkingRecords) != 0 {
+		t.Errorf("Unexpected first result %+v", results[0])
+	}
+	if results[1].Err != nil || results[1].Links.Total != 3 {
+		t.Errorf("Unexpected second result %+v", results[1])
+	}
+	if !errors.Is(results[2].Err, constellation.ErrCountOnly) || results[2].Links != nil {
+		t.Errorf("Expected ErrCountOnly for the emulated filter, got %+v", results[2])
+	}
+	if requests[constellation.BatchCountEndpoint] != 1 || requests["/links"] != 0 || requests["/links/count"] != 0 {
+		t.Errorf("Expected only a batch count request, got %v", requests)
+	}
+}
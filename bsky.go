@@ -0,0 +1,295 @@
+package constellation
+
+import (
+	"context"
+	"fmt"
+)
+
+// likesParams returns the query for likes of a post
+func likesParams(postURI string) LinksParams {
+	return LinksParams{Target: postURI, Collection: CollectionLike, Path: PathSubjectURI}
+}
+
+// repostsParams returns the query for reposts of a post
+func repostsParams(postURI string) LinksParams {
+	return LinksParams{Target: postURI, Collection: CollectionRepost, Path: PathSubjectURI}
+}
+
+// repliesParams returns the query for direct replies to a post
+func repliesParams(postURI string) LinksParams {
+	return LinksParams{Target: postURI, Collection: CollectionPost, Path: PathReplyParentURI}
+}
+
+// threadRepliesParams returns the query for every reply in a thread
+func threadRepliesParams(rootURI string) LinksParams {
+	return LinksParams{Target: rootURI, Collection: CollectionPost, Path: PathReplyRootURI}
+}
+
+// followersParams returns the query for follows of an account
+func followersParams(did string) LinksParams {
+	return LinksParams{Target: did, Collection: CollectionFollow, Path: PathSubject}
+}
+
+// blockersParams returns the query for blocks of an account
+func blockersParams(did string) LinksParams {
+	return LinksParams{Target: did, Collection: CollectionBlock, Path: PathSubject}
+}
+
+// listItemsParams returns the query for list items referencing an account
+func listItemsParams(did string) LinksParams {
+	return LinksParams{Target: did, Collection: CollectionListItem, Path: PathSubject}
+}
+
+// LikersOf returns the distinct DIDs that liked the post at postURI
+func (c *Client) LikersOf(ctx context.Context, postURI string, opts PaginateOptions) (DIDSet, error) {
+	return c.GetAllDistinctDIDs(ctx, likesParams(postURI), opts)
+}
+
+// LikeRecordsOf returns the like records for the post at postURI
+func (c *Client) LikeRecordsOf(ctx context.Context, postURI string, opts PaginateOptions) ([]LinkRecord, error) {
+	return c.GetAllLinks(ctx, likesParams(postURI), opts)
+}
+
+// RepostersOf returns the distinct DIDs that reposted the post at postURI
+func (c *Client) RepostersOf(ctx context.Context, postURI string, opts PaginateOptions) (DIDSet, error) {
+	return c.GetAllDistinctDIDs(ctx, repostsParams(postURI), opts)
+}
+
+// RepostRecordsOf returns the repost records for the post at postURI
+func (c *Client) RepostRecordsOf(ctx context.Context, postURI string, opts PaginateOptions) ([]LinkRecord, error) {
+	return c.GetAllLinks(ctx, repostsParams(postURI), opts)
+}
+
+// quotePaths are the paths at which quote posts embed the quoted post: plain
+// record embeds, and record-with-media embeds
+var quotePaths = []string{PathEmbedRecordURI, PathEmbedMediaURI}
+
+// QuotesOf returns the posts quoting the post at postURI, merging quotes found
+// under both embed paths and dropping duplicates. opts applies to each path.
+func (c *Client) QuotesOf(ctx context.Context, postURI string, opts PaginateOptions) ([]LinkRecord, error) {
+	return c.postsLinkingAt(ctx, postURI, quotePaths, opts)
+}
+
+// urlLinkPaths are the paths at which posts link to web pages: link cards, link
+// cards alongside a quote, and links in the text
+var urlLinkPaths = []string{PathEmbedExternalURI, PathEmbedMediaExternalURI, PathFacetLinkURI}
+
+// GetPostsLinkingToURL returns the posts linking to the web page at pageURL
+// through a link card or a link in their text, merged and deduplicated, to find
+// who shared an article. The URL must match exactly as posted. opts applies to
+// each path.
+func (c *Client) GetPostsLinkingToURL(ctx context.Context, pageURL string, opts PaginateOptions) ([]LinkRecord, error) {
+	return c.postsLinkingAt(ctx, pageURL, urlLinkPaths, opts)
+}
+
+// postsLinkingAt returns the posts linking to target at any of paths, dropping
+// posts found under more than one
+func (c *Client) postsLinkingAt(ctx context.Context, target string, paths []string, opts PaginateOptions) ([]LinkRecord, error) {
+	var posts []LinkRecord
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		records, err := c.GetAllLinks(ctx, LinksParams{Target: target, Collection: CollectionPost, Path: path}, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			uri := record.RecordURI()
+			if !seen[uri] {
+				seen[uri] = true
+				posts = append(posts, record)
+			}
+		}
+	}
+	return posts, nil
+}
+
+// QuoteCount returns the number of posts quoting the post at postURI under both
+// embed paths. A post has a single embed, so the paths don't overlap.
+func (c *Client) QuoteCount(ctx context.Context, postURI string) (int, error) {
+	total := 0
+	for _, path := range quotePaths {
+		count, err := c.GetLinksCountContext(ctx, LinksParams{Target: postURI, Collection: CollectionPost, Path: path})
+		if err != nil {
+			return 0, err
+		}
+		total += count.Total
+	}
+	return total, nil
+}
+
+// RepliesTo returns the direct replies to the post at postURI
+func (c *Client) RepliesTo(ctx context.Context, postURI string, opts PaginateOptions) ([]LinkRecord, error) {
+	return c.GetAllLinks(ctx, repliesParams(postURI), opts)
+}
+
+// ReplyCount returns the number of direct replies to the post at postURI
+func (c *Client) ReplyCount(ctx context.Context, postURI string) (int, error) {
+	count, err := c.GetLinksCountContext(ctx, repliesParams(postURI))
+	if err != nil {
+		return 0, err
+	}
+	return count.Total, nil
+}
+
+// ThreadRepliesOf returns every reply in the thread rooted at rootURI, at any depth
+func (c *Client) ThreadRepliesOf(ctx context.Context, rootURI string, opts PaginateOptions) ([]LinkRecord, error) {
+	return c.GetAllLinks(ctx, threadRepliesParams(rootURI), opts)
+}
+
+// ThreadReplyCount returns the number of replies in the thread rooted at rootURI,
+// at any depth
+func (c *Client) ThreadReplyCount(ctx context.Context, rootURI string) (int, error) {
+	count, err := c.GetLinksCountContext(ctx, threadRepliesParams(rootURI))
+	if err != nil {
+		return 0, err
+	}
+	return count.Total, nil
+}
+
+// FollowersOf returns the distinct DIDs following the account did
+func (c *Client) FollowersOf(ctx context.Context, did string, opts PaginateOptions) (DIDSet, error) {
+	return c.GetAllDistinctDIDs(ctx, followersParams(did), opts)
+}
+
+// FollowerCount returns the number of distinct accounts following did
+func (c *Client) FollowerCount(ctx context.Context, did string) (int, error) {
+	return c.GetDistinctDIDsCountContext(ctx, followersParams(did))
+}
+
+// BlockersOf returns the distinct DIDs blocking the account did
+func (c *Client) BlockersOf(ctx context.Context, did string, opts PaginateOptions) (DIDSet, error) {
+	return c.GetAllDistinctDIDs(ctx, blockersParams(did), opts)
+}
+
+// BlockerCount returns the number of distinct accounts blocking did
+func (c *Client) BlockerCount(ctx context.Context, did string) (int, error) {
+	return c.GetDistinctDIDsCountContext(ctx, blockersParams(did))
+}
+
+// ListsContaining returns the list item records that add the account did to a
+// list, whether curation or moderation
+func (c *Client) ListsContaining(ctx context.Context, did string, opts PaginateOptions) ([]LinkRecord, error) {
+	return c.GetAllLinks(ctx, listItemsParams(did), opts)
+}
+
+// ListURIsContaining returns the distinct URIs of the lists containing the account
+// did, read from each list item's .list field. Values the API doesn't include are
+// fetched from the list owner's PDS; items that can't be fetched are skipped.
+func (c *Client) ListURIsContaining(ctx context.Context, did string, opts PaginateOptions) ([]string, error) {
+	items, err := c.ListsContaining(ctx, did, opts)
+	if err != nil {
+		return nil, err
+	}
+	return c.listURIs(ctx, items)
+}
+
+// listURIs returns the distinct lists the list items belong to, as
+// ListURIsContaining
+func (c *Client) listURIs(ctx context.Context, items []LinkRecord) ([]string, error) {
+	var lists []string
+	seen := make(map[string]bool)
+	for _, item := range items {
+		value, err := c.recordValue(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+
+		if list, ok := value["list"].(string); ok && !seen[list] {
+			seen[list] = true
+			lists = append(lists, list)
+		}
+	}
+	return lists, nil
+}
+
+// recordValue returns the record's value, fetching it from the author's PDS when
+// the API didn't include it. It returns nil if the record can't be fetched, and
+// an error only if ctx is done.
+func (c *Client) recordValue(ctx context.Context, record LinkRecord) (map[string]any, error) {
+	if record.Value != nil {
+		return record.Value, nil
+	}
+	fetched, err := c.GetRecord(ctx, record.RecordURI())
+	if err != nil {
+		return nil, ctx.Err()
+	}
+	return fetched.Value, nil
+}
+
+// StarterPacksContaining returns the starter pack records that include the
+// account did. Starter packs don't reference members directly: a pack's .list
+// points to a list whose items reference the members, so this resolves the lists
+// containing did and then the starter packs built on each list. opts applies to
+// each query in the chain.
+func (c *Client) StarterPacksContaining(ctx context.Context, did string, opts PaginateOptions) ([]LinkRecord, error) {
+	lists, err := c.ListURIsContaining(ctx, did, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var packs []LinkRecord
+	for _, list := range lists {
+		records, err := c.GetAllLinks(ctx, LinksParams{Target: list, Collection: CollectionStarterPack, Path: PathList}, opts)
+		if err != nil {
+			return nil, err
+		}
+		packs = append(packs, records...)
+	}
+	return packs, nil
+}
+
+// feedGeneratorLikesParams returns the query for likes of a feed generator,
+// rejecting URIs of other record types
+func feedGeneratorLikesParams(feedURI string) (LinksParams, error) {
+	uri, err := ParseATURI(feedURI)
+	if err != nil {
+		return LinksParams{}, err
+	}
+	if uri.Collection != CollectionFeedGenerator {
+		return LinksParams{}, fmt.Errorf("not a feed generator URI: %s", feedURI)
+	}
+	return likesParams(feedURI), nil
+}
+
+// FeedGeneratorLikes returns the like records for the feed generator at feedURI
+func (c *Client) FeedGeneratorLikes(ctx context.Context, feedURI string, opts PaginateOptions) ([]LinkRecord, error) {
+	params, err := feedGeneratorLikesParams(feedURI)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetAllLinks(ctx, params, opts)
+}
+
+// FeedGeneratorLikeCount returns the number of likes of the feed generator at feedURI
+func (c *Client) FeedGeneratorLikeCount(ctx context.Context, feedURI string) (int, error) {
+	params, err := feedGeneratorLikesParams(feedURI)
+	if err != nil {
+		return 0, err
+	}
+	count, err := c.GetLinksCountContext(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+	return count.Total, nil
+}
+
+// LabelerServiceURI returns the URI of the labeler service record for did. Each
+// labeler has a single service record with the key "self".
+func LabelerServiceURI(did string) string {
+	return ATURI{DID: did, Collection: CollectionLabelerService, RKey: "self"}.String()
+}
+
+// LabelerLikes returns the like records for the labeler service of did, a proxy
+// for subscriber interest
+func (c *Client) LabelerLikes(ctx context.Context, did string, opts PaginateOptions) ([]LinkRecord, error) {
+	return c.GetAllLinks(ctx, likesParams(LabelerServiceURI(did)), opts)
+}
+
+// LabelerLikeCount returns the number of likes of the labeler service of did
+func (c *Client) LabelerLikeCount(ctx context.Context, did string) (int, error) {
+	count, err := c.GetLinksCountContext(ctx, likesParams(LabelerServiceURI(did)))
+	if err != nil {
+		return 0, err
+	}
+	return count.Total, nil
+}
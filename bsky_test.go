@@ -0,0 +1,308 @@
+package constellation_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// newShortcutServer returns a server checking the collection and path of each
+// request and answering with one record or DID
+func newShortcutServer(t *testing.T, collection, path string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("collection") != collection || query.Get("path") != path {
+			t.Errorf("Expected %s %s, got %s", collection, path, r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/links/count", "/links/count/distinct-dids":
+			w.Write([]byte(`{"total": 1}`))
+		case "/links/distinct-dids":
+			w.Write([]byte(`{"total": 1, "linking_dids": ["did:plc:alice"]}`))
+		default:
+			w.Write([]byte(`{"total": 1, "linking_records": [{"did": "did:plc:alice", "rkey": "1"}]}`))
+		}
+	}))
+}
+
+// TestLikersOf tests the likes shortcuts
+func TestLikersOf(t *testing.T) {
+	server := newShortcutServer(t, "app.bsky.feed.like", ".subject.uri")
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	ctx := context.Background()
+	post := "at://did:plc:creator/app.bsky.feed.post/1"
+
+	likers, err := client.LikersOf(ctx, post, constellation.PaginateOptions{})
+	if err != nil || !likers.Contains("did:plc:alice") {
+		t.Errorf("Expected alice among likers, got %v, %v", likers, err)
+	}
+	records, err := client.LikeRecordsOf(ctx, post, constellation.PaginateOptions{})
+	if err != nil || len(records) != 1 {
+		t.Errorf("Expected one like record, got %v, %v", records, err)
+	}
+}
+
+// TestRepostersOf tests the reposts shortcuts
+func TestRepostersOf(t *testing.T) {
+	server := newShortcutServer(t, "app.bsky.feed.repost", ".subject.uri")
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	ctx := context.Background()
+	post := "at://did:plc:creator/app.bsky.feed.post/1"
+
+	reposters, err := client.RepostersOf(ctx, post, constellation.PaginateOptions{})
+	if err != nil || !reposters.Contains("did:plc:alice") {
+		t.Errorf("Expected alice among reposters, got %v, %v", reposters, err)
+	}
+	records, err := client.RepostRecordsOf(ctx, post, constellation.PaginateOptions{})
+	if err != nil || len(records) != 1 {
+		t.Errorf("Expected one repost record, got %v, %v", records, err)
+	}
+}
+
+// TestQuotesOf tests merging quotes from both embed paths
+func TestQuotesOf(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("path") == ".embed.record.uri" {
+			w.Write([]byte(`{"total": 2, "linking_records": [{"did": "did:plc:alice", "collection": "app.bsky.feed.post", "rkey": "1"}, {"did": "did:plc:bob", "collection": "app.bsky.feed.post", "rkey": "2"}]}`))
+			return
+		}
+		w.Write([]byte(`{"total": 2, "linking_records": [{"did": "did:plc:bob", "collection": "app.bsky.feed.post", "rkey": "2"}, {"did": "did:plc:carol", "collection": "app.bsky.feed.post", "rkey": "3"}]}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	quotes, err := client.QuotesOf(context.Background(), "at://did:plc:creator/app.bsky.feed.post/1", constellation.PaginateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get quotes: %v", err)
+	}
+	if len(quotes) != 3 {
+		t.Errorf("Expected 3 distinct quotes, got %+v", quotes)
+	}
+}
+
+// TestGetPostsLinkingToURL tests merging link card and text link shares of a URL
+func TestGetPostsLinkingToURL(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Query().Get("path"))
+		switch r.URL.Query().Get("path") {
+		case constellation.PathEmbedExternalURI:
+			w.Write([]byte(`{"linking_records": [{"did": "did:plc:alice", "collection": "app.bsky.feed.post", "rkey": "1"}]}`))
+		case constellation.PathFacetLinkURI:
+			w.Write([]byte(`{"linking_records": [{"did": "did:plc:alice", "collection": "app.bsky.feed.post", "rkey": "1"}, {"did": "did:plc:bob", "collection": "app.bsky.feed.post", "rkey": "2"}]}`))
+		default:
+			w.Write([]byte(`{"linking_records": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	posts, err := client.GetPostsLinkingToURL(context.Background(), "https://example.com/article", constellation.PaginateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get posts: %v", err)
+	}
+	if len(posts) != 2 {
+		t.Errorf("Expected 2 distinct posts, got %+v", posts)
+	}
+	if len(paths) != 3 {
+		t.Errorf("Expected link cards, quoted link cards, and text links to be queried, got %v", paths)
+	}
+}
+
+// TestRepliesTo tests the direct and thread reply shortcuts
+func TestRepliesTo(t *testing.T) {
+	ctx := context.Background()
+	post := "at://did:plc:creator/app.bsky.feed.post/1"
+
+	for _, tc := range []struct {
+		path    string
+		records func(*constellation.Client) ([]constellation.LinkRecord, error)
+		count   func(*constellation.Client) (int, error)
+	}{
+		{
+			".reply.parent.uri",
+			func(c *constellation.Client) ([]constellation.LinkRecord, error) {
+				return c.RepliesTo(ctx, post, constellation.PaginateOptions{})
+			},
+			func(c *constellation.Client) (int, error) { return c.ReplyCount(ctx, post) },
+		},
+		{
+			".reply.root.uri",
+			func(c *constellation.Client) ([]constellation.LinkRecord, error) {
+				return c.ThreadRepliesOf(ctx, post, constellation.PaginateOptions{})
+			},
+			func(c *constellation.Client) (int, error) { return c.ThreadReplyCount(ctx, post) },
+		},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			server := newShortcutServer(t, "app.bsky.feed.post", tc.path)
+			defer server.Close()
+			client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+
+			if records, err := tc.records(client); err != nil || len(records) != 1 {
+				t.Errorf("Expected one reply, got %v, %v", records, err)
+			}
+			if count, err := tc.count(client); err != nil || count != 1 {
+				t.Errorf("Expected count of 1, got %d, %v", count, err)
+			}
+		})
+	}
+}
+
+// TestFollowersOf tests the followers shortcuts
+func TestFollowersOf(t *testing.T) {
+	server := newShortcutServer(t, "app.bsky.graph.follow", ".subject")
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	ctx := context.Background()
+
+	followers, err := client.FollowersOf(ctx, "did:plc:creator", constellation.PaginateOptions{})
+	if err != nil || !followers.Contains("did:plc:alice") {
+		t.Errorf("Expected alice among followers, got %v, %v", followers, err)
+	}
+	if count, err := client.FollowerCount(ctx, "did:plc:creator"); err != nil || count != 1 {
+		t.Errorf("Expected follower count of 1, got %d, %v", count, err)
+	}
+}
+
+// TestBlockersOf tests the blockers shortcuts
+func TestBlockersOf(t *testing.T) {
+	server := newShortcutServer(t, "app.bsky.graph.block", ".subject")
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	ctx := context.Background()
+
+	blockers, err := client.BlockersOf(ctx, "did:plc:creator", constellation.PaginateOptions{})
+	if err != nil || !blockers.Contains("did:plc:alice") {
+		t.Errorf("Expected alice among blockers, got %v, %v", blockers, err)
+	}
+	if count, err := client.BlockerCount(ctx, "did:plc:creator"); err != nil || count != 1 {
+		t.Errorf("Expected blocker count of 1, got %d, %v", count, err)
+	}
+}
+
+// TestListsContaining tests list membership lookups with value hydration
+func TestListsContaining(t *testing.T) {
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/links":
+			if r.URL.Query().Get("collection") != "app.bsky.graph.listitem" || r.URL.Query().Get("path") != ".subject" {
+				t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"linking_records": [
+				{"did": "did:plc:owner", "collection": "app.bsky.graph.listitem", "rkey": "1", "value": {"list": "at://did:plc:owner/app.bsky.graph.list/mods"}},
+				{"did": "did:plc:owner", "collection": "app.bsky.graph.listitem", "rkey": "2"}
+			]}`))
+		case "/did:plc:owner":
+			fmt.Fprintf(w, `{"id": "did:plc:owner", "service": [{"id": "#atproto_pds", "serviceEndpoint": %q}]}`, serverURL)
+		case "/xrpc/com.atproto.repo.getRecord":
+			w.Write([]byte(`{"value": {"list": "at://did:plc:owner/app.bsky.graph.list/friends"}}`))
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.PLCDirectory = server.URL
+	ctx := context.Background()
+
+	items, err := client.ListsContaining(ctx, "did:plc:member", constellation.PaginateOptions{})
+	if err != nil || len(items) != 2 {
+		t.Fatalf("Expected 2 list items, got %v, %v", items, err)
+	}
+
+	lists, err := client.ListURIsContaining(ctx, "did:plc:member", constellation.PaginateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get list URIs: %v", err)
+	}
+	if len(lists) != 2 || lists[0] != "at://did:plc:owner/app.bsky.graph.list/mods" || lists[1] != "at://did:plc:owner/app.bsky.graph.list/friends" {
+		t.Errorf("Unexpected list URIs: %v", lists)
+	}
+}
+
+// TestStarterPacksContaining tests resolving starter packs through list items
+func TestStarterPacksContaining(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch query.Get("collection") {
+		case "app.bsky.graph.listitem":
+			w.Write([]byte(`{"linking_records": [
+				{"did": "did:plc:owner", "rkey": "1", "value": {"list": "at://did:plc:owner/app.bsky.graph.list/pack"}},
+				{"did": "did:plc:owner", "rkey": "2", "value": {"list": "at://did:plc:owner/app.bsky.graph.list/plain"}}
+			]}`))
+		case "app.bsky.graph.starterpack":
+			if query.Get("path") != ".list" {
+				t.Errorf("Unexpected starter pack path: %s", query.Get("path"))
+			}
+			if query.Get("target") == "at://did:plc:owner/app.bsky.graph.list/pack" {
+				w.Write([]byte(`{"linking_records": [{"did": "did:plc:owner", "collection": "app.bsky.graph.starterpack", "rkey": "sp"}]}`))
+				return
+			}
+			w.Write([]byte(`{"linking_records": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	packs, err := client.StarterPacksContaining(context.Background(), "did:plc:member", constellation.PaginateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get starter packs: %v", err)
+	}
+	if len(packs) != 1 || packs[0].RKey != "sp" {
+		t.Errorf("Expected one starter pack, got %+v", packs)
+	}
+}
+
+// TestFeedGeneratorLikes tests the feed generator like shortcuts
+func TestFeedGeneratorLikes(t *testing.T) {
+	server := newShortcutServer(t, "app.bsky.feed.like", ".subject.uri")
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	ctx := context.Background()
+	feed := "at://did:plc:creator/app.bsky.feed.generator/cats"
+
+	likes, err := client.FeedGeneratorLikes(ctx, feed, constellation.PaginateOptions{})
+	if err != nil || len(likes) != 1 {
+		t.Errorf("Expected one feed like, got %v, %v", likes, err)
+	}
+	if count, err := client.FeedGeneratorLikeCount(ctx, feed); err != nil || count != 1 {
+		t.Errorf("Expected feed like count of 1, got %d, %v", count, err)
+	}
+	if _, err := client.FeedGeneratorLikeCount(ctx, "at://did:plc:creator/app.bsky.feed.post/1"); err == nil {
+		t.Error("Expected an error for a non-generator URI")
+	}
+}
+
+// TestLabelerLikes tests the labeler service like shortcuts
+func TestLabelerLikes(t *testing.T) {
+	server := newShortcutServer(t, "app.bsky.feed.like", ".subject.uri")
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	ctx := context.Background()
+
+	if uri := constellation.LabelerServiceURI("did:plc:labeler"); uri != "at://did:plc:labeler/app.bsky.labeler.service/self" {
+		t.Errorf("Unexpected labeler service URI: %s", uri)
+	}
+	likes, err := client.LabelerLikes(ctx, "did:plc:labeler", constellation.PaginateOptions{})
+	if err != nil || len(likes) != 1 {
+		t.Errorf("Expected one labeler like, got %v, %v", likes, err)
+	}
+	if count, err := client.LabelerLikeCount(ctx, "did:plc:labeler"); err != nil || count != 1 {
+		t.Errorf("Expected labeler like count of 1, got %d, %v", count, err)
+	}
+}
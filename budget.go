@@ -0,0 +1,160 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is matched by errors.Is for every BudgetExceededError
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// Budget caps the resources an operation may consume. Unlike the limits in
+// PaginateOptions, exceeding a budget is reported as an error, returned alongside
+// the partial results collected so far. A Budget may be shared by several
+// operations to cap their combined usage. Zero values mean no limit.
+//
+// Operations with options take a Budget there; any other operation, including
+// fan-outs like PostEngagement and Leaderboard, can be budgeted with WithBudget.
+// Every request to the instance is charged, however many run concurrently.
+type Budget struct {
+	MaxRequests int
+	MaxRecords  int
+	MaxDuration time.Duration
+
+	mu       sync.Mutex
+	started  time.Time
+	requests int
+	records  int
+}
+
+// BudgetExceededError reports which budget limit stopped an operation
+type BudgetExceededError struct {
+	Limit    string // "requests", "records", or "duration"
+	Requests int    // Requests made before the budget was exceeded
+	Records  int    // Records processed before the budget was exceeded
+	Elapsed  time.Duration
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("budget exceeded: %s limit reached after %d requests, %d records, %s",
+		e.Limit, e.Requests, e.Records, e.Elapsed.Round(time.Millisecond))
+}
+
+// Is makes errors.Is(err, ErrBudgetExceeded) match
+func (e *BudgetExceededError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+// exceeded builds the error for limit. Callers hold mu.
+func (b *Budget) exceeded(limit string) error {
+	return &BudgetExceededError{
+		Limit:    limit,
+		Requests: b.requests,
+		Records:  b.records,
+		Elapsed:  time.Since(b.started),
+	}
+}
+
+// checkTime starts the clock on first use and checks the duration limit. Callers hold mu.
+func (b *Budget) checkTime() error {
+	if b.started.IsZero() {
+		b.started = time.Now()
+	}
+	if b.MaxDuration > 0 && time.Since(b.started) >= b.MaxDuration {
+		return b.exceeded("duration")
+	}
+	return nil
+}
+
+// budgetsKey is the context key for the budgets requests are charged to
+type budgetsKey struct{}
+
+// WithBudget returns a context under which every request the client makes is
+// charged to budget, as are the records paginating operations process. Requests
+// fail with a BudgetExceededError once it's exhausted. Budgets nest: ctx's
+// existing budgets are still charged. A nil budget returns ctx unchanged.
+func WithBudget(ctx context.Context, budget *Budget) context.Context {
+	if budget == nil {
+		return ctx
+	}
+	budgets := budgetsFrom(ctx)
+	for _, b := range budgets {
+		if b == budget {
+			return ctx
+		}
+	}
+	return context.WithValue(ctx, budgetsKey{}, append(budgets[:len(budgets):len(budgets)], budget))
+}
+
+// budgetsFrom returns the budgets ctx carries
+func budgetsFrom(ctx context.Context) []*Budget {
+	budgets, _ := ctx.Value(budgetsKey{}).([]*Budget)
+	return budgets
+}
+
+// spendRequest charges a request about to be made to every budget ctx carries
+func spendRequest(ctx context.Context) error {
+	for _, budget := range budgetsFrom(ctx) {
+		if err := budget.spendRequest(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spendRecord charges a record about to be processed to every budget ctx carries
+func spendRecord(ctx context.Context) error {
+	for _, budget := range budgetsFrom(ctx) {
+		if err := budget.spendRecord(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spendRequest accounts for a request about to be made
+func (b *Budget) spendRequest() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkTime(); err != nil {
+		return err
+	}
+	if b.MaxRequests > 0 && b.requests >= b.MaxRequests {
+		return b.exceeded("requests")
+	}
+	b.requests++
+	return nil
+}
+
+// spendRecord accounts for a record about to be processed
+func (b *Budget) spendRecord() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkTime(); err != nil {
+		return err
+	}
+	if b.MaxRecords > 0 && b.records >= b.MaxRecords {
+		return b.exceeded("records")
+	}
+	b.records++
+	return nil
+}
+
+// Usage returns the requests and records consumed so far
+func (b *Budget) Usage() (requests, records int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.requests, b.records
+}
@@ -0,0 +1,78 @@
+package constellation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestBudget tests that exceeding a budget returns partial results and a typed error
+func TestBudget(t *testing.T) {
+	server := newPagedServer(t, 50)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	budget := &constellation.Budget{MaxRequests: 2}
+	records, err := client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{Budget: budget})
+	if !errors.Is(err, constellation.ErrBudgetExceeded) {
+		t.Fatalf("Expected ErrBudgetExceeded, got: %v", err)
+	}
+
+	var budgetErr *constellation.BudgetExceededError
+	if !errors.As(err, &budgetErr) || budgetErr.Limit != "requests" {
+		t.Errorf("Expected requests limit to be reported, got: %v", err)
+	}
+	if len(records) != 20 {
+		t.Errorf("Expected 20 partial records, got %d", len(records))
+	}
+
+	records, err = client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{
+		Budget: &constellation.Budget{MaxRecords: 15},
+	})
+	if !errors.Is(err, constellation.ErrBudgetExceeded) {
+		t.Fatalf("Expected ErrBudgetExceeded, got: %v", err)
+	}
+	if len(records) != 15 {
+		t.Errorf("Expected 15 partial records, got %d", len(records))
+	}
+}
+
+// TestWithBudgetFanOut tests that a context budget caps the concurrent requests
+// of fan-out operations and batches
+func TestWithBudgetFanOut(t *testing.T) {
+	server, requests := newBatchServer(t, false)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+
+	budget := &constellation.Budget{MaxRequests: 2}
+	_, err := client.PostEngagement(constellation.WithBudget(context.Background(), budget), "at://did:plc:a/app.bsky.feed.post/123")
+	if !errors.Is(err, constellation.ErrBudgetExceeded) {
+		t.Fatalf("Expected ErrBudgetExceeded, got: %v", err)
+	}
+	if requests["/links/count"] != 2 {
+		t.Errorf("Expected 2 requests within the budget, got %d", requests["/links/count"])
+	}
+
+	queries := []constellation.LinksParams{
+		{Target: "at://x/1", Collection: "app.bsky.feed.like", Path: ".subject.uri"},
+		{Target: "at://x/22", Collection: "app.bsky.feed.like", Path: ".subject.uri"},
+		{Target: "at://x/333", Collection: "app.bsky.feed.like", Path: ".subject.uri"},
+	}
+	results, err := client.BatchGetLinksCount(context.Background(), queries, constellation.BatchOptions{
+		Budget: &constellation.Budget{MaxRequests: 1},
+	})
+	if err == nil {
+		t.Fatal("Expected the batch to report failed queries")
+	}
+	exceeded := 0
+	for _, result := range results {
+		if errors.Is(result.Err, constellation.ErrBudgetExceeded) {
+			exceeded++
+		}
+	}
+	if exceeded != 2 {
+		t.Errorf("Expected 2 queries over budget, got %d", exceeded)
+	}
+}
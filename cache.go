@@ -0,0 +1,240 @@
+package constellation
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Getter loads the value for a key. Its shape matches a groupcache Group's Get, so a
+// distributed groupcache can serve as the client's count cache:
+//
+//	group := groupcache.NewGroup("counts", 64<<20, groupcache.GetterFunc(
+//		func(ctx context.Context, key string, dest groupcache.Sink) error {
+//			data, err := client.CountGetter().Get(ctx, key)
+//			if err != nil {
+//				return err
+//			}
+//			return dest.SetBytes(data)
+//		}))
+//	client.UseCountCache(constellation.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+//		var data []byte
+//		err := group.Get(ctx, key, groupcache.AllocatingByteSliceSink(&data))
+//		return data, err
+//	}))
+type Getter interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// GetterFunc adapts a function to the Getter interface
+type GetterFunc func(ctx context.Context, key string) ([]byte, error)
+
+// Get calls f(ctx, key)
+func (f GetterFunc) Get(ctx context.Context, key string) ([]byte, error) {
+	return f(ctx, key)
+}
+
+// UseCountCache routes GetLinksCount and GetDistinctDIDsCount through a read-through
+// cache. On a miss the cache should load values with the getter from CountGetter.
+func (c *Client) UseCountCache(cache Getter) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.countCache = cache
+	return c
+}
+
+// getCountCache returns the count cache, or nil if none is set
+func (c *Client) getCountCache() Getter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.countCache
+}
+
+// CountGetter returns a Getter that loads counts from the API for keys built by
+// CountKey, bypassing the count cache. Use it as the loader behind a cache.
+func (c *Client) CountGetter() Getter {
+	return GetterFunc(c.loadCount)
+}
+
+// CountKey builds the cache key for a count query. Set distinct to key distinct DID
+// counts rather than link counts.
+func CountKey(params LinksParams, distinct bool) string {
+	kind := "links"
+	if distinct {
+		kind = "distinct-dids"
+	}
+	return kind + "?" + params.queryValues(false).Encode()
+}
+
+// loadCount fetches the count described by key from the API
+func (c *Client) loadCount(ctx context.Context, key string) ([]byte, error) {
+	kind, query, ok := strings.Cut(key, "?")
+	if !ok {
+		return nil, fmt.Errorf("invalid count key: %s", key)
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid count key: %w", err)
+	}
+
+	params := LinksParams{
+		Target:     values.Get("target"),
+		Collection: values.Get("collection"),
+		Path:       values.Get("path"),
+	}
+	values.Del("target")
+	values.Del("collection")
+	values.Del("path")
+	if len(values) > 0 {
+		params.Extra = values
+	}
+
+	var total int
+	switch kind {
+	case "links":
+		count, err := c.fetchLinksCount(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		total = count.Total
+	case "distinct-dids":
+		total, err = c.fetchDistinctDIDsCount(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid count key: %s", key)
+	}
+
+	return []byte(strconv.Itoa(total)), nil
+}
+
+// cachedCount reads a count through cache
+func cachedCount(ctx context.Context, cache Getter, key string) (int, error) {
+	data, err := cache.Get(ctx, key)
+	if err != nil {
+		return -1, err
+	}
+
+	total, err := strconv.Atoi(string(data))
+	if err != nil {
+		return -1, fmt.Errorf("failed to decode cached count: %w", err)
+	}
+	return total, nil
+}
+
+// DefaultMemoryCacheEntries is the number of entries NewMemoryCache keeps before
+// evicting the oldest
+const DefaultMemoryCacheEntries = 10000
+
+// memoryEntry is a cached value with its expiry time
+type memoryEntry struct {
+	key     string
+	data    []byte
+	expires time.Time
+}
+
+// memoryCall is a load in progress, shared by concurrent misses for its key
+type memoryCall struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// MemoryCache is an in-process read-through cache with a fixed TTL, suitable for
+// single-node deployments. Concurrent misses for a key share one load from
+// Source, expired entries are evicted as new ones are added, and at most
+// MaxEntries are kept, evicting the oldest first.
+type MemoryCache struct {
+	TTL        time.Duration
+	Source     Getter // Loads values on a miss
+	MaxEntries int    // Entries kept before evicting the oldest; zero means no limit
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // Elements of order, holding *memoryEntry
+	order   list.List                // Oldest first, which with a fixed TTL is also soonest to expire
+	loading map[string]*memoryCall
+}
+
+// NewMemoryCache creates an in-memory read-through cache in front of source,
+// holding up to DefaultMemoryCacheEntries entries
+func NewMemoryCache(ttl time.Duration, source Getter) *MemoryCache {
+	return &MemoryCache{
+		TTL:        ttl,
+		Source:     source,
+		MaxEntries: DefaultMemoryCacheEntries,
+	}
+}
+
+// Get returns the cached value for key, loading it from Source on a miss
+func (m *MemoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	if elem, ok := m.entries[key]; ok {
+		if entry := elem.Value.(*memoryEntry); time.Now().Before(entry.expires) {
+			m.mu.Unlock()
+			return entry.data, nil
+		}
+	}
+	if call, ok := m.loading[key]; ok {
+		m.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.data, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &memoryCall{done: make(chan struct{})}
+	if m.loading == nil {
+		m.loading = make(map[string]*memoryCall)
+	}
+	m.loading[key] = call
+	m.mu.Unlock()
+
+	call.data, call.err = m.Source.Get(ctx, key)
+
+	m.mu.Lock()
+	delete(m.loading, key)
+	if call.err == nil {
+		m.store(key, call.data)
+	}
+	m.mu.Unlock()
+	close(call.done)
+
+	return call.data, call.err
+}
+
+// Len returns the number of cached entries, including expired ones not yet evicted
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// store caches data for key, then evicts expired entries and any beyond
+// MaxEntries. The caller must hold m.mu.
+func (m *MemoryCache) store(key string, data []byte) {
+	if m.entries == nil {
+		m.entries = make(map[string]*list.Element)
+	}
+	if elem, ok := m.entries[key]; ok {
+		m.order.Remove(elem)
+	}
+	now := time.Now()
+	m.entries[key] = m.order.PushBack(&memoryEntry{key: key, data: data, expires: now.Add(m.TTL)})
+
+	for front := m.order.Front(); front != nil; front = m.order.Front() {
+		entry := front.Value.(*memoryEntry)
+		if now.Before(entry.expires) && (m.MaxEntries <= 0 || m.order.Len() <= m.MaxEntries) {
+			break
+		}
+		m.order.Remove(front)
+		delete(m.entries, entry.key)
+	}
+}
@@ -0,0 +1,108 @@
+package constellation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestCountCache tests that counts are served through a read-through cache
+func TestCountCache(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"total": 7}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.UseCountCache(constellation.NewMemoryCache(time.Minute, client.CountGetter()))
+
+	params := constellation.LinksParams{
+		Target:     "at://did:plc:example/app.bsky.feed.post/example",
+		Collection: "app.bsky.feed.like",
+		Path:       ".subject.uri",
+	}
+
+	for i := 0; i < 3; i++ {
+		count, err := client.GetLinksCount(params)
+		if err != nil {
+			t.Fatalf("Failed to get links count: %v", err)
+		}
+		if count.Total != 7 {
+			t.Errorf("Expected count 7, got %d", count.Total)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected 1 API call, got %d", calls)
+	}
+
+	if _, err := client.GetDistinctDIDsCount(params); err != nil {
+		t.Fatalf("Failed to get distinct DIDs count: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected distinct DID count to use a separate cache key, got %d calls", calls)
+	}
+}
+
+// TestMemoryCacheSingleflight tests that concurrent misses share one load
+func TestMemoryCacheSingleflight(t *testing.T) {
+	var loads atomic.Int64
+	release := make(chan struct{})
+	cache := constellation.NewMemoryCache(time.Minute, constellation.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		loads.Add(1)
+		<-release
+		return []byte("7"), nil
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if data, err := cache.Get(context.Background(), "key"); err != nil || string(data) != "7" {
+				t.Errorf("Unexpected result %q, %v", data, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if loads.Load() != 1 {
+		t.Errorf("Expected one load for concurrent misses, got %d", loads.Load())
+	}
+}
+
+// TestMemoryCacheEviction tests evicting expired entries and bounding the size
+func TestMemoryCacheEviction(t *testing.T) {
+	source := constellation.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte(key), nil
+	})
+	ctx := context.Background()
+
+	cache := constellation.NewMemoryCache(10*time.Millisecond, source)
+	cache.Get(ctx, "a")
+	cache.Get(ctx, "b")
+	time.Sleep(20 * time.Millisecond)
+	cache.Get(ctx, "c")
+	if cache.Len() != 1 {
+		t.Errorf("Expected expired entries to be evicted, got %d entries", cache.Len())
+	}
+
+	cache = constellation.NewMemoryCache(time.Minute, source)
+	cache.MaxEntries = 2
+	for _, key := range []string{"a", "b", "c", "b", "d"} {
+		cache.Get(ctx, key)
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Expected at most 2 entries, got %d", cache.Len())
+	}
+}
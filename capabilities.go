@@ -0,0 +1,131 @@
+package constellation
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultMaxLimit is the maximum page size assumed when the server doesn't report one
+const DefaultMaxLimit = 100
+
+// Capabilities describes the limits and features reported by a Constellation instance
+type Capabilities struct {
+	MaxLimit     int           // Maximum page size accepted by the server
+	Endpoints    []string      // Endpoints advertised by the server; nil if not reported
+	IndexLag     time.Duration // Delay between firehose events and indexing; zero if not reported
+	DaysIndexed  int           // Number of days of history the server has indexed
+	OffsetPaging bool          // Whether the server accepts an offset parameter for random page access
+	Filters      []string      // Server-side filters the server supports, e.g. FilterDID
+}
+
+// SupportsEndpoint reports whether the server advertises the given endpoint.
+// Servers that don't advertise their endpoints are assumed to support everything.
+func (caps *Capabilities) SupportsEndpoint(endpoint string) bool {
+	if caps.Endpoints == nil {
+		return true
+	}
+	for _, supported := range caps.Endpoints {
+		if supported == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// ClampLimit restricts limit to the server's maximum page size
+func (caps *Capabilities) ClampLimit(limit int) int {
+	if caps.MaxLimit > 0 && limit > caps.MaxLimit {
+		return caps.MaxLimit
+	}
+	return limit
+}
+
+// CoversTarget reports whether the indexed history is likely to include links to target.
+// Targets whose record key is a TID created before the indexed window may have
+// incomplete link data. Targets without a TID record key are assumed to be covered.
+func (caps *Capabilities) CoversTarget(target string) bool {
+	if caps.DaysIndexed <= 0 {
+		return true
+	}
+
+	createdAt, ok := tidTime(atURIRKey(target))
+	if !ok {
+		return true
+	}
+
+	indexedSince := time.Now().AddDate(0, 0, -caps.DaysIndexed)
+	return !createdAt.Before(indexedSince)
+}
+
+// GetCapabilities retrieves the limits and features of the Constellation instance
+// from the root endpoint
+func (c *Client) GetCapabilities() (*Capabilities, error) {
+	info, err := c.GetAPIInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	return capabilitiesFromInfo(info), nil
+}
+
+// capabilitiesFromInfo extracts capabilities from a root endpoint response
+func capabilitiesFromInfo(info *APIResponse) *Capabilities {
+	caps := &Capabilities{
+		MaxLimit:     info.MaxLimit,
+		Endpoints:    info.Endpoints,
+		IndexLag:     time.Duration(info.IndexLagSeconds * float64(time.Second)),
+		DaysIndexed:  info.DaysIndexed,
+		OffsetPaging: info.OffsetPaging,
+		Filters:      info.Filters,
+	}
+	if caps.MaxLimit <= 0 {
+		caps.MaxLimit = DefaultMaxLimit
+	}
+
+	return caps
+}
+
+// UseCapabilities makes the client clamp page sizes to the server's limits and
+// log a warning when a target predates the indexed history
+func (c *Client) UseCapabilities(caps *Capabilities) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.capabilities = caps
+	return c
+}
+
+// applyCapabilities adjusts params to the server's known capabilities, warning
+// about queries whose target is the wrong kind for their path
+func (c *Client) applyCapabilities(params LinksParams) LinksParams {
+	if kind := TargetKindOf(NormalizeTarget(params.Target)); kind != TargetUnknown {
+		if err := params.checkKind(kind); err != nil {
+			c.warnOnce(params, "query can't match any links", "target", params.Target, "error", err)
+		}
+	}
+
+	c.mu.Lock()
+	caps := c.capabilities
+	c.mu.Unlock()
+
+	if caps == nil {
+		return params
+	}
+
+	params.Limit = caps.ClampLimit(params.Limit)
+	if !caps.CoversTarget(params.Target) {
+		c.warnOnce(params, "target predates indexed history; results may be incomplete",
+			"target", params.Target, "days_indexed", caps.DaysIndexed)
+	}
+
+	return params
+}
+
+// warnOnce logs a warning about the query described by params unless it was
+// already logged, so paging through results doesn't repeat it for every page
+func (c *Client) warnOnce(params LinksParams, msg string, args ...any) {
+	key := strings.Join([]string{msg, NormalizeTarget(params.Target), params.Collection, params.Path}, "\x00")
+	if _, warned := c.warned.LoadOrStore(key, struct{}{}); !warned {
+		c.warn(msg, args...)
+	}
+}
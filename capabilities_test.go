@@ -0,0 +1,93 @@
+package constellation_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestCapabilities tests capability parsing and limit clamping
+func TestCapabilities(t *testing.T) {
+	var limit string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			w.Write([]byte(`{"days_indexed": 30, "max_limit": 50, "endpoints": ["/links"]}`))
+			return
+		}
+		limit = r.URL.Query().Get("limit")
+		w.Write([]byte(`{"total": 0}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	caps, err := client.GetCapabilities()
+	if err != nil {
+		t.Fatalf("Failed to get capabilities: %v", err)
+	}
+
+	if caps.MaxLimit != 50 {
+		t.Errorf("Expected MaxLimit 50, got %d", caps.MaxLimit)
+	}
+	if !caps.SupportsEndpoint("/links") || caps.SupportsEndpoint("/links/count") {
+		t.Errorf("Unexpected endpoint support for %v", caps.Endpoints)
+	}
+
+	client.UseCapabilities(caps)
+	_, err = client.GetLinks(constellation.LinksParams{
+		Target: "at://did:plc:example/app.bsky.feed.post/example",
+		Limit:  500,
+	})
+	if err != nil {
+		t.Fatalf("Failed to get links: %v", err)
+	}
+	if limit != "50" {
+		t.Errorf("Expected limit clamped to 50, got '%s'", limit)
+	}
+}
+
+// TestCapabilitiesCoversTarget tests detection of targets older than the indexed history
+func TestCapabilitiesCoversTarget(t *testing.T) {
+	caps := &constellation.Capabilities{DaysIndexed: 30}
+
+	// TID record key from January 2025
+	oldTarget := "at://did:plc:vc7f4oafdgxsihk4cry2xpze/app.bsky.feed.post/3lgwdn7vd722r"
+	if caps.CoversTarget(oldTarget) {
+		t.Error("Expected old target to fall outside the indexed history")
+	}
+
+	if !caps.CoversTarget("did:plc:vc7f4oafdgxsihk4cry2xpze") {
+		t.Error("Expected DID target to be assumed covered")
+	}
+}
+
+// TestOnWarning tests that OnWarning receives redacted warnings without a Logger
+func TestOnWarning(t *testing.T) {
+	server := newPagedServer(t, 5)
+	var warnings []string
+	var targets []any
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second).UseRedactor(constellation.HashRedactor("salt", 12))
+	client.OnWarning = func(msg string, args ...any) {
+		warnings = append(warnings, msg)
+		for i := 0; i+1 < len(args); i += 2 {
+			if args[i] == "target" {
+				targets = append(targets, args[i+1])
+			}
+		}
+	}
+	client.UseCapabilities(&constellation.Capabilities{MaxLimit: 100, DaysIndexed: 1})
+
+	oldTarget := "at://did:plc:vc7f4oafdgxsihk4cry2xpze/app.bsky.feed.post/3lgwdn7vd722r" // January 2025
+	if _, err := client.GetLinks(constellation.LinksParams{Target: oldTarget}); err != nil {
+		t.Fatalf("GetLinks failed: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "predates indexed history") {
+		t.Fatalf("Expected one history warning, got %q", warnings)
+	}
+	if len(targets) != 1 || targets[0] == oldTarget {
+		t.Errorf("Expected a redacted target attribute, got %v", targets)
+	}
+}
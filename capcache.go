@@ -0,0 +1,140 @@
+package constellation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCapabilitiesTTL is how long cached capabilities are trusted
+const DefaultCapabilitiesTTL = 24 * time.Hour
+
+// CapabilitiesCache persists detected capabilities to a small JSON file keyed by
+// instance base URL, so short-lived CLIs don't re-probe the instance on every
+// invocation
+type CapabilitiesCache struct {
+	Path string
+	TTL  time.Duration // Entries older than this are ignored; defaults to DefaultCapabilitiesTTL
+}
+
+// cachedCapabilities is a capabilities cache file entry
+type cachedCapabilities struct {
+	Capabilities *Capabilities `json:"capabilities"`
+	DetectedAt   time.Time     `json:"detected_at"`
+}
+
+// DefaultCapabilitiesCache returns a cache in the user's cache directory
+func DefaultCapabilitiesCache() (*CapabilitiesCache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &CapabilitiesCache{Path: filepath.Join(dir, "constellation-go", "capabilities.json")}, nil
+}
+
+// Load returns the cached capabilities of the instance at baseURL, and false if
+// none are cached or they have expired
+func (cc *CapabilitiesCache) Load(baseURL string) (*Capabilities, bool) {
+	entries, err := cc.read()
+	if err != nil {
+		return nil, false
+	}
+	entry, ok := entries[baseURL]
+	if !ok || entry.Capabilities == nil || time.Since(entry.DetectedAt) > cc.ttl() {
+		return nil, false
+	}
+	return entry.Capabilities, true
+}
+
+// Save caches the capabilities of the instance at baseURL
+func (cc *CapabilitiesCache) Save(baseURL string, caps *Capabilities) error {
+	entries, err := cc.read()
+	if err != nil {
+		// Replace an unreadable cache rather than failing forever
+		entries = make(map[string]cachedCapabilities)
+	}
+	entries[baseURL] = cachedCapabilities{Capabilities: caps, DetectedAt: time.Now()}
+	return cc.write(entries)
+}
+
+// Forget drops the cached capabilities of the instance at baseURL
+func (cc *CapabilitiesCache) Forget(baseURL string) error {
+	entries, err := cc.read()
+	if err != nil {
+		return nil
+	}
+	if _, ok := entries[baseURL]; !ok {
+		return nil
+	}
+	delete(entries, baseURL)
+	return cc.write(entries)
+}
+
+// ttl returns the configured TTL or the default
+func (cc *CapabilitiesCache) ttl() time.Duration {
+	if cc.TTL > 0 {
+		return cc.TTL
+	}
+	return DefaultCapabilitiesTTL
+}
+
+// read loads every cache entry, returning an empty map if the file doesn't exist
+func (cc *CapabilitiesCache) read() (map[string]cachedCapabilities, error) {
+	data, err := os.ReadFile(cc.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return make(map[string]cachedCapabilities), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries map[string]cachedCapabilities
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid capabilities cache: %w", err)
+	}
+	if entries == nil {
+		entries = make(map[string]cachedCapabilities)
+	}
+	return entries, nil
+}
+
+// write atomically replaces the cache file with entries
+func (cc *CapabilitiesCache) write(entries map[string]cachedCapabilities) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	store := &FileStore{Dir: filepath.Dir(cc.Path)}
+	return store.PutBlob(context.Background(), filepath.Base(cc.Path), bytes.NewReader(data))
+}
+
+// UseCapabilitiesCache makes DetectCapabilities read capabilities from cache when
+// they're fresh, and save them there after probing
+func (c *Client) UseCapabilitiesCache(cache *CapabilitiesCache) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.capabilitiesCache = cache
+	return c
+}
+
+// RefreshCapabilities probes the instance again, ignoring capabilities detected
+// earlier or cached on disk, and updates the cache
+func (c *Client) RefreshCapabilities(ctx context.Context) (*Capabilities, error) {
+	c.mu.Lock()
+	c.detected = false
+	cache := c.capabilitiesCache
+	c.mu.Unlock()
+
+	if cache != nil {
+		if err := cache.Forget(c.BaseURL); err != nil {
+			c.warn("failed to clear cached capabilities", "error", err)
+		}
+	}
+	return c.DetectCapabilities(ctx)
+}
@@ -0,0 +1,76 @@
+package constellation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestCapabilitiesCache tests that detected capabilities are reused across clients until refreshed
+func TestCapabilitiesCache(t *testing.T) {
+	rootCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rootCalls++
+		w.Write([]byte(`{"max_limit": 50, "endpoints": ["/links"]}`))
+	}))
+	defer server.Close()
+
+	cache := &constellation.CapabilitiesCache{Path: filepath.Join(t.TempDir(), "caps", "capabilities.json")}
+	ctx := context.Background()
+
+	first := constellation.NewClientWithConfig(server.URL, 5*time.Second).UseCapabilitiesCache(cache)
+	if _, err := first.DetectCapabilities(ctx); err != nil {
+		t.Fatalf("Failed to detect capabilities: %v", err)
+	}
+
+	// A new client, as in the next CLI invocation, reads the cache
+	second := constellation.NewClientWithConfig(server.URL, 5*time.Second).UseCapabilitiesCache(cache)
+	caps, err := second.DetectCapabilities(ctx)
+	if err != nil {
+		t.Fatalf("Failed to detect capabilities: %v", err)
+	}
+	if rootCalls != 1 {
+		t.Errorf("Expected the cache to avoid probing again, got %d root calls", rootCalls)
+	}
+	if caps.MaxLimit != 50 || !caps.SupportsEndpoint("/links") || caps.SupportsEndpoint("/links/count") {
+		t.Errorf("Expected cached capabilities to round-trip, got %+v", caps)
+	}
+
+	if _, err := second.RefreshCapabilities(ctx); err != nil {
+		t.Fatalf("Failed to refresh capabilities: %v", err)
+	}
+	if rootCalls != 2 {
+		t.Errorf("Expected refreshing to probe again, got %d root calls", rootCalls)
+	}
+
+	expired := &constellation.CapabilitiesCache{Path: cache.Path, TTL: time.Nanosecond}
+	time.Sleep(time.Millisecond)
+	if _, ok := expired.Load(server.URL); ok {
+		t.Error("Expected expired capabilities to be ignored")
+	}
+}
+
+// TestCapabilitiesCacheCorrupt tests that an unreadable cache is replaced rather than fatal
+func TestCapabilitiesCacheCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capabilities.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cache := &constellation.CapabilitiesCache{Path: path}
+
+	if _, ok := cache.Load("http://example.com"); ok {
+		t.Error("Expected a miss from a corrupt cache")
+	}
+	if err := cache.Save("http://example.com", &constellation.Capabilities{MaxLimit: 10}); err != nil {
+		t.Fatalf("Failed to save over a corrupt cache: %v", err)
+	}
+	if caps, ok := cache.Load("http://example.com"); !ok || caps.MaxLimit != 10 {
+		t.Errorf("Expected the saved capabilities, got %+v, %v", caps, ok)
+	}
+}
@@ -0,0 +1,49 @@
+//go:build !production
+
+package constellation
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// chaosMaxDelay is the upper bound for delays injected in chaos mode
+const chaosMaxDelay = 2 * time.Second
+
+// WithChaos enables chaos testing mode on the client. Each request has probability p
+// of failing with ErrChaosInjected and, independently, probability p of being delayed
+// by up to two seconds. Chaos mode is compiled out of builds using the production tag.
+func (c *Client) WithChaos(p float64) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.chaosProbability = p
+	return c
+}
+
+// injectChaos randomly fails or delays a request according to the chaos probability
+func (c *Client) injectChaos(req *http.Request) error {
+	c.mu.Lock()
+	p := c.chaosProbability
+	c.mu.Unlock()
+	if p <= 0 {
+		return nil
+	}
+
+	if rand.Float64() < p {
+		return fmt.Errorf("failed to make request: %w", ErrChaosInjected)
+	}
+
+	if rand.Float64() < p {
+		delay := time.Duration(rand.Int63n(int64(chaosMaxDelay)))
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return fmt.Errorf("failed to make request: %w", req.Context().Err())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build production
+
+package constellation
+
+import "net/http"
+
+// WithChaos is a no-op in production builds
+func (c *Client) WithChaos(p float64) *Client {
+	return c
+}
+
+// injectChaos never injects faults in production builds
+func (c *Client) injectChaos(req *http.Request) error {
+	return nil
+}
@@ -0,0 +1,54 @@
+//go:build !production
+
+package constellation_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestWithChaos tests that chaos mode injects failures and can be disabled
+func TestWithChaos(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"days_indexed": 10}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second).WithChaos(1)
+	_, err := client.GetAPIInfo()
+	if !errors.Is(err, constellation.ErrChaosInjected) {
+		t.Errorf("Expected ErrChaosInjected, got: %v", err)
+	}
+
+	client.WithChaos(0)
+	if _, err := client.GetAPIInfo(); err != nil {
+		t.Errorf("Expected no error with chaos disabled, got: %v", err)
+	}
+}
+
+// TestWithChaosConcurrent tests toggling chaos mode while requests are in flight,
+// for the race detector
+func TestWithChaosConcurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"days_indexed": 10}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			client.GetAPIInfo()
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		client.WithChaos(float64(i%2) * 0.001)
+	}
+	<-done
+}
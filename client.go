@@ -3,11 +3,14 @@
 package constellation
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -22,6 +25,19 @@ const (
 	EnvUserAgent = "CONSTELLATION_USER_AGENT"
 )
 
+// ErrChaosInjected is returned for synthetic failures injected by chaos testing mode
+var ErrChaosInjected = errors.New("chaos: injected failure")
+
+// APIError is returned when the API responds with a non-200 status
+type APIError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status: %s", e.Status)
+}
+
 // getUserAgent returns the User-Agent string, checking environment variable first
 func getUserAgent() string {
 	if envUserAgent := os.Getenv(EnvUserAgent); envUserAgent != "" {
@@ -35,6 +51,55 @@ type Client struct {
 	BaseURL    string
 	HTTPClient *http.Client
 	UserAgent  string
+
+	// clientLogger adds Logger, a *slog.Logger receiving the client's warnings
+	// and debug messages, on Go 1.21 and later
+	clientLogger
+
+	// OnWarning, if set, receives the client's warnings as a message and
+	// key-value attributes, alongside Logger. It works on every toolchain, so
+	// code built with Go 1.20 can log warnings with the logger of its choice.
+	OnWarning func(msg string, args ...any)
+
+	// PLCDirectory is the PLC directory used to resolve did:plc identities,
+	// defaulting to DefaultPLCDirectory when empty
+	PLCDirectory string
+
+	// MembershipScanLimit caps the links HasLink and the checks built on it scan
+	// on instances without the server-side DID filter, defaulting to
+	// DefaultMembershipScanLimit when zero
+	MembershipScanLimit int
+
+	// CountScanLimit caps the links or DIDs counted by paging when the instance
+	// can't count a query itself, defaulting to DefaultCountScanLimit when zero.
+	// Counts are lower bounds when they reach it. It also caps the list items
+	// ActorStats scans to count starter packs.
+	CountScanLimit int
+
+	mu                sync.Mutex
+	capabilities      *Capabilities
+	capabilitiesCache *CapabilitiesCache
+	codecs            []Codec // Preferred response encodings besides JSON
+	countCache        Getter
+	membershipCache   Getter
+	livenessCache     Getter
+	auditLog          *AuditLog
+	exclusions        []func(did string) bool
+	excludedLists     map[string]*listExclusion // Keyed by list URI, for ExcludeList
+	preflight         bool
+	detected          bool
+	lastStats         *StatsReport
+	statsEMA          [3]EMA // DIDs, targetables, and linking records, for StatsReport.Rates
+	metrics           requestMetrics
+	semaphore         chan struct{}
+	rateLimiter       *tokenBucket
+	chaosProbability  float64
+	redactor          Redactor
+	adaptive          *AdaptiveTimeout
+	mirror            *Mirror
+	timeouts          Timeouts
+	lastRequest       atomic.Int64 // Unix nanoseconds of the last request, for keep-alive
+	warned            sync.Map     // Query warnings already logged, so pages of a crawl warn once
 }
 
 // NewClient creates a new Constellation API client with default settings
@@ -76,6 +141,13 @@ type APIResponse struct {
 	DaysIndexed int    `json:"days_indexed,omitempty"`
 	Stats       Stats  `json:"stats,omitempty"`
 	Error       string `json:"error,omitempty"`
+
+	// Optional capability fields, reported by instances that advertise them
+	MaxLimit        int      `json:"max_limit,omitempty"`
+	Endpoints       []string `json:"endpoints,omitempty"`
+	IndexLagSeconds float64  `json:"index_lag_seconds,omitempty"`
+	OffsetPaging    bool     `json:"offset_pagination,omitempty"`
+	Filters         []string `json:"filters,omitempty"`
 }
 
 // Stats represents the statistics from the API
@@ -98,27 +170,104 @@ type LinkRecord struct {
 
 // makeRequest performs an HTTP GET request to the specified endpoint with parameters
 func (c *Client) makeRequest(endpoint string, params url.Values) (*http.Response, error) {
+	return c.makeRequestContext(context.Background(), endpoint, params)
+}
+
+// makeRequestContext performs an HTTP GET request bound to ctx, failing early with
+// ErrUnsupportedEndpoint when preflight detection shows the instance lacks the endpoint
+func (c *Client) makeRequestContext(ctx context.Context, endpoint string, params url.Values) (*http.Response, error) {
+	if err := c.checkSupported(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	if err := spendRequest(ctx); err != nil {
+		return nil, err
+	}
+	return c.doRequest(ctx, endpoint, params)
+}
+
+// doRequest performs an HTTP GET request without preflight checks
+func (c *Client) doRequest(ctx context.Context, endpoint string, params url.Values) (*http.Response, error) {
 	fullURL := fmt.Sprintf("%s%s", c.BaseURL, endpoint)
 	if len(params) > 0 {
 		fullURL = fmt.Sprintf("%s?%s", fullURL, params.Encode())
 	}
 
-	req, err := http.NewRequest("GET", fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept", c.acceptHeader())
 	req.Header.Set("User-Agent", c.UserAgent)
 
+	if err := c.injectChaos(req); err != nil {
+		return nil, err
+	}
+
+	if err := c.waitForRateLimit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+
+	release, err := c.acquireSlot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer release()
+
+	cancel := context.CancelFunc(func() {})
+	if timeout, ok := c.RequestTimeout(endpoint); ok {
+		var timeoutCtx context.Context
+		timeoutCtx, cancel = context.WithTimeout(ctx, timeout)
+		req = req.WithContext(timeoutCtx)
+	}
+
+	req, phases := c.startTimeouts(req)
+
+	c.markActive()
+	sample := latencySample{endpoint: endpoint, concurrency: c.metrics.start()}
+	start := time.Now()
 	resp, err := c.HTTPClient.Do(req)
+	if phases != nil {
+		err = phases.headers(resp, err)
+	}
+	sample.latency = time.Since(start)
+	sample.failed = err != nil || resp.StatusCode != http.StatusOK
+	c.metrics.finish(sample)
+	if err != nil {
+		cancel()
+	} else {
+		resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	}
+
+	c.mu.Lock()
+	auditLog := c.auditLog
+	c.mu.Unlock()
+	if auditLog != nil {
+		entry := AuditEntry{Timestamp: start.UTC(), Endpoint: endpoint, ParamsHash: hashParams(params)}
+		if err != nil {
+			entry.Error = err.Error()
+			entry.DurationMS = sample.latency.Milliseconds()
+			auditLog.write(entry)
+		} else {
+			entry.Status = resp.StatusCode
+			resp.Body = &auditedBody{ReadCloser: resp.Body, log: auditLog, entry: entry, start: start}
+		}
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		resp.Body.Close()
-		return nil, fmt.Errorf("API request failed with status: %s", resp.Status)
+		return nil, &APIError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	c.mu.Lock()
+	mirror := c.mirror
+	c.mu.Unlock()
+	if mirror != nil {
+		resp.Body = mirror.wrap(endpoint, params, resp.Body)
 	}
 
 	return resp, nil
@@ -126,14 +275,19 @@ func (c *Client) makeRequest(endpoint string, params url.Values) (*http.Response
 
 // GetAPIInfo retrieves basic information about the Constellation API
 func (c *Client) GetAPIInfo() (*APIResponse, error) {
-	resp, err := c.makeRequest("/", nil)
+	return c.getAPIInfo(context.Background())
+}
+
+// getAPIInfo retrieves the root endpoint response bound to ctx
+func (c *Client) getAPIInfo(ctx context.Context) (*APIResponse, error) {
+	resp, err := c.makeRequestContext(ctx, "/", nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var apiResp APIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+	if err := c.decodeResponse(resp, &apiResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
@@ -1,6 +1,9 @@
 package constellation_test
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
 	"time"
@@ -134,3 +137,60 @@ func TestStructDefinitions(t *testing.T) {
 		t.Errorf("Expected Collection 'app.bsky.feed.like', got '%s'", linkRecord.Collection)
 	}
 }
+
+// TestLinksParamsExtra tests that extra query parameters are passed through
+func TestLinksParamsExtra(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"total": 0}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{
+		Target: "at://did:plc:example/app.bsky.feed.post/example",
+		Extra:  url.Values{"since": {"2024-01-01T00:00:00Z"}},
+	}
+
+	if _, err := client.GetLinksCount(params); err != nil {
+		t.Fatalf("Failed to get links count: %v", err)
+	}
+
+	if query.Get("since") != "2024-01-01T00:00:00Z" {
+		t.Errorf("Expected extra parameter 'since' to be passed through, got query: %v", query)
+	}
+	if query.Get("target") != params.Target {
+		t.Errorf("Expected target '%s', got '%s'", params.Target, query.Get("target"))
+	}
+}
+
+// TestLinksParamsExtraCollision tests that modeled fields win over colliding extras
+func TestLinksParamsExtraCollision(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"total": 0}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{
+		Target: "did:plc:example",
+		Limit:  10,
+		Extra:  url.Values{"target": {"did:plc:other"}, "limit": {"99"}, "cursor": {"abc"}},
+	}
+
+	if _, err := client.GetLinks(params); err != nil {
+		t.Fatalf("Failed to get links: %v", err)
+	}
+	if got := query["target"]; len(got) != 1 || got[0] != "did:plc:example" {
+		t.Errorf("Expected only the modeled target, got %v", got)
+	}
+	if got := query["limit"]; len(got) != 1 || got[0] != "10" {
+		t.Errorf("Expected only the modeled limit, got %v", got)
+	}
+	if query.Get("cursor") != "abc" {
+		t.Errorf("Expected an extra for an unset field to pass through, got %v", query)
+	}
+}
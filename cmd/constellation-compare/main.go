@@ -0,0 +1,88 @@
+// Command constellation-compare runs a set of queries against two Constellation
+// instances and reports discrepancies. Queries are read as a JSON array of
+// {"target", "collection", "path"} objects.
+//
+//	constellation-compare -a https://constellation.microcosm.blue -b http://localhost:6789 queries.json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+func main() {
+	baseA := flag.String("a", constellation.DefaultBaseURL, "base URL of the first instance")
+	baseB := flag.String("b", "", "base URL of the second instance")
+	records := flag.Bool("records", false, "also compare linking records, not just counts")
+	maxRecords := flag.Int("max-records", 10000, "maximum records fetched per query when comparing records")
+	refresh := flag.Bool("refresh-capabilities", false, "probe instance capabilities again instead of using the cached ones")
+	flag.Parse()
+
+	if *baseB == "" || flag.NArg() != 1 {
+		log.Fatalf("usage: %s -a <url> -b <url> [-records] [-refresh-capabilities] <queries.json>", os.Args[0])
+	}
+
+	data, err := os.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("failed to read queries: %v", err)
+	}
+
+	var queries []struct {
+		Target     string `json:"target"`
+		Collection string `json:"collection"`
+		Path       string `json:"path"`
+	}
+	if err := json.Unmarshal(data, &queries); err != nil {
+		log.Fatalf("failed to parse queries: %v", err)
+	}
+
+	params := make([]constellation.LinksParams, len(queries))
+	for i, query := range queries {
+		params[i] = constellation.LinksParams{Target: query.Target, Collection: query.Collection, Path: query.Path}
+	}
+
+	a := constellation.NewClientWithConfig(*baseA, constellation.DefaultTimeout)
+	b := constellation.NewClientWithConfig(*baseB, constellation.DefaultTimeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	// Capabilities clamp page sizes to each instance's limits; they're cached on
+	// disk so repeated runs don't re-probe
+	cache, cacheErr := constellation.DefaultCapabilitiesCache()
+	for _, client := range []*constellation.Client{a, b} {
+		if cacheErr == nil {
+			client.UseCapabilitiesCache(cache)
+		}
+		detect := client.DetectCapabilities
+		if *refresh {
+			detect = client.RefreshCapabilities
+		}
+		if _, err := detect(ctx); err != nil {
+			log.Printf("failed to detect capabilities of %s: %v", client.BaseURL, err)
+		}
+	}
+
+	inconsistent := 0
+	for _, result := range constellation.Compare(ctx, a, b, params, constellation.CompareOptions{
+		Records:    *records,
+		MaxRecords: *maxRecords,
+	}) {
+		fmt.Println(result)
+		if !result.Consistent() {
+			inconsistent++
+		}
+	}
+
+	if inconsistent > 0 {
+		fmt.Printf("%d of %d queries differ\n", inconsistent, len(params))
+		os.Exit(1)
+	}
+}
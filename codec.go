@@ -0,0 +1,105 @@
+package constellation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// MediaTypeJSON is the media type of JSON responses, which every instance serves
+const MediaTypeJSON = "application/json"
+
+// Codec decodes API responses of one media type. JSONCodec is always available;
+// others, such as CBOR, can be added with UseCodecs and are requested when the
+// instance offers them. Response types carry json struct tags, which most CBOR
+// libraries also honor.
+type Codec interface {
+	MediaType() string
+	Decode(r io.Reader, v any) error
+}
+
+// CodecFunc adapts a decoding function for mediaType to the Codec interface:
+//
+//	cborCodec := constellation.CodecFunc("application/cbor", func(r io.Reader, v any) error {
+//		return cbor.NewDecoder(r).Decode(v)
+//	})
+func CodecFunc(mediaType string, decode func(r io.Reader, v any) error) Codec {
+	return codecFunc{mediaType: mediaType, decode: decode}
+}
+
+type codecFunc struct {
+	mediaType string
+	decode    func(r io.Reader, v any) error
+}
+
+func (f codecFunc) MediaType() string               { return f.mediaType }
+func (f codecFunc) Decode(r io.Reader, v any) error { return f.decode(r, v) }
+
+// JSONCodec decodes JSON responses
+var JSONCodec Codec = CodecFunc(MediaTypeJSON, func(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+})
+
+// UseCodecs sets the encodings requested from the instance, most preferred
+// first. JSON is always accepted last, so instances without the preferred
+// encodings keep working. Responses are decoded by their Content-Type; with no
+// codecs set, only JSON is requested.
+func (c *Client) UseCodecs(codecs ...Codec) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.codecs = nil
+	for _, codec := range codecs {
+		if codec.MediaType() != MediaTypeJSON {
+			c.codecs = append(c.codecs, codec)
+		}
+	}
+	return c
+}
+
+// acceptHeader returns the Accept header listing the client's codecs by preference
+func (c *Client) acceptHeader() string {
+	c.mu.Lock()
+	codecs := c.codecs
+	c.mu.Unlock()
+
+	if len(codecs) == 0 {
+		return MediaTypeJSON
+	}
+	types := make([]string, 0, len(codecs)+1)
+	for i, codec := range codecs {
+		q := ""
+		if i > 0 {
+			q = fmt.Sprintf(";q=%.1f", maxOf(0.9-0.1*float64(i-1), 0.2))
+		}
+		types = append(types, codec.MediaType()+q)
+	}
+	return strings.Join(append(types, MediaTypeJSON+";q=0.1"), ", ")
+}
+
+// codecFor returns the codec for a response's Content-Type, falling back to
+// JSON for missing or unrecognized types
+func (c *Client) codecFor(contentType string) Codec {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return JSONCodec
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, codec := range c.codecs {
+		if strings.EqualFold(codec.MediaType(), mediaType) {
+			return codec
+		}
+	}
+	return JSONCodec
+}
+
+// decodeResponse decodes an API response body into v with the codec matching
+// its Content-Type
+func (c *Client) decodeResponse(resp *http.Response, v any) error {
+	return c.codecFor(resp.Header.Get("Content-Type")).Decode(resp.Body, v)
+}
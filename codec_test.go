@@ -0,0 +1,68 @@
+package constellation_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// base64Codec decodes base64-encoded JSON, standing in for an alternate encoding
+var base64Codec = constellation.CodecFunc("application/x-base64-json", func(r io.Reader, v any) error {
+	return json.NewDecoder(base64.NewDecoder(base64.StdEncoding, r)).Decode(v)
+})
+
+// TestContentNegotiation tests requesting and decoding alternate encodings
+func TestContentNegotiation(t *testing.T) {
+	var accept string
+	jsonOnly := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		body := `{"total": 5}`
+		if !jsonOnly && strings.Contains(accept, "application/x-base64-json") {
+			w.Header().Set("Content-Type", "application/x-base64-json; charset=utf-8")
+			w.Write([]byte(base64.StdEncoding.EncodeToString([]byte(body))))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:a"}
+
+	if count, err := client.GetLinksCount(params); err != nil || count.Total != 5 {
+		t.Fatalf("Expected a JSON count of 5, got %+v, %v", count, err)
+	}
+	if accept != "application/json" {
+		t.Errorf("Expected only JSON to be requested by default, got %q", accept)
+	}
+
+	client.UseCodecs(base64Codec)
+	if count, err := client.GetLinksCount(params); err != nil || count.Total != 5 {
+		t.Fatalf("Expected a decoded count of 5, got %+v, %v", count, err)
+	}
+	if accept != "application/x-base64-json, application/json;q=0.1" {
+		t.Errorf("Unexpected Accept header %q", accept)
+	}
+
+	// Instances without the preferred encoding still answer in JSON
+	jsonOnly = true
+	if count, err := client.GetLinksCount(params); err != nil || count.Total != 5 {
+		t.Errorf("Expected a JSON fallback count of 5, got %+v, %v", count, err)
+	}
+
+	other := constellation.CodecFunc("application/cbor", nil)
+	client.UseCodecs(other, base64Codec, constellation.JSONCodec)
+	client.GetLinksCount(params)
+	if accept != "application/cbor, application/x-base64-json;q=0.9, application/json;q=0.1" {
+		t.Errorf("Unexpected Accept header for several codecs %q", accept)
+	}
+}
@@ -0,0 +1,185 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Comment is a reply in a comment thread, hydrated for rendering
+type Comment struct {
+	URI       string     `json:"uri"`
+	CID       string     `json:"cid,omitempty"`
+	DID       string     `json:"did"`
+	Handle    string     `json:"handle,omitempty"`
+	Text      string     `json:"text,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Deleted   bool       `json:"deleted,omitempty"` // The record no longer exists; kept so its replies stay in place
+	Replies   []*Comment `json:"replies,omitempty"`
+}
+
+// GetCommentThread returns the replies in the thread rooted at postURI as a nested
+// comment tree, oldest first at each level. Replies are found by their
+// .reply.root.uri backlinks and hydrated from their authors' PDSes, with
+// DefaultParallelism concurrent workers and one DID lookup per author. Replies
+// whose record or account no longer exists are kept as Deleted; any other
+// hydration failure is returned.
+func (c *Client) GetCommentThread(ctx context.Context, postURI string) ([]*Comment, error) {
+	records, err := c.ThreadRepliesOf(ctx, postURI, PaginateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := c.resolveAuthors(ctx, records)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]*Comment, len(records))
+	parents := make([]string, len(records))
+	errs := make([]error, len(records))
+	parallelEach(len(records), DefaultParallelism, func(i int) {
+		comments[i], parents[i], errs[i] = c.hydrateComment(ctx, records[i], docs[records[i].DID])
+	})
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	byURI := make(map[string]*Comment, len(comments))
+	parentOf := make(map[string]string, len(comments))
+	var unique []*Comment
+	for i, comment := range comments {
+		if _, dup := byURI[comment.URI]; dup {
+			continue
+		}
+		byURI[comment.URI] = comment
+		parentOf[comment.URI] = parents[i]
+		unique = append(unique, comment)
+	}
+
+	// Replies whose parent is unknown (deleted or not indexed) are shown at the
+	// top level, as are replies in a parent cycle, which only malformed records
+	// can form
+	var top []*Comment
+	for _, comment := range unique {
+		if parent, ok := byURI[parentOf[comment.URI]]; ok && !inParentCycle(comment.URI, parentOf) {
+			parent.Replies = append(parent.Replies, comment)
+		} else {
+			top = append(top, comment)
+		}
+	}
+
+	sortComments(top)
+	return top, nil
+}
+
+// resolveAuthors resolves the DID document of each distinct author of records
+// concurrently. Authors whose document no longer exists map to nil.
+func (c *Client) resolveAuthors(ctx context.Context, records []LinkRecord) (map[string]*DIDDocument, error) {
+	var dids []string
+	seen := make(map[string]bool)
+	for _, record := range records {
+		if !seen[record.DID] {
+			seen[record.DID] = true
+			dids = append(dids, record.DID)
+		}
+	}
+
+	resolved := make([]*DIDDocument, len(dids))
+	errs := make([]error, len(dids))
+	parallelEach(len(dids), DefaultParallelism, func(i int) {
+		resolved[i], errs[i] = c.ResolveDID(ctx, dids[i])
+		if notFound(errs[i]) {
+			errs[i] = nil
+		}
+	})
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]*DIDDocument, len(dids))
+	for i, did := range dids {
+		docs[did] = resolved[i]
+	}
+	return docs, nil
+}
+
+// hydrateComment fetches the reply record behind link from its author's PDS
+// and returns it as a comment, with the URI of the post it replies to. A nil doc
+// means the author's account no longer exists.
+func (c *Client) hydrateComment(ctx context.Context, link LinkRecord, doc *DIDDocument) (*Comment, string, error) {
+	uri := link.RecordURI()
+	comment := &Comment{URI: uri, DID: link.DID}
+	if created, ok := tidTime(link.RKey); ok {
+		comment.CreatedAt = created
+	}
+	if doc == nil {
+		comment.Deleted = true
+		return comment, "", nil
+	}
+	comment.Handle = doc.Handle()
+
+	parsed, err := ParseATURI(uri)
+	if err != nil {
+		return nil, "", err
+	}
+	record, err := c.getRecordFrom(ctx, doc, parsed)
+	if notFound(err) {
+		comment.Deleted = true
+		return comment, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	comment.CID = record.CID
+	comment.Text, _ = record.Value["text"].(string)
+	if created, ok := record.Value["createdAt"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, created); err == nil {
+			comment.CreatedAt = t
+		}
+	}
+	var parentURI string
+	if reply, ok := record.Value["reply"].(map[string]any); ok {
+		if parent, ok := reply["parent"].(map[string]any); ok {
+			parentURI, _ = parent["uri"].(string)
+		}
+	}
+	return comment, parentURI, nil
+}
+
+// notFound reports whether err shows a record or DID document doesn't exist
+func notFound(err error) bool {
+	var apiErr *APIError
+	return errors.Is(err, ErrRecordNotFound) ||
+		(errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusGone))
+}
+
+// inParentCycle reports whether following parents up from uri leads back to it
+func inParentCycle(uri string, parentOf map[string]string) bool {
+	visited := make(map[string]bool)
+	for current := parentOf[uri]; ; current = parentOf[current] {
+		if current == uri {
+			return true
+		}
+		if _, ok := parentOf[current]; !ok || visited[current] {
+			return false
+		}
+		visited[current] = true
+	}
+}
+
+// sortComments orders comments and their replies oldest first
+func sortComments(comments []*Comment) {
+	sort.Slice(comments, func(i, j int) bool {
+		if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+		}
+		return comments[i].URI < comments[j].URI
+	})
+	for _, comment := range comments {
+		sortComments(comment.Replies)
+	}
+}
@@ -0,0 +1,133 @@
+package constellation_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestGetCommentThread tests nesting hydrated replies under their parents
+func TestGetCommentThread(t *testing.T) {
+	const root = "at://did:plc:author/app.bsky.feed.post/root"
+
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/links":
+			w.Write([]byte(`{"total": 3, "linking_records": [
+				{"did": "did:plc:bob", "collection": "app.bsky.feed.post", "rkey": "b"},
+				{"did": "did:plc:alice", "collection": "app.bsky.feed.post", "rkey": "a"},
+				{"did": "did:plc:carol", "collection": "app.bsky.feed.post", "rkey": "gone"}
+			]}`))
+		case "/did:plc:alice", "/did:plc:bob", "/did:plc:carol":
+			did := r.URL.Path[1:]
+			fmt.Fprintf(w, `{"id": %q, "alsoKnownAs": ["at://%s.test"], "service": [{"id": "#atproto_pds", "type": "AtprotoPersonalDataServer", "serviceEndpoint": %q}]}`,
+				did, did[len("did:plc:"):], serverURL)
+		case "/xrpc/com.atproto.repo.getRecord":
+			switch query.Get("rkey") {
+			case "a":
+				fmt.Fprintf(w, `{"uri": "at://did:plc:alice/app.bsky.feed.post/a", "cid": "cida", "value": {"text": "first", "createdAt": "2025-01-01T00:00:00Z", "reply": {"root": {"uri": %q}, "parent": {"uri": %q}}}}`, root, root)
+			case "b":
+				fmt.Fprintf(w, `{"uri": "at://did:plc:bob/app.bsky.feed.post/b", "cid": "cidb", "value": {"text": "second", "createdAt": "2025-01-02T00:00:00Z", "reply": {"root": {"uri": %q}, "parent": {"uri": "at://did:plc:alice/app.bsky.feed.post/a"}}}}`, root)
+			default:
+				http.Error(w, `{"error": "RecordNotFound"}`, http.StatusBadRequest)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.PLCDirectory = server.URL
+
+	thread, err := client.GetCommentThread(context.Background(), root)
+	if err != nil {
+		t.Fatalf("Failed to get comment thread: %v", err)
+	}
+
+	if len(thread) != 2 {
+		t.Fatalf("Expected 2 top-level comments, got %d", len(thread))
+	}
+	var first *constellation.Comment
+	for _, comment := range thread {
+		if comment.Text == "first" {
+			first = comment
+		} else if !comment.Deleted {
+			t.Errorf("Expected the unparented top-level comment to be deleted, got %+v", comment)
+		}
+	}
+	if first == nil || first.Handle != "alice.test" {
+		t.Fatalf("Expected alice's comment at the top level, got %+v", thread)
+	}
+	if len(first.Replies) != 1 || first.Replies[0].Text != "second" || first.Replies[0].Handle != "bob.test" {
+		t.Errorf("Expected bob's reply nested under alice's comment, got %+v", first.Replies)
+	}
+}
+
+// newCyclicCommentServer serves two replies by alice that name each other as
+// parents, failing record fetches with status if it's set
+func newCyclicCommentServer(t *testing.T, status int) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/links":
+			w.Write([]byte(`{"total": 2, "linking_records": [
+				{"did": "did:plc:alice", "collection": "app.bsky.feed.post", "rkey": "x"},
+				{"did": "did:plc:alice", "collection": "app.bsky.feed.post", "rkey": "y"}
+			]}`))
+		case "/did:plc:alice":
+			fmt.Fprintf(w, `{"id": "did:plc:alice", "service": [{"id": "#atproto_pds", "serviceEndpoint": %q}]}`, server.URL)
+		case "/xrpc/com.atproto.repo.getRecord":
+			if status != 0 {
+				w.WriteHeader(status)
+				return
+			}
+			other := map[string]string{"x": "y", "y": "x"}[r.URL.Query().Get("rkey")]
+			fmt.Fprintf(w, `{"value": {"text": "loop", "reply": {"parent": {"uri": "at://did:plc:alice/app.bsky.feed.post/%s"}}}}`, other)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestGetCommentThreadCycle tests that replies forming a parent cycle are shown
+// at the top level instead of disappearing
+func TestGetCommentThreadCycle(t *testing.T) {
+	server := newCyclicCommentServer(t, 0)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.PLCDirectory = server.URL
+
+	thread, err := client.GetCommentThread(context.Background(), "at://did:plc:author/app.bsky.feed.post/root")
+	if err != nil {
+		t.Fatalf("Failed to get comment thread: %v", err)
+	}
+	if len(thread) != 2 || len(thread[0].Replies) != 0 || len(thread[1].Replies) != 0 {
+		t.Errorf("Expected both cyclic replies at the top level, got %+v", thread)
+	}
+}
+
+// TestGetCommentThreadHydrationError tests that failures other than a missing
+// record are returned instead of marking comments deleted
+func TestGetCommentThreadHydrationError(t *testing.T) {
+	server := newCyclicCommentServer(t, http.StatusServiceUnavailable)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.PLCDirectory = server.URL
+
+	_, err := client.GetCommentThread(context.Background(), "at://did:plc:author/app.bsky.feed.post/root")
+	var apiErr *constellation.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected the PDS error, got: %v", err)
+	}
+}
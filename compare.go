@@ -0,0 +1,118 @@
+package constellation
+
+import (
+	"context"
+	"fmt"
+)
+
+// CompareOptions controls Compare. Zero values compare counts only.
+type CompareOptions struct {
+	Records    bool // Also paginate both instances and diff the linking records
+	MaxRecords int  // Cap on records fetched per instance; see CompareResult.Partial
+}
+
+// CompareResult reports how two instances answered the same query
+type CompareResult struct {
+	Params     LinksParams
+	CountA     int
+	CountB     int
+	ErrA       error
+	ErrB       error
+	MissingInA []string // Records present only on instance B, as did/collection/rkey
+	MissingInB []string // Records present only on instance A, as did/collection/rkey
+
+	// Partial is set when MaxRecords cut off either instance's records. The
+	// record diff then covers only the range both instances fetched, so records
+	// past it may differ unreported.
+	Partial bool
+}
+
+// Consistent reports whether both instances succeeded and agree
+func (r CompareResult) Consistent() bool {
+	return r.ErrA == nil && r.ErrB == nil && r.CountA == r.CountB &&
+		len(r.MissingInA) == 0 && len(r.MissingInB) == 0
+}
+
+// String summarizes the comparison on one line
+func (r CompareResult) String() string {
+	switch {
+	case r.ErrA != nil || r.ErrB != nil:
+		return fmt.Sprintf("%s: error (a: %v, b: %v)", r.Params.Target, r.ErrA, r.ErrB)
+	case r.Consistent():
+		return fmt.Sprintf("%s: ok (%d)", r.Params.Target, r.CountA)
+	default:
+		return fmt.Sprintf("%s: count %d vs %d, %d missing in a, %d missing in b",
+			r.Params.Target, r.CountA, r.CountB, len(r.MissingInA), len(r.MissingInB))
+	}
+}
+
+// Compare runs the same queries against two instances, e.g. the public instance and
+// a self-hosted one, and reports count and record discrepancies for each query
+func Compare(ctx context.Context, a, b *Client, queries []LinksParams, opts CompareOptions) []CompareResult {
+	results := make([]CompareResult, 0, len(queries))
+	for _, params := range queries {
+		result := CompareResult{Params: params}
+
+		if count, err := a.GetLinksCountContext(ctx, params); err != nil {
+			result.ErrA = err
+		} else {
+			result.CountA = count.Total
+		}
+		if count, err := b.GetLinksCountContext(ctx, params); err != nil {
+			result.ErrB = err
+		} else {
+			result.CountB = count.Total
+		}
+
+		if opts.Records && result.ErrA == nil && result.ErrB == nil {
+			pageOpts := PaginateOptions{MaxRecords: opts.MaxRecords}
+			recordsA, errA := a.GetAllLinks(ctx, params, pageOpts)
+			recordsB, errB := b.GetAllLinks(ctx, params, pageOpts)
+			result.ErrA, result.ErrB = errA, errB
+			if errA == nil && errB == nil {
+				truncatedA := len(recordsA) < result.CountA
+				truncatedB := len(recordsB) < result.CountB
+				result.Partial = truncatedA || truncatedB
+				result.MissingInB = recordDifference(recordsA, recordsB, truncatedB)
+				result.MissingInA = recordDifference(recordsB, recordsA, truncatedA)
+			}
+		}
+
+		results = append(results, result)
+	}
+	return results
+}
+
+// recordKey identifies a linking record independent of instance
+func recordKey(record LinkRecord) string {
+	return record.DID + "/" + record.Collection + "/" + record.RKey
+}
+
+// recordDifference returns the keys of records in from that are absent in other.
+// If other was truncated, only records of from up to the last one other also
+// holds are considered; both instances list newest first, so later records may
+// simply lie past other's cutoff.
+func recordDifference(from, other []LinkRecord, otherTruncated bool) []string {
+	present := make(map[string]bool, len(other))
+	for _, record := range other {
+		present[recordKey(record)] = true
+	}
+
+	if otherTruncated {
+		end := 0
+		for i, record := range from {
+			if present[recordKey(record)] {
+				end = i + 1
+			}
+		}
+		from = from[:end]
+	}
+
+	var missing []string
+	for _, record := range from {
+		if key := recordKey(record); !present[key] {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,68 @@
+package constellation_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestCompare tests count and record discrepancy reporting between instances
+func TestCompare(t *testing.T) {
+	a := constellation.NewClientWithConfig(newPagedServer(t, 12).URL, 5*time.Second)
+	b := constellation.NewClientWithConfig(newPagedServer(t, 10).URL, 5*time.Second)
+
+	queries := []constellation.LinksParams{{Target: "did:plc:example"}}
+	results := constellation.Compare(context.Background(), a, b, queries, constellation.CompareOptions{Records: true})
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	result := results[0]
+	if result.Consistent() {
+		t.Error("Expected instances to differ")
+	}
+	if len(result.MissingInB) != 2 || len(result.MissingInA) != 0 {
+		t.Errorf("Expected 2 records missing in b, got %+v", result)
+	}
+}
+
+// newRecordListServer serves dids as one page of like records, newest first
+func newRecordListServer(t *testing.T, dids ...string) *httptest.Server {
+	t.Helper()
+	records := make([]constellation.LinkRecord, len(dids))
+	for i, did := range dids {
+		records[i] = constellation.LinkRecord{DID: did, Collection: "app.bsky.feed.like", RKey: "1"}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(constellation.LinksResponse{Total: len(records), LinkingRecords: records})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestCompareMaxRecords tests that truncated record lists are only diffed over
+// the range both instances fetched
+func TestCompareMaxRecords(t *testing.T) {
+	dids := []string{"did:plc:1", "did:plc:2", "did:plc:3", "did:plc:4", "did:plc:5", "did:plc:6"}
+	a := constellation.NewClientWithConfig(newRecordListServer(t, append([]string{"did:plc:new"}, dids...)...).URL, 5*time.Second)
+	b := constellation.NewClientWithConfig(newRecordListServer(t, dids...).URL, 5*time.Second)
+
+	queries := []constellation.LinksParams{{Target: "did:plc:example"}}
+	results := constellation.Compare(context.Background(), a, b, queries, constellation.CompareOptions{Records: true, MaxRecords: 4})
+
+	result := results[0]
+	if !result.Partial {
+		t.Error("Expected a partial comparison")
+	}
+	if len(result.MissingInB) != 1 || result.MissingInB[0] != "did:plc:new/app.bsky.feed.like/1" {
+		t.Errorf("Expected only the new record missing in b, got %v", result.MissingInB)
+	}
+	if len(result.MissingInA) != 0 {
+		t.Errorf("Expected records past a's cutoff to go unreported, got %v", result.MissingInA)
+	}
+}
@@ -0,0 +1,19 @@
+package constellation
+
+// The package builds with Go 1.20, which lacks the min and max builtins
+
+// minOf returns the smaller of a and b
+func minOf[T number](a, b T) T {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// maxOf returns the larger of a and b
+func maxOf[T number](a, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
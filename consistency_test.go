@@ -0,0 +1,26 @@
+package constellation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestVerifyConsistency tests a consistent pagination against the count
+func TestVerifyConsistency(t *testing.T) {
+	server := newPagedServer(t, 25)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+
+	report, err := client.VerifyConsistency(context.Background(), constellation.LinksParams{Target: "did:plc:example", Limit: 10})
+	if err != nil {
+		t.Fatalf("Failed to verify consistency: %v", err)
+	}
+	if !report.Consistent() {
+		t.Errorf("Expected consistent report, got: %s", report)
+	}
+	if report.Paginated != 25 || report.Count != 25 {
+		t.Errorf("Expected 25 paginated and counted, got: %s", report)
+	}
+}
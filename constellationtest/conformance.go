@@ -0,0 +1,146 @@
+package constellationtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestKVStore checks that store behaves as a constellation.KVStore must, so
+// custom backends can run the same checks as the built-in ones:
+//
+//	func TestMyStore(t *testing.T) {
+//		constellationtest.TestKVStore(t, newMyStore(t))
+//	}
+//
+// It writes keys prefixed with "constellationtest/" and deletes them afterwards.
+func TestKVStore(t *testing.T, store constellation.KVStore) {
+	t.Helper()
+	ctx := context.Background()
+	key := func(name string) string {
+		return "constellationtest/" + name
+	}
+
+	t.Run("Missing", func(t *testing.T) {
+		if _, err := store.Get(ctx, key("missing")); !errors.Is(err, constellation.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for a missing key, got %v", err)
+		}
+		if err := store.Delete(ctx, key("missing")); err != nil {
+			t.Errorf("Expected deleting a missing key to succeed, got %v", err)
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		values := map[string][]byte{
+			"plain":  []byte("value"),
+			"empty":  {},
+			"binary": {0, 1, '\r', '\n', 0xff},
+			"at://did:plc:a/app.bsky.feed.post/1 ünicode": []byte("keys are opaque"),
+		}
+		for name, value := range values {
+			if err := store.Put(ctx, key(name), value); err != nil {
+				t.Fatalf("Failed to put %q: %v", name, err)
+			}
+			got, err := store.Get(ctx, key(name))
+			if err != nil {
+				t.Fatalf("Failed to get %q: %v", name, err)
+			}
+			if !bytes.Equal(got, value) {
+				t.Errorf("Expected %q for %q, got %q", value, name, got)
+			}
+			if err := store.Delete(ctx, key(name)); err != nil {
+				t.Fatalf("Failed to delete %q: %v", name, err)
+			}
+			if _, err := store.Get(ctx, key(name)); !errors.Is(err, constellation.ErrNotFound) {
+				t.Errorf("Expected ErrNotFound after deleting %q, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("Overwrite", func(t *testing.T) {
+		defer store.Delete(ctx, key("overwrite"))
+		store.Put(ctx, key("overwrite"), []byte("first, and longer"))
+		if err := store.Put(ctx, key("overwrite"), []byte("second")); err != nil {
+			t.Fatalf("Failed to overwrite: %v", err)
+		}
+		if got, err := store.Get(ctx, key("overwrite")); err != nil || string(got) != "second" {
+			t.Errorf("Expected second, got %q, %v", got, err)
+		}
+	})
+
+	t.Run("Isolation", func(t *testing.T) {
+		defer store.Delete(ctx, key("isolated"))
+		value := []byte("original")
+		store.Put(ctx, key("isolated"), value)
+		value[0] = 'X'
+		got, err := store.Get(ctx, key("isolated"))
+		if err != nil {
+			t.Fatalf("Failed to get: %v", err)
+		}
+		if string(got) != "original" {
+			t.Errorf("Expected the store to copy values on Put, got %q", got)
+		}
+		got[0] = 'Y'
+		if again, _ := store.Get(ctx, key("isolated")); string(again) != "original" {
+			t.Errorf("Expected the store to copy values on Get, got %q", again)
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 16; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				name := key(fmt.Sprintf("concurrent/%d", i))
+				defer store.Delete(ctx, name)
+				want := fmt.Sprintf("value %d", i)
+				if err := store.Put(ctx, name, []byte(want)); err != nil {
+					t.Errorf("Failed to put %s: %v", name, err)
+					return
+				}
+				if got, err := store.Get(ctx, name); err != nil || string(got) != want {
+					t.Errorf("Expected %q for %s, got %q, %v", want, name, got, err)
+				}
+			}(i)
+		}
+		wg.Wait()
+	})
+}
+
+// TestCursorStore checks that store behaves as a constellation.CursorStore must.
+// It saves cursors under keys prefixed with "constellationtest/" and deletes them
+// afterwards.
+func TestCursorStore(t *testing.T, store constellation.CursorStore) {
+	t.Helper()
+	ctx := context.Background()
+	const key = "constellationtest/cursor"
+
+	if cursor, err := store.LoadCursor(ctx, key); err != nil || cursor != "" {
+		t.Fatalf("Expected no cursor for a new key, got %q, %v", cursor, err)
+	}
+	if err := store.DeleteCursor(ctx, key); err != nil {
+		t.Errorf("Expected deleting a missing cursor to succeed, got %v", err)
+	}
+
+	for _, cursor := range []string{"abc", "3lgwdn7vd722r::did:plc:a"} {
+		if err := store.SaveCursor(ctx, key, cursor); err != nil {
+			t.Fatalf("Failed to save cursor: %v", err)
+		}
+		if got, err := store.LoadCursor(ctx, key); err != nil || got != cursor {
+			t.Errorf("Expected cursor %q, got %q, %v", cursor, got, err)
+		}
+	}
+
+	if err := store.DeleteCursor(ctx, key); err != nil {
+		t.Fatalf("Failed to delete cursor: %v", err)
+	}
+	if cursor, err := store.LoadCursor(ctx, key); err != nil || cursor != "" {
+		t.Errorf("Expected no cursor after deleting, got %q, %v", cursor, err)
+	}
+}
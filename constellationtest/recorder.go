@@ -0,0 +1,170 @@
+// Package constellationtest provides helpers for testing code built on the
+// constellation client.
+package constellationtest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// Request is an outgoing request captured by a RequestRecorder
+type Request struct {
+	Method   string
+	Endpoint string // URL path, e.g. "/links/count"
+	Params   url.Values
+}
+
+// Target returns the request's target parameter
+func (r Request) Target() string {
+	return r.Params.Get("target")
+}
+
+// Collection returns the request's collection parameter
+func (r Request) Collection() string {
+	return r.Params.Get("collection")
+}
+
+// RequestRecorder is an http.RoundTripper that captures every request a client
+// makes, so tests can assert on the queries their integration logic issues.
+//
+//	recorder := constellationtest.NewRequestRecorder(handler)
+//	recorder.Install(client)
+//	runCodeUnderTest(client)
+//	recorder.AssertQueried(t, postURI, "app.bsky.feed.like")
+type RequestRecorder struct {
+	// Handler, if set, answers requests in-process instead of sending them.
+	// Otherwise requests are forwarded to Next.
+	Handler http.Handler
+	Next    http.RoundTripper // Defaults to http.DefaultTransport
+
+	mu       sync.Mutex
+	requests []Request
+}
+
+// NewRequestRecorder creates a recorder answering requests with handler. A nil
+// handler forwards requests to the network.
+func NewRequestRecorder(handler http.Handler) *RequestRecorder {
+	return &RequestRecorder{Handler: handler}
+}
+
+// Install routes the client's requests through the recorder
+func (r *RequestRecorder) Install(c *constellation.Client) {
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{}
+	}
+	client := *c.HTTPClient
+	if client.Transport != nil && r.Next == nil && r.Handler == nil {
+		r.Next = client.Transport
+	}
+	client.Transport = r
+	c.HTTPClient = &client
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *RequestRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	r.requests = append(r.requests, Request{
+		Method:   req.Method,
+		Endpoint: req.URL.Path,
+		Params:   req.URL.Query(),
+	})
+	r.mu.Unlock()
+
+	if r.Handler != nil {
+		rec := httptest.NewRecorder()
+		r.Handler.ServeHTTP(rec, req)
+		resp := rec.Result()
+		resp.Request = req
+		return resp, nil
+	}
+
+	next := r.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req)
+}
+
+// Requests returns the captured requests in order
+func (r *RequestRecorder) Requests() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Request(nil), r.requests...)
+}
+
+// Reset discards the captured requests
+func (r *RequestRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests = nil
+}
+
+// Queried returns the captured requests for target, restricted to collection
+// unless it is empty
+func (r *RequestRecorder) Queried(target, collection string) []Request {
+	var matched []Request
+	for _, req := range r.Requests() {
+		if req.Target() == target && (collection == "" || req.Collection() == collection) {
+			matched = append(matched, req)
+		}
+	}
+	return matched
+}
+
+// AssertQueried fails the test unless a request was made for target and collection.
+// An empty collection matches any collection.
+func (r *RequestRecorder) AssertQueried(t testing.TB, target, collection string) {
+	t.Helper()
+	if len(r.Queried(target, collection)) == 0 {
+		t.Errorf("expected a query for target %q collection %q; got:\n%s", target, collection, r.summary())
+	}
+}
+
+// AssertNotQueried fails the test if a request was made for target and collection
+func (r *RequestRecorder) AssertNotQueried(t testing.TB, target, collection string) {
+	t.Helper()
+	if matched := r.Queried(target, collection); len(matched) > 0 {
+		t.Errorf("expected no query for target %q collection %q; got %d", target, collection, len(matched))
+	}
+}
+
+// AssertEndpoint fails the test unless a request was made to endpoint
+func (r *RequestRecorder) AssertEndpoint(t testing.TB, endpoint string) {
+	t.Helper()
+	for _, req := range r.Requests() {
+		if req.Endpoint == endpoint {
+			return
+		}
+	}
+	t.Errorf("expected a request to %s; got:\n%s", endpoint, r.summary())
+}
+
+// AssertCount fails the test unless exactly n requests were made
+func (r *RequestRecorder) AssertCount(t testing.TB, n int) {
+	t.Helper()
+	if got := len(r.Requests()); got != n {
+		t.Errorf("expected %d requests, got %d:\n%s", n, got, r.summary())
+	}
+}
+
+// summary lists the captured requests for failure messages
+func (r *RequestRecorder) summary() string {
+	requests := r.Requests()
+	if len(requests) == 0 {
+		return "  (no requests)"
+	}
+	lines := make([]string, len(requests))
+	for i, req := range requests {
+		lines[i] = fmt.Sprintf("  %s %s?%s", req.Method, req.Endpoint, req.Params.Encode())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,37 @@
+package constellationtest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+	"github.com/tanner-caffrey/constellation-go/constellationtest"
+)
+
+// TestRequestRecorder tests capturing and asserting on client requests
+func TestRequestRecorder(t *testing.T) {
+	recorder := constellationtest.NewRequestRecorder(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total": 7}`))
+	}))
+	client := constellation.NewClient()
+	recorder.Install(client)
+
+	post := "at://did:plc:creator/app.bsky.feed.post/1"
+	count, err := client.GetLinksCountContext(context.Background(), constellation.LinksParams{
+		Target:     post,
+		Collection: "app.bsky.feed.like",
+		Path:       ".subject.uri",
+	})
+	if err != nil || count.Total != 7 {
+		t.Fatalf("Expected stubbed count of 7, got %v, %v", count, err)
+	}
+
+	recorder.AssertQueried(t, post, "app.bsky.feed.like")
+	recorder.AssertNotQueried(t, post, "app.bsky.feed.repost")
+	recorder.AssertEndpoint(t, "/links/count")
+	recorder.AssertCount(t, 1)
+
+	recorder.Reset()
+	recorder.AssertCount(t, 0)
+}
@@ -0,0 +1,159 @@
+package constellationtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// DefaultPageSize is the page size the fake Server uses when a request has no limit
+const DefaultPageSize = 16
+
+// Server is an in-memory Constellation instance for tests and examples. It serves
+// the links, count, distinct DID, and grouped count endpoints from links added
+// with AddLink, paging in insertion order with numeric cursors:
+//
+//	server := constellationtest.NewServer()
+//	server.AddLink(postURI, "app.bsky.feed.like", ".subject.uri", "did:plc:alice")
+//	client := server.Client()
+type Server struct {
+	mu    sync.Mutex
+	links []serverLink
+}
+
+// serverLink is one link held by a Server
+type serverLink struct {
+	target, collection, path string
+	record                   constellation.LinkRecord
+}
+
+// NewServer creates an empty fake instance
+func NewServer() *Server {
+	return &Server{}
+}
+
+// AddLink adds a link to target from a record in collection authored by each of
+// dids, with the target at path. Record keys are numbered in insertion order.
+func (s *Server) AddLink(target, collection, path string, dids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, did := range dids {
+		rkey := strconv.Itoa(len(s.links) + 1)
+		s.links = append(s.links, serverLink{
+			target:     target,
+			collection: collection,
+			path:       path,
+			record: constellation.LinkRecord{
+				DID:        did,
+				Collection: collection,
+				RKey:       rkey,
+				URI:        "at://" + did + "/" + collection + "/" + rkey,
+			},
+		})
+	}
+}
+
+// Client returns a client whose requests are answered by the server in-process
+func (s *Server) Client() *constellation.Client {
+	client := constellation.NewClient()
+	NewRequestRecorder(s).Install(client)
+	return client
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("target") == "" {
+		http.Error(w, "target parameter is required", http.StatusBadRequest)
+		return
+	}
+	matches := s.matching(query.Get("target"), query.Get("collection"), query.Get("path"))
+
+	switch r.URL.Path {
+	case "/links":
+		records := make([]constellation.LinkRecord, len(matches))
+		for i, link := range matches {
+			records[i] = link.record
+		}
+		page, cursor := pageOf(records, query)
+		writeJSON(w, constellation.LinksResponse{Total: len(records), LinkingRecords: page, Cursor: cursor})
+	case "/links/count":
+		writeJSON(w, constellation.CountResponse{Total: len(matches)})
+	case "/links/distinct-dids":
+		dids := distinctDIDs(matches)
+		page, cursor := pageOf(dids, query)
+		writeJSON(w, constellation.DistinctDIDsResponse{Total: len(dids), DIDs: page, Cursor: cursor})
+	case "/links/count/distinct-dids":
+		writeJSON(w, constellation.CountResponse{Total: len(distinctDIDs(matches))})
+	case "/links/all/count":
+		counts := constellation.LinkCounts{}
+		for _, link := range matches {
+			if counts[link.collection] == nil {
+				counts[link.collection] = map[string]int{}
+			}
+			counts[link.collection][link.path]++
+		}
+		writeJSON(w, map[string]any{"links": counts})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// matching returns the links to target, restricted to collection and path when
+// they are set
+func (s *Server) matching(target, collection, path string) []serverLink {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matches []serverLink
+	for _, link := range s.links {
+		if link.target == target &&
+			(collection == "" || link.collection == collection) &&
+			(path == "" || link.path == path) {
+			matches = append(matches, link)
+		}
+	}
+	return matches
+}
+
+// distinctDIDs returns the authors of links in first-seen order
+func distinctDIDs(links []serverLink) []string {
+	seen := make(map[string]bool)
+	var dids []string
+	for _, link := range links {
+		if !seen[link.record.DID] {
+			seen[link.record.DID] = true
+			dids = append(dids, link.record.DID)
+		}
+	}
+	return dids
+}
+
+// pageOf returns the page of items selected by the query's cursor and limit,
+// and the cursor for the next page, empty on the last
+func pageOf[T any](items []T, query url.Values) ([]T, string) {
+	start, _ := strconv.Atoi(query.Get("cursor"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if start < 0 || start > len(items) {
+		start = len(items)
+	}
+	end := start + limit
+	if end >= len(items) {
+		return items[start:], ""
+	}
+	return items[start:end], strconv.Itoa(end)
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,92 @@
+package constellation
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// CursorStore persists pagination cursors so long-running exports can resume
+// after a crash or restart. Implementations must be safe for concurrent use.
+// File and SQL stores are built in, and KVCursorStore adapts any KVStore. A
+// bbolt-backed store is in the cursorstore/bbolt module, kept separate so this
+// module depends only on the standard library.
+type CursorStore interface {
+	// LoadCursor returns the cursor saved under key, or "" if there is none
+	LoadCursor(ctx context.Context, key string) (string, error)
+	// SaveCursor saves cursor under key, replacing any previous cursor
+	SaveCursor(ctx context.Context, key, cursor string) error
+	// DeleteCursor removes the cursor saved under key, if any
+	DeleteCursor(ctx context.Context, key string) error
+}
+
+// Checkpoint configures periodic cursor checkpointing for an auto-paginating
+// operation. Pagination resumes from the saved cursor, and the checkpoint is
+// deleted once the results are exhausted. Records on the page being processed
+// when the process stopped are delivered again on resume.
+type Checkpoint struct {
+	Store CursorStore
+	Key   string // Identifies the operation; see CheckpointKey
+	Every int    // Save after this many pages; defaults to 1
+}
+
+// CheckpointKey returns a checkpoint key identifying the query described by params
+func CheckpointKey(params LinksParams) string {
+	return paramsFingerprint(params)
+}
+
+// resume returns the saved cursor, or cursor if none is saved
+func (cp *Checkpoint) resume(ctx context.Context, cursor string) (string, error) {
+	if cp == nil {
+		return cursor, nil
+	}
+	saved, err := cp.Store.LoadCursor(ctx, cp.Key)
+	if err != nil {
+		return "", fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	if saved == "" {
+		return cursor, nil
+	}
+	return saved, nil
+}
+
+// save records the cursor of the next page after the given number of pages
+func (cp *Checkpoint) save(ctx context.Context, pages int, cursor string) error {
+	if cp == nil {
+		return nil
+	}
+	if cursor == "" {
+		if err := cp.Store.DeleteCursor(ctx, cp.Key); err != nil {
+			return fmt.Errorf("failed to clear checkpoint: %w", err)
+		}
+		return nil
+	}
+	if every := maxOf(cp.Every, 1); pages%every != 0 {
+		return nil
+	}
+	if err := cp.Store.SaveCursor(ctx, cp.Key, cursor); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// NewFileCursorStore creates a cursor store keeping each cursor in its own file
+// within dir, creating the directory if needed. It's a KVCursorStore over a FileKV.
+func NewFileCursorStore(dir string) (*KVCursorStore, error) {
+	store, err := NewFileKV(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cursor store: %w", err)
+	}
+	return &KVCursorStore{Store: store}, nil
+}
+
+// NewSQLCursorStore creates a cursor store keeping cursors in a SQLite database
+// table and ensures the table exists. It's a KVCursorStore over a SQLKV, and the
+// caller opens the database with the SQLite driver of their choice.
+func NewSQLCursorStore(ctx context.Context, db *sql.DB) (*KVCursorStore, error) {
+	store, err := NewSQLKV(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cursor store: %w", err)
+	}
+	return &KVCursorStore{Store: store}, nil
+}
@@ -0,0 +1,113 @@
+// Package bbolt provides a bbolt-backed constellation.KVStore and CursorStore,
+// for resumable exports that want a single embedded database file rather than
+// a directory of cursor files or a SQL server:
+//
+//	store, err := bbolt.Open("checkpoints.db")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer store.Close()
+//	records, err := client.GetAllLinks(ctx, params, constellation.PaginateOptions{
+//		Checkpoint: &constellation.Checkpoint{Store: store.CursorStore(), Key: constellation.CheckpointKey(params)},
+//	})
+//
+// This package is its own module so the client doesn't depend on bbolt.
+package bbolt
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultBucket is the bucket values are kept in unless Store.Bucket is set
+const DefaultBucket = "constellation"
+
+// Store is a constellation.KVStore keeping values in a bucket of a bbolt database
+type Store struct {
+	DB     *bolt.DB
+	Bucket []byte
+}
+
+// Open opens (or creates) the bbolt database at path and ensures the default
+// bucket exists. bbolt locks the file, so only one process can open it at a time;
+// Open waits up to a second for the lock before failing.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bbolt database: %w", err)
+	}
+	store, err := New(db, DefaultBucket)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// New creates a store in bucket of an open database, creating the bucket if needed
+func New(db *bolt.DB, bucket string) (*Store, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bucket: %w", err)
+	}
+	return &Store{DB: db, Bucket: []byte(bucket)}, nil
+}
+
+// CursorStore returns a cursor store keeping cursors in s
+func (s *Store) CursorStore() *constellation.KVCursorStore {
+	return &constellation.KVCursorStore{Store: s}
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.DB.Close()
+}
+
+// Get implements constellation.KVStore
+func (s *Store) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var value []byte
+	err := s.DB.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(s.Bucket).Get([]byte(key))
+		if data == nil {
+			return constellation.ErrNotFound
+		}
+		// Values are only valid for the life of the transaction
+		value = append([]byte{}, data...)
+		return nil
+	})
+	return value, err
+}
+
+// Put implements constellation.KVStore
+func (s *Store) Put(ctx context.Context, key string, value []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	return s.DB.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.Bucket).Put([]byte(key), value)
+	})
+}
+
+// Delete implements constellation.KVStore
+func (s *Store) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.DB.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.Bucket).Delete([]byte(key))
+	})
+}
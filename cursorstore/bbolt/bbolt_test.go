@@ -0,0 +1,39 @@
+package bbolt_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go/constellationtest"
+	"github.com/tanner-caffrey/constellation-go/cursorstore/bbolt"
+)
+
+// TestStore runs the store and cursor store conformance checks, and checks that
+// cursors survive reopening the database
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints.db")
+	store, err := bbolt.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	constellationtest.TestKVStore(t, store)
+	constellationtest.TestCursorStore(t, store.CursorStore())
+
+	ctx := context.Background()
+	if err := store.CursorStore().SaveCursor(ctx, "export", "c1"); err != nil {
+		t.Fatalf("Failed to save cursor: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	reopened, err := bbolt.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen: %v", err)
+	}
+	defer reopened.Close()
+	if cursor, err := reopened.CursorStore().LoadCursor(ctx, "export"); err != nil || cursor != "c1" {
+		t.Errorf("Expected the cursor to survive reopening, got %q, %v", cursor, err)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/tanner-caffrey/constellation-go/cursorstore/bbolt
+
+go 1.22
+
+require (
+	github.com/tanner-caffrey/constellation-go v0.1.0
+	go.etcd.io/bbolt v1.3.11
+)
+
+require golang.org/x/sys v0.4.0 // indirect
@@ -0,0 +1,85 @@
+package constellation_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestFileCursorStore tests saving, loading, and deleting cursors
+func TestFileCursorStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := constellation.NewFileCursorStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	if cursor, err := store.LoadCursor(ctx, "export"); err != nil || cursor != "" {
+		t.Fatalf("Expected no cursor, got %q, %v", cursor, err)
+	}
+	if err := store.SaveCursor(ctx, "export", "abc"); err != nil {
+		t.Fatalf("Failed to save cursor: %v", err)
+	}
+	if cursor, _ := store.LoadCursor(ctx, "export"); cursor != "abc" {
+		t.Errorf("Expected cursor abc, got %q", cursor)
+	}
+	if err := store.DeleteCursor(ctx, "export"); err != nil {
+		t.Fatalf("Failed to delete cursor: %v", err)
+	}
+	if cursor, _ := store.LoadCursor(ctx, "export"); cursor != "" {
+		t.Errorf("Expected deleted cursor, got %q", cursor)
+	}
+}
+
+// TestCheckpointResume tests resuming an interrupted pagination from its checkpoint
+func TestCheckpointResume(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Write([]byte(`{"total": 3, "linking_records": [{"did": "did:plc:a"}], "cursor": "p2"}`))
+		case "p2":
+			w.Write([]byte(`{"total": 3, "linking_records": [{"did": "did:plc:b"}], "cursor": "p3"}`))
+		default:
+			fmt.Fprint(w, `{"total": 3, "linking_records": [{"did": "did:plc:c"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example"}
+	store, err := constellation.NewFileCursorStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	checkpoint := &constellation.Checkpoint{Store: store, Key: constellation.CheckpointKey(params)}
+
+	// Simulate a crash while processing the second page
+	crash := errors.New("crash")
+	err = client.GetLinksEach(ctx, params, constellation.PaginateOptions{Checkpoint: checkpoint}, func(record constellation.LinkRecord) error {
+		if record.DID == "did:plc:b" {
+			return crash
+		}
+		return nil
+	})
+	if !errors.Is(err, crash) {
+		t.Fatalf("Expected simulated crash, got %v", err)
+	}
+
+	records, err := client.GetAllLinks(ctx, params, constellation.PaginateOptions{Checkpoint: checkpoint})
+	if err != nil {
+		t.Fatalf("Failed to resume: %v", err)
+	}
+	if len(records) != 2 || records[0].DID != "did:plc:b" {
+		t.Errorf("Expected to resume at the second page, got %+v", records)
+	}
+	if cursor, _ := store.LoadCursor(ctx, checkpoint.Key); cursor != "" {
+		t.Errorf("Expected checkpoint cleared after completion, got %q", cursor)
+	}
+}
@@ -0,0 +1,110 @@
+package constellation
+
+import (
+	"container/list"
+	"hash/fnv"
+	"math"
+)
+
+// Deduper remembers keys to drop duplicate records or DIDs across pages. Index
+// updates during long paginations can return the same item on two pages.
+type Deduper interface {
+	// Seen records key and reports whether it was seen before
+	Seen(key string) bool
+}
+
+// ExactDeduper remembers up to a fixed number of keys exactly, forgetting the
+// oldest once full
+type ExactDeduper struct {
+	max   int
+	keys  map[string]*list.Element
+	order *list.List
+}
+
+// NewExactDeduper creates a deduper remembering at most maxEntries keys.
+// A maxEntries of zero or less means no bound.
+func NewExactDeduper(maxEntries int) *ExactDeduper {
+	return &ExactDeduper{
+		max:   maxEntries,
+		keys:  make(map[string]*list.Element),
+		order: list.New(),
+	}
+}
+
+// Seen records key and reports whether it was seen before
+func (d *ExactDeduper) Seen(key string) bool {
+	if _, ok := d.keys[key]; ok {
+		return true
+	}
+
+	d.keys[key] = d.order.PushBack(key)
+	if d.max > 0 && d.order.Len() > d.max {
+		oldest := d.order.Front()
+		d.order.Remove(oldest)
+		delete(d.keys, oldest.Value.(string))
+	}
+	return false
+}
+
+// BloomDeduper uses a Bloom filter for constant memory on huge result sets. It
+// never misses a duplicate but may drop a small fraction of unique items as
+// false positives.
+type BloomDeduper struct {
+	bits   []uint64
+	size   uint64
+	hashes int
+}
+
+// NewBloomDeduper sizes a Bloom filter for the expected number of items and the
+// acceptable false positive rate, e.g. 0.001
+func NewBloomDeduper(expectedItems int, falsePositiveRate float64) *BloomDeduper {
+	if expectedItems < 1 {
+		expectedItems = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.001
+	}
+
+	size := uint64(math.Ceil(-float64(expectedItems) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	hashes := int(math.Round(float64(size) / float64(expectedItems) * math.Ln2))
+	if hashes < 1 {
+		hashes = 1
+	}
+
+	return &BloomDeduper{
+		bits:   make([]uint64, (size+63)/64),
+		size:   size,
+		hashes: hashes,
+	}
+}
+
+// Seen records key and reports whether it was probably seen before
+func (d *BloomDeduper) Seen(key string) bool {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h1 := h.Sum64()
+	h2 := h1>>33 | h1<<31 | 1
+
+	seen := true
+	for i := 0; i < d.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % d.size
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if d.bits[word]&mask == 0 {
+			seen = false
+			d.bits[word] |= mask
+		}
+	}
+	return seen
+}
+
+// dedupeKey returns the identity of a paginated item
+func dedupeKey(item any) string {
+	switch v := item.(type) {
+	case LinkRecord:
+		return recordKey(v)
+	case string:
+		return v
+	default:
+		return ""
+	}
+}
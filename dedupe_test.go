@@ -0,0 +1,55 @@
+package constellation_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestDedupe tests that records repeated across pages are dropped
+func TestDedupe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The second page repeats the last record of the first
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"linking_records": [{"did": "did:plc:a", "rkey": "1"}, {"did": "did:plc:b", "rkey": "2"}], "cursor": "next"}`))
+			return
+		}
+		w.Write([]byte(`{"linking_records": [{"did": "did:plc:b", "rkey": "2"}, {"did": "did:plc:c", "rkey": "3"}]}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example"}
+
+	for name, deduper := range map[string]constellation.Deduper{
+		"exact": constellation.NewExactDeduper(100),
+		"bloom": constellation.NewBloomDeduper(1000, 0.001),
+	} {
+		records, err := client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{Dedupe: deduper})
+		if err != nil {
+			t.Fatalf("%s: failed to get all links: %v", name, err)
+		}
+		if len(records) != 3 {
+			t.Errorf("%s: expected 3 unique records, got %d", name, len(records))
+		}
+	}
+}
+
+// TestExactDeduperBound tests that the exact deduper forgets the oldest keys
+func TestExactDeduperBound(t *testing.T) {
+	deduper := constellation.NewExactDeduper(2)
+	for i := 0; i < 3; i++ {
+		deduper.Seen(fmt.Sprint(i))
+	}
+	if deduper.Seen("0") {
+		t.Error("Expected oldest key to be forgotten")
+	}
+	if !deduper.Seen("2") {
+		t.Error("Expected recent key to be remembered")
+	}
+}
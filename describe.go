@@ -0,0 +1,91 @@
+package constellation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxCursorLen is the number of cursor characters kept in summaries
+const maxCursorLen = 8
+
+// shortCursor abbreviates an opaque cursor for summaries
+func shortCursor(cursor string) string {
+	if len(cursor) <= maxCursorLen {
+		return cursor
+	}
+	return cursor[:maxCursorLen] + "…"
+}
+
+// String summarizes the query on one line, abbreviating the cursor and listing
+// only the names of extra parameters. Use Redactor.Params to redact identifiers.
+func (p LinksParams) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "target=%s", p.Target)
+	if p.Collection != "" {
+		fmt.Fprintf(&b, " collection=%s", p.Collection)
+	}
+	if p.Path != "" {
+		fmt.Fprintf(&b, " path=%s", p.Path)
+	}
+	if p.Limit > 0 {
+		fmt.Fprintf(&b, " limit=%d", p.Limit)
+	}
+	if p.Cursor != "" {
+		fmt.Fprintf(&b, " cursor=%s", shortCursor(p.Cursor))
+	}
+	if p.Offset > 0 {
+		fmt.Fprintf(&b, " offset=%d", p.Offset)
+	}
+	if p.FromDID != "" {
+		fmt.Fprintf(&b, " did=%s", p.FromDID)
+	}
+	if !p.Since.IsZero() {
+		fmt.Fprintf(&b, " since=%s", p.Since.UTC().Format(time.RFC3339))
+	}
+	if len(p.Extra) > 0 {
+		keys := make([]string, 0, len(p.Extra))
+		for key := range p.Extra {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(&b, " extra=%s", strings.Join(keys, ","))
+	}
+	return b.String()
+}
+
+// String identifies the record by URI without its value, which can be large
+func (r LinkRecord) String() string {
+	uri := r.RecordURI()
+	if r.IndexedAt == "" {
+		return uri
+	}
+	return uri + " indexed " + r.IndexedAt
+}
+
+// String summarizes the page without listing its records
+func (r *LinksResponse) String() string {
+	s := fmt.Sprintf("%d of %d records", len(r.LinkingRecords), r.Total)
+	if r.Cursor != "" {
+		s += ", cursor=" + shortCursor(r.Cursor)
+	}
+	return s
+}
+
+// String summarizes the page without listing its DIDs
+func (r *DistinctDIDsResponse) String() string {
+	s := fmt.Sprintf("%d of %d DIDs", len(r.DIDs), r.Total)
+	if r.Cursor != "" {
+		s += ", cursor=" + shortCursor(r.Cursor)
+	}
+	return s
+}
+
+// String reports the total, noting when it's a lower bound
+func (r *CountResponse) String() string {
+	if r.LowerBound {
+		return fmt.Sprintf("total>=%d", r.Total)
+	}
+	return fmt.Sprintf("total=%d", r.Total)
+}
@@ -0,0 +1,53 @@
+package constellation_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestLinksParamsString tests the one-line query summary
+func TestLinksParamsString(t *testing.T) {
+	params := constellation.LinksParams{
+		Target:     "at://did:plc:a/app.bsky.feed.post/1",
+		Collection: constellation.CollectionLike,
+		Path:       constellation.PathSubjectURI,
+		Limit:      100,
+		Cursor:     "0123456789abcdef",
+		Since:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Extra:      url.Values{"b": {"1"}, "a": {"2"}},
+	}
+	want := "target=at://did:plc:a/app.bsky.feed.post/1 collection=app.bsky.feed.like path=.subject.uri limit=100 cursor=01234567… since=2026-01-02T03:04:05Z extra=a,b"
+	if got := params.String(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+// TestResponseStrings tests that summaries omit record values and DIDs
+func TestResponseStrings(t *testing.T) {
+	record := constellation.LinkRecord{
+		DID: "did:plc:a", Collection: constellation.CollectionLike, RKey: "3l",
+		Value: map[string]any{"secret": strings.Repeat("x", 1000)},
+	}
+	if got := record.String(); got != "at://did:plc:a/app.bsky.feed.like/3l" {
+		t.Errorf("Unexpected record summary %q", got)
+	}
+
+	links := &constellation.LinksResponse{Total: 40, LinkingRecords: []constellation.LinkRecord{record}, Cursor: "abc"}
+	if got := links.String(); got != "1 of 40 records, cursor=abc" {
+		t.Errorf("Unexpected links summary %q", got)
+	}
+
+	dids := &constellation.DistinctDIDsResponse{Total: 2, DIDs: []string{"did:plc:a", "did:plc:b"}}
+	if got := dids.String(); got != "2 of 2 DIDs" {
+		t.Errorf("Unexpected DIDs summary %q", got)
+	}
+
+	count := &constellation.CountResponse{Total: 7, LowerBound: true}
+	if got := count.String(); got != "total>=7" {
+		t.Errorf("Unexpected count summary %q", got)
+	}
+}
@@ -0,0 +1,94 @@
+package constellation
+
+import (
+	"context"
+	"sort"
+)
+
+// DIDSet is a set of DIDs
+type DIDSet map[string]struct{}
+
+// NewDIDSet creates a set containing dids
+func NewDIDSet(dids ...string) DIDSet {
+	set := make(DIDSet, len(dids))
+	for _, did := range dids {
+		set.Add(did)
+	}
+	return set
+}
+
+// Add adds did to the set
+func (s DIDSet) Add(did string) {
+	s[did] = struct{}{}
+}
+
+// Contains reports whether did is in the set
+func (s DIDSet) Contains(did string) bool {
+	_, ok := s[did]
+	return ok
+}
+
+// Len returns the number of DIDs in the set
+func (s DIDSet) Len() int {
+	return len(s)
+}
+
+// Union returns the DIDs in either set
+func (s DIDSet) Union(other DIDSet) DIDSet {
+	result := make(DIDSet, len(s)+len(other))
+	for did := range s {
+		result.Add(did)
+	}
+	for did := range other {
+		result.Add(did)
+	}
+	return result
+}
+
+// Intersect returns the DIDs in both sets
+func (s DIDSet) Intersect(other DIDSet) DIDSet {
+	small, large := s, other
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+
+	result := make(DIDSet)
+	for did := range small {
+		if large.Contains(did) {
+			result.Add(did)
+		}
+	}
+	return result
+}
+
+// Difference returns the DIDs in s that are not in other
+func (s DIDSet) Difference(other DIDSet) DIDSet {
+	result := make(DIDSet)
+	for did := range s {
+		if !other.Contains(did) {
+			result.Add(did)
+		}
+	}
+	return result
+}
+
+// Sorted returns the DIDs in lexical order
+func (s DIDSet) Sorted() []string {
+	dids := make([]string, 0, len(s))
+	for did := range s {
+		dids = append(dids, did)
+	}
+	sort.Strings(dids)
+	return dids
+}
+
+// GetAllDistinctDIDs paginates /links/distinct-dids to completion, or until a limit
+// in opts is reached, and returns the DIDs as a set
+func (c *Client) GetAllDistinctDIDs(ctx context.Context, params LinksParams, opts PaginateOptions) (DIDSet, error) {
+	set := make(DIDSet)
+	err := paginate(ctx, opts, params.Cursor, c.distinctDIDsFetcher(params), func(did string) error {
+		set.Add(did)
+		return nil
+	})
+	return set, err
+}
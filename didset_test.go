@@ -0,0 +1,39 @@
+package constellation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestDIDSetOperations tests set algebra on DID sets
+func TestDIDSetOperations(t *testing.T) {
+	a := constellation.NewDIDSet("did:plc:1", "did:plc:2", "did:plc:3")
+	b := constellation.NewDIDSet("did:plc:2", "did:plc:3", "did:plc:4")
+
+	if got := a.Union(b).Len(); got != 4 {
+		t.Errorf("Expected union of 4, got %d", got)
+	}
+	if got := a.Intersect(b).Sorted(); len(got) != 2 || got[0] != "did:plc:2" {
+		t.Errorf("Expected intersection [did:plc:2 did:plc:3], got %v", got)
+	}
+	if got := a.Difference(b); got.Len() != 1 || !got.Contains("did:plc:1") {
+		t.Errorf("Expected difference {did:plc:1}, got %v", got.Sorted())
+	}
+}
+
+// TestGetAllDistinctDIDs tests collecting every distinct DID into a set
+func TestGetAllDistinctDIDs(t *testing.T) {
+	server := newPagedDIDServer(t, 25)
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	set, err := client.GetAllDistinctDIDs(context.Background(), constellation.LinksParams{Target: "did:plc:example"}, constellation.PaginateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get all distinct DIDs: %v", err)
+	}
+	if set.Len() != 25 || !set.Contains("did:plc:user24") {
+		t.Errorf("Expected 25 DIDs including did:plc:user24, got %d", set.Len())
+	}
+}
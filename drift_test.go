@@ -0,0 +1,45 @@
+package constellation_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestDriftWarning tests detection of totals changing between pages
+func TestDriftWarning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"total": 3, "linking_records": [{"did": "did:plc:a", "rkey": "1"}], "cursor": "next"}`))
+			return
+		}
+		fmt.Fprint(w, `{"total": 5, "linking_records": [{"did": "did:plc:b", "rkey": "2"}]}`)
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example"}
+
+	var warnings []constellation.DriftWarning
+	_, err := client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{
+		OnDrift: func(w constellation.DriftWarning) { warnings = append(warnings, w) },
+	})
+	if err != nil {
+		t.Fatalf("Failed to get all links: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Delta() != 2 || warnings[0].Page != 2 {
+		t.Errorf("Expected one drift warning of +2 on page 2, got %+v", warnings)
+	}
+
+	p := client.LinksPaginator(params)
+	for p.Next(context.Background()) {
+	}
+	if len(p.Drift()) != 1 {
+		t.Errorf("Expected paginator to report one drift warning, got %+v", p.Drift())
+	}
+}
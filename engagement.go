@@ -0,0 +1,90 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Engagement holds the engagement counts for a post
+type Engagement struct {
+	Likes         int `json:"likes"`
+	Reposts       int `json:"reposts"`
+	Quotes        int `json:"quotes"`  // Under both embed paths
+	Replies       int `json:"replies"` // Direct replies
+	ThreadReplies int `json:"threadReplies"`
+}
+
+// PostEngagement fetches the like, repost, quote, direct reply, and thread reply
+// counts for a post concurrently. It returns an error if any count fails.
+func (c *Client) PostEngagement(ctx context.Context, postURI string) (*Engagement, error) {
+	engagement := &Engagement{}
+	errs := fanOut(
+		func() error {
+			count, err := c.GetLinksCountContext(ctx, likesParams(postURI))
+			if err == nil {
+				engagement.Likes = count.Total
+			}
+			return err
+		},
+		func() error {
+			count, err := c.GetLinksCountContext(ctx, repostsParams(postURI))
+			if err == nil {
+				engagement.Reposts = count.Total
+			}
+			return err
+		},
+		func() (err error) {
+			engagement.Quotes, err = c.QuoteCount(ctx, postURI)
+			return err
+		},
+		func() (err error) {
+			engagement.Replies, err = c.ReplyCount(ctx, postURI)
+			return err
+		},
+		func() (err error) {
+			engagement.ThreadReplies, err = c.ThreadReplyCount(ctx, postURI)
+			return err
+		},
+	)
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return engagement, nil
+}
+
+// fanOut runs fns concurrently and returns their errors in order. Requests still
+// honor the client's concurrency limit.
+func fanOut(fns ...func() error) []error {
+	errs := make([]error, len(fns))
+	var wg sync.WaitGroup
+	for i, fn := range fns {
+		wg.Add(1)
+		go func(i int, fn func() error) {
+			defer wg.Done()
+			errs[i] = fn()
+		}(i, fn)
+	}
+	wg.Wait()
+	return errs
+}
+
+// parallelEach calls fn for each index below n from a pool of workers
+func parallelEach(n, workers int, fn func(i int)) {
+	var wg sync.WaitGroup
+	indexes := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -0,0 +1,40 @@
+package constellation_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestPostEngagement tests fetching every engagement count in one call
+func TestPostEngagement(t *testing.T) {
+	totals := map[string]int{
+		"app.bsky.feed.like .subject.uri":             10,
+		"app.bsky.feed.repost .subject.uri":           4,
+		"app.bsky.feed.post .embed.record.uri":        2,
+		"app.bsky.feed.post .embed.record.record.uri": 1,
+		"app.bsky.feed.post .reply.parent.uri":        3,
+		"app.bsky.feed.post .reply.root.uri":          8,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		fmt.Fprintf(w, `{"total": %d}`, totals[query.Get("collection")+" "+query.Get("path")])
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	engagement, err := client.PostEngagement(context.Background(), "at://did:plc:creator/app.bsky.feed.post/1")
+	if err != nil {
+		t.Fatalf("Failed to get engagement: %v", err)
+	}
+
+	want := constellation.Engagement{Likes: 10, Reposts: 4, Quotes: 3, Replies: 3, ThreadReplies: 8}
+	if *engagement != want {
+		t.Errorf("Expected %+v, got %+v", want, *engagement)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build go1.23
+
+package constellation_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// ExampleClient_Links ranges over the likes on a post, stopping early
+func ExampleClient_Links() {
+	client := exampleServer().Client()
+	params := constellation.LinksParams{Target: examplePost, Collection: "app.bsky.feed.like", Limit: 1}
+
+	for like, err := range client.Links(context.Background(), params, constellation.PaginateOptions{}) {
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(like.DID)
+		if like.DID == "did:plc:bob" {
+			break
+		}
+	}
+	// Output:
+	// did:plc:alice
+	// did:plc:bob
+}
@@ -0,0 +1,112 @@
+package constellation_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/tanner-caffrey/constellation-go"
+	"github.com/tanner-caffrey/constellation-go/constellationtest"
+)
+
+const (
+	examplePost = "at://did:plc:vc7f4oafdgxsihk4cry2xpze/app.bsky.feed.post/3lgwdn7vd722r"
+	exampleDID  = "did:plc:vc7f4oafdgxsihk4cry2xpze"
+)
+
+// exampleServer returns a fake instance with a few likes, follows, and blocks.
+// Real code would use constellation.NewClient() instead of its client.
+func exampleServer() *constellationtest.Server {
+	server := constellationtest.NewServer()
+	server.AddLink(examplePost, constellation.CollectionLike, ".subject.uri", "did:plc:alice", "did:plc:bob", "did:plc:carol")
+	server.AddLink(exampleDID, constellation.CollectionFollow, ".subject", "did:plc:dave", "did:plc:alice")
+	server.AddLink(exampleDID, constellation.CollectionBlock, ".subject", "did:plc:eve", "did:plc:eve")
+	return server
+}
+
+// ExampleClient_GetLinksCount counts the likes on a post
+func ExampleClient_GetLinksCount() {
+	client := exampleServer().Client()
+
+	count, err := client.GetLinksCount(constellation.LinksParams{
+		Target:     examplePost,
+		Collection: "app.bsky.feed.like",
+		Path:       ".subject.uri",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Likes: %d\n", count.Total)
+	// Output: Likes: 3
+}
+
+// ExampleClient_GetAllLinks fetches every like on a post, paging automatically
+func ExampleClient_GetAllLinks() {
+	client := exampleServer().Client()
+
+	likes, err := client.GetAllLinks(context.Background(), constellation.LinksParams{
+		Target:     examplePost,
+		Collection: "app.bsky.feed.like",
+		Path:       ".subject.uri",
+		Limit:      2,
+	}, constellation.PaginateOptions{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, like := range likes {
+		fmt.Println(like.DID)
+	}
+	// Output:
+	// did:plc:alice
+	// did:plc:bob
+	// did:plc:carol
+}
+
+// ExampleClient_GetDistinctDIDsCount counts the accounts blocking a DID
+func ExampleClient_GetDistinctDIDsCount() {
+	client := exampleServer().Client()
+
+	blockers, err := client.GetDistinctDIDsCount(constellation.LinksParams{
+		Target:     exampleDID,
+		Collection: "app.bsky.graph.block",
+		Path:       ".subject",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Blocked by %d accounts\n", blockers)
+	// Output: Blocked by 1 accounts
+}
+
+// ExampleClient_FollowersOf lists an account's followers
+func ExampleClient_FollowersOf() {
+	client := exampleServer().Client()
+
+	followers, err := client.FollowersOf(context.Background(), exampleDID, constellation.PaginateOptions{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, follower := range followers.Sorted() {
+		fmt.Println(follower)
+	}
+	// Output:
+	// did:plc:alice
+	// did:plc:dave
+}
+
+// ExampleDIDSet_Intersect finds likers of a post who also follow its author
+func ExampleDIDSet_Intersect() {
+	client := exampleServer().Client()
+	ctx := context.Background()
+
+	likers, err := client.LikersOf(ctx, examplePost, constellation.PaginateOptions{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	followers, err := client.FollowersOf(ctx, exampleDID, constellation.PaginateOptions{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(likers.Intersect(followers).Sorted())
+	// Output: [did:plc:alice]
+}
@@ -0,0 +1,39 @@
+// Command block-auditor lists accounts that block a DID
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatalf("usage: %s <did>", os.Args[0])
+	}
+	if err := run(context.Background(), constellation.NewClient(), os.Args[1], os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run writes the number of accounts blocking did, then each blocker
+func run(ctx context.Context, client *constellation.Client, did string, out io.Writer) error {
+	total, err := client.BlockerCount(ctx, did)
+	if err != nil {
+		return fmt.Errorf("failed to count blockers: %w", err)
+	}
+	fmt.Fprintf(out, "%s is blocked by %d accounts\n", did, total)
+
+	blockers, err := client.BlockersOf(ctx, did, constellation.PaginateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list blockers: %w", err)
+	}
+	for _, blocker := range blockers.Sorted() {
+		fmt.Fprintln(out, blocker)
+	}
+	return nil
+}
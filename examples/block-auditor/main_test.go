@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+	"github.com/tanner-caffrey/constellation-go/constellationtest"
+)
+
+// TestRun tests listing blockers across pages
+func TestRun(t *testing.T) {
+	server := constellationtest.NewServer()
+	var blockers []string
+	for i := 0; i < constellationtest.DefaultPageSize+2; i++ {
+		blockers = append(blockers, "did:plc:blocker"+string(rune('a'+i)))
+	}
+	server.AddLink("did:plc:target", constellation.CollectionBlock, ".subject", blockers...)
+
+	var out strings.Builder
+	if err := run(context.Background(), server.Client(), "did:plc:target", &out); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if lines[0] != "did:plc:target is blocked by 18 accounts" || len(lines) != 19 {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,97 @@
+// Command bulk-exporter writes every record linking to a target as JSON lines to
+// stdout, or to a file, S3, or GCS when given a destination:
+//
+//	bulk-exporter <target> <collection> <path> [dest]
+//
+// dest is a local path, s3://bucket/key (credentials from AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY, AWS_REGION, and optionally S3_ENDPOINT), or
+// gs://bucket/object (an access token from GCS_ACCESS_TOKEN).
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+func main() {
+	if len(os.Args) != 4 && len(os.Args) != 5 {
+		log.Fatalf("usage: %s <target> <collection> <path> [dest]", os.Args[0])
+	}
+
+	client := constellation.NewClient()
+	params := constellation.LinksParams{
+		Target:     os.Args[1],
+		Collection: os.Args[2],
+		Path:       os.Args[3],
+		Limit:      100,
+	}
+
+	if len(os.Args) == 5 {
+		store, name := destination(os.Args[4])
+		exported, err := client.Export(context.Background(), params, constellation.PaginateOptions{}, store, name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("exported %d records to %s", exported, os.Args[4])
+		return
+	}
+
+	exported, err := writeRecords(context.Background(), client, params, os.Stdout)
+	if err != nil {
+		log.Fatalf("failed after %d records: %v", exported, err)
+	}
+	log.Printf("exported %d records", exported)
+}
+
+// writeRecords writes every record linking to the target as JSON lines to out,
+// holding one page in memory at a time
+func writeRecords(ctx context.Context, client *constellation.Client, params constellation.LinksParams, out io.Writer) (int, error) {
+	encoder := json.NewEncoder(out)
+	exported := 0
+	err := client.GetLinksEach(ctx, params, constellation.PaginateOptions{}, func(record constellation.LinkRecord) error {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+		exported++
+		return nil
+	})
+	return exported, err
+}
+
+// destination returns the store and blob name for a destination argument
+func destination(dest string) (constellation.BlobStore, string) {
+	if rest, ok := strings.CutPrefix(dest, "s3://"); ok {
+		bucket, key, _ := strings.Cut(rest, "/")
+		endpoint := os.Getenv("S3_ENDPOINT")
+		region := os.Getenv("AWS_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		if endpoint == "" {
+			endpoint = "https://s3." + region + ".amazonaws.com"
+		}
+		return &constellation.S3Store{
+			Endpoint:        endpoint,
+			Bucket:          bucket,
+			Region:          region,
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		}, key
+	}
+	if rest, ok := strings.CutPrefix(dest, "gs://"); ok {
+		bucket, object, _ := strings.Cut(rest, "/")
+		token := os.Getenv("GCS_ACCESS_TOKEN")
+		return &constellation.GCSStore{
+			Bucket: bucket,
+			Token:  func(context.Context) (string, error) { return token, nil },
+		}, object
+	}
+	return &constellation.FileStore{Dir: filepath.Dir(dest)}, filepath.Base(dest)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+	"github.com/tanner-caffrey/constellation-go/constellationtest"
+)
+
+// TestWriteRecords tests exporting records across pages as JSON lines
+func TestWriteRecords(t *testing.T) {
+	post := "at://did:plc:author/app.bsky.feed.post/1"
+	server := constellationtest.NewServer()
+	server.AddLink(post, constellation.CollectionLike, constellation.PathSubjectURI, "did:plc:a", "did:plc:b", "did:plc:c")
+	params := constellation.LinksParams{Target: post, Collection: constellation.CollectionLike, Path: constellation.PathSubjectURI, Limit: 2}
+
+	var out strings.Builder
+	exported, err := writeRecords(context.Background(), server.Client(), params, &out)
+	if err != nil {
+		t.Fatalf("writeRecords failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if exported != 3 || len(lines) != 3 || !strings.Contains(lines[2], `"did":"did:plc:c"`) {
+		t.Errorf("Expected 3 JSON lines, got %d:\n%s", exported, out.String())
+	}
+}
@@ -0,0 +1,259 @@
+// Command engagement-api serves post engagement over HTTP, with counts and
+// liveness checks cached in memory or, with -redis, in Redis shared between
+// replicas:
+//
+//	engagement-api -addr :8080 -redis localhost:6379
+//
+// Endpoints:
+//
+//	GET  /healthz                     upstream health, 503 when it's down
+//	GET  /metrics                     upstream request metrics
+//	GET  /v1/engagement?uri=<post>    like, repost, quote, and reply counts
+//	POST /v1/engagement               counts for {"uris": [...]}, up to -max-batch posts
+//	GET  /v1/summary?uri=<target>     everything linking to a target, by kind
+//	GET  /v1/quotes?uri=<post>        live quotes with their text, up to limit (default 25)
+//	GET  /widget?uri=<post>           the embeddable engagement widget
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+const (
+	defaultQuotes = 25
+	maxQuotes     = 100
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	baseURL := flag.String("base", constellation.DefaultBaseURL, "base URL of the Constellation instance")
+	cacheTTL := flag.Duration("cache-ttl", time.Minute, "how long counts and liveness checks are cached")
+	redisAddr := flag.String("redis", "", "Redis address for a cache shared between replicas; in-memory when empty")
+	concurrency := flag.Int("concurrency", 32, "maximum concurrent upstream requests")
+	maxBatch := flag.Int("max-batch", 50, "maximum posts in one batch request")
+	flag.Parse()
+
+	client := constellation.NewClientWithConfig(*baseURL, constellation.DefaultTimeout)
+	client.SetMaxConcurrency(*concurrency)
+	useCaches(client, *cacheTTL, *redisAddr)
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           newHandler(client, *maxBatch),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("listening on %s", *addr)
+	log.Fatal(server.ListenAndServe())
+}
+
+// useCaches puts read-through caches in front of the client's counts and
+// liveness checks, kept in Redis when redisAddr is set
+func useCaches(client *constellation.Client, ttl time.Duration, redisAddr string) {
+	if redisAddr == "" {
+		client.UseCountCache(constellation.NewMemoryCache(ttl, client.CountGetter()))
+		client.UseLivenessCache(constellation.NewMemoryCache(ttl, client.LivenessGetter()))
+		return
+	}
+
+	store := constellation.NewRedisKV(redisAddr)
+	store.Prefix = "engagement-api:"
+	client.UseCountCache(constellation.NewKVCache(store, ttl, client.CountGetter()))
+	client.UseLivenessCache(constellation.NewKVCache(store, ttl, client.LivenessGetter()))
+}
+
+// handler serves the API for a client
+type handler struct {
+	client   *constellation.Client
+	maxBatch int
+}
+
+// newHandler returns the API's routes
+func newHandler(client *constellation.Client, maxBatch int) http.Handler {
+	h := &handler{client: client, maxBatch: maxBatch}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", h.health)
+	mux.HandleFunc("/metrics", h.metrics)
+	mux.HandleFunc("/v1/engagement", h.engagement)
+	mux.HandleFunc("/v1/summary", h.summary)
+	mux.HandleFunc("/v1/quotes", h.quotes)
+	mux.Handle("/widget", client.WidgetHandler(constellation.WidgetOptions{}))
+	return mux
+}
+
+func (h *handler) health(w http.ResponseWriter, r *http.Request) {
+	result := h.client.Ping(r.Context())
+	status := http.StatusOK
+	if !result.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, map[string]any{
+		"status":      result.Status,
+		"latencyMs":   result.Latency.Milliseconds(),
+		"daysIndexed": result.DaysIndexed,
+	})
+}
+
+func (h *handler) metrics(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.client.Metrics())
+}
+
+func (h *handler) engagement(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		uri := r.URL.Query().Get("uri")
+		if uri == "" {
+			writeError(w, http.StatusBadRequest, "uri parameter is required")
+			return
+		}
+		engagement, err := h.client.PostEngagement(r.Context(), uri)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, engagement)
+
+	case http.MethodPost:
+		var req struct {
+			URIs []string `json:"uris"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid body: "+err.Error())
+			return
+		}
+		if len(req.URIs) == 0 || len(req.URIs) > h.maxBatch {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("between 1 and %d uris are required", h.maxBatch))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"results": h.batchEngagement(r.Context(), req.URIs)})
+
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// batchResult is the engagement of one post in a batch, or why it failed
+type batchResult struct {
+	*constellation.Engagement
+	Error string `json:"error,omitempty"`
+}
+
+// batchEngagement fetches the engagement of each distinct post concurrently;
+// the client's concurrency limit bounds the upstream requests
+func (h *handler) batchEngagement(ctx context.Context, uris []string) map[string]batchResult {
+	distinct := make(map[string]bool, len(uris))
+	for _, uri := range uris {
+		distinct[uri] = true
+	}
+
+	results := make(map[string]batchResult, len(distinct))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for uri := range distinct {
+		wg.Add(1)
+		go func(uri string) {
+			defer wg.Done()
+			engagement, err := h.client.PostEngagement(ctx, uri)
+			result := batchResult{Engagement: engagement}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			mu.Lock()
+			results[uri] = result
+			mu.Unlock()
+		}(uri)
+	}
+	wg.Wait()
+	return results
+}
+
+func (h *handler) summary(w http.ResponseWriter, r *http.Request) {
+	uri := r.URL.Query().Get("uri")
+	if uri == "" {
+		writeError(w, http.StatusBadRequest, "uri parameter is required")
+		return
+	}
+	summary, err := h.client.BacklinkSummary(r.Context(), uri)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"total": summary.Total(), "groups": summary.Groups()})
+}
+
+// quote is a quote post hydrated from its author's PDS
+type quote struct {
+	URI       string `json:"uri"`
+	DID       string `json:"did"`
+	Text      string `json:"text"`
+	CreatedAt string `json:"createdAt,omitempty"`
+}
+
+func (h *handler) quotes(w http.ResponseWriter, r *http.Request) {
+	uri := r.URL.Query().Get("uri")
+	if uri == "" {
+		writeError(w, http.StatusBadRequest, "uri parameter is required")
+		return
+	}
+	limit := defaultQuotes
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxQuotes {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxQuotes))
+			return
+		}
+		limit = n
+	}
+
+	records, err := h.client.QuotesOf(r.Context(), uri, constellation.PaginateOptions{MaxRecords: limit})
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	// Drop quotes deleted since they were indexed, then fetch the rest for their text
+	live, err := h.client.FilterLive(r.Context(), records, 0)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	quotes := make([]quote, len(live))
+	var wg sync.WaitGroup
+	for i, record := range live {
+		quotes[i] = quote{URI: record.RecordURI(), DID: record.DID}
+		wg.Add(1)
+		go func(q *quote) {
+			defer wg.Done()
+			post, err := h.client.GetRecord(r.Context(), q.URI)
+			if err != nil {
+				return
+			}
+			q.Text, _ = post.Value["text"].(string)
+			q.CreatedAt, _ = post.Value["createdAt"].(string)
+		}(&quotes[i])
+	}
+	wg.Wait()
+	writeJSON(w, http.StatusOK, map[string]any{"quotes": quotes})
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
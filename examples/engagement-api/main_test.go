@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// newUpstream fakes a Constellation instance, PLC directory, and PDS. Likes
+// count 7 and every other count 1; each post is quoted by q1, which exists, and
+// q2, which was deleted.
+func newUpstream(t *testing.T, counts *atomic.Int64) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path == "/":
+			w.Write([]byte(`{"days_indexed": 30}`))
+		case r.URL.Path == "/links/count":
+			counts.Add(1)
+			total := 1
+			if query.Get("collection") == constellation.CollectionLike {
+				total = 7
+			}
+			fmt.Fprintf(w, `{"total": %d}`, total)
+		case r.URL.Path == "/links":
+			records := []constellation.LinkRecord{}
+			if query.Get("path") == constellation.PathEmbedRecordURI {
+				for _, rkey := range []string{"q1", "q2"} {
+					records = append(records, constellation.LinkRecord{DID: "did:plc:quoter", Collection: constellation.CollectionPost, RKey: rkey})
+				}
+			}
+			json.NewEncoder(w).Encode(constellation.LinksResponse{LinkingRecords: records})
+		case r.URL.Path == "/links/all/count":
+			w.Write([]byte(`{"links": {"app.bsky.feed.like": {".subject.uri": 7}, "app.bsky.feed.post": {".embed.record.uri": 2}}}`))
+		case strings.HasPrefix(r.URL.Path, "/did:plc:"):
+			fmt.Fprintf(w, `{"id": %q, "service": [{"id": "#atproto_pds", "serviceEndpoint": %q}]}`, r.URL.Path[1:], server.URL)
+		case r.URL.Path == "/xrpc/com.atproto.repo.getRecord":
+			if query.Get("rkey") != "q1" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": "RecordNotFound"}`))
+				return
+			}
+			w.Write([]byte(`{"uri": "at://x", "value": {"text": "look at this", "createdAt": "2026-10-15T00:00:00Z"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestAPI starts the API in front of a fake upstream
+func newTestAPI(t *testing.T) (*httptest.Server, *atomic.Int64) {
+	counts := &atomic.Int64{}
+	upstream := newUpstream(t, counts)
+	client := constellation.NewClientWithConfig(upstream.URL, 5*time.Second)
+	client.PLCDirectory = upstream.URL
+	client.SetMaxConcurrency(8)
+	useCaches(client, time.Minute, "")
+
+	api := httptest.NewServer(newHandler(client, 10))
+	t.Cleanup(api.Close)
+	return api, counts
+}
+
+// TestEngagementLoad tests many concurrent clients reading the same posts: every
+// request succeeds, and once cached, counts are served without upstream requests
+func TestEngagementLoad(t *testing.T) {
+	api, counts := newTestAPI(t)
+	const posts, requests = 10, 200
+
+	run := func() {
+		var wg sync.WaitGroup
+		for i := 0; i < requests; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				uri := fmt.Sprintf("at://did:plc:author/app.bsky.feed.post/%d", i%posts)
+				resp, err := http.Get(api.URL + "/v1/engagement?uri=" + uri)
+				if err != nil {
+					t.Errorf("Request failed: %v", err)
+					return
+				}
+				defer resp.Body.Close()
+				var engagement constellation.Engagement
+				if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&engagement) != nil {
+					t.Errorf("Unexpected response %s", resp.Status)
+					return
+				}
+				if engagement.Likes != 7 || engagement.Quotes != 2 {
+					t.Errorf("Unexpected engagement %+v", engagement)
+				}
+			}(i)
+		}
+		wg.Wait()
+	}
+
+	run()
+	cold := counts.Load()
+	if cold == 0 {
+		t.Fatal("Expected upstream count requests")
+	}
+	run()
+	if warm := counts.Load() - cold; warm != 0 {
+		t.Errorf("Expected cached counts to absorb repeated load, got %d upstream requests", warm)
+	}
+}
+
+// TestBatchEngagement tests fetching several posts in one request
+func TestBatchEngagement(t *testing.T) {
+	api, _ := newTestAPI(t)
+
+	body := `{"uris": ["at://did:plc:a/app.bsky.feed.post/1", "at://did:plc:a/app.bsky.feed.post/2", "at://did:plc:a/app.bsky.feed.post/1"]}`
+	resp, err := http.Post(api.URL+"/v1/engagement", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var batch struct {
+		Results map[string]struct {
+			Likes int    `json:"likes"`
+			Error string `json:"error"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if len(batch.Results) != 2 {
+		t.Fatalf("Expected 2 distinct results, got %d", len(batch.Results))
+	}
+	for uri, result := range batch.Results {
+		if result.Likes != 7 || result.Error != "" {
+			t.Errorf("Unexpected result for %s: %+v", uri, result)
+		}
+	}
+
+	uris, _ := json.Marshal(map[string][]string{"uris": make([]string, 11)})
+	resp, err = http.Post(api.URL+"/v1/engagement", "application/json", bytes.NewReader(uris))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected an oversized batch to be rejected, got %s", resp.Status)
+	}
+}
+
+// TestQuotesAndSummary tests the hydrated quote list and the backlink summary
+func TestQuotesAndSummary(t *testing.T) {
+	api, _ := newTestAPI(t)
+	uri := "at://did:plc:a/app.bsky.feed.post/1"
+
+	resp, err := http.Get(api.URL + "/v1/quotes?uri=" + uri)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	var quotes struct {
+		Quotes []quote `json:"quotes"`
+	}
+	json.NewDecoder(resp.Body).Decode(&quotes)
+	resp.Body.Close()
+	if len(quotes.Quotes) != 1 || quotes.Quotes[0].Text != "look at this" {
+		t.Errorf("Expected the live quote with its text, got %+v", quotes.Quotes)
+	}
+
+	resp, err = http.Get(api.URL + "/v1/summary?uri=" + uri)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	var summary struct {
+		Total  int64                         `json:"total"`
+		Groups []constellation.BacklinkGroup `json:"groups"`
+	}
+	json.NewDecoder(resp.Body).Decode(&summary)
+	resp.Body.Close()
+	if summary.Total != 9 || len(summary.Groups) != 2 || summary.Groups[0].Preset != "bsky.likes" {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+
+	resp, err = http.Get(api.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected a healthy upstream, got %s", resp.Status)
+	}
+}
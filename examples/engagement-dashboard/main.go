@@ -0,0 +1,43 @@
+// Command engagement-dashboard prints like, repost, quote, and reply counts for a post
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatalf("usage: %s <post-uri>", os.Args[0])
+	}
+	if err := run(context.Background(), constellation.NewClient(), os.Args[1], os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run writes the engagement counts for postURI, fetched concurrently
+func run(ctx context.Context, client *constellation.Client, postURI string, out io.Writer) error {
+	engagement, err := client.PostEngagement(ctx, postURI)
+	if err != nil {
+		return fmt.Errorf("failed to fetch engagement: %w", err)
+	}
+
+	fmt.Fprintf(out, "Engagement for %s\n", postURI)
+	for _, metric := range []struct {
+		label string
+		count int
+	}{
+		{"Likes", engagement.Likes},
+		{"Reposts", engagement.Reposts},
+		{"Quotes", engagement.Quotes},
+		{"Replies", engagement.Replies},
+	} {
+		fmt.Fprintf(out, "  %-8s %d\n", metric.label, metric.count)
+	}
+	return nil
+}
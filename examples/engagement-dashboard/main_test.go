@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+	"github.com/tanner-caffrey/constellation-go/constellationtest"
+)
+
+// TestRun tests printing a post's engagement counts
+func TestRun(t *testing.T) {
+	post := "at://did:plc:author/app.bsky.feed.post/1"
+	server := constellationtest.NewServer()
+	server.AddLink(post, constellation.CollectionLike, constellation.PathSubjectURI, "did:plc:a", "did:plc:b")
+	server.AddLink(post, constellation.CollectionRepost, constellation.PathSubjectURI, "did:plc:c")
+
+	var out strings.Builder
+	if err := run(context.Background(), server.Client(), post, &out); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	for _, want := range []string{"Likes    2", "Reposts  1", "Replies  0"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
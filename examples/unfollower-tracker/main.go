@@ -0,0 +1,78 @@
+// Command unfollower-tracker reports accounts that unfollowed a DID since the last run.
+// Follower snapshots are stored as JSON in the given state file.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		log.Fatalf("usage: %s <did> <state-file>", os.Args[0])
+	}
+	if err := run(context.Background(), constellation.NewClient(), os.Args[1], os.Args[2], os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run reports changes to did's followers since the snapshot in stateFile, then
+// replaces the snapshot
+func run(ctx context.Context, client *constellation.Client, did, stateFile string, out io.Writer) error {
+	current, err := client.FollowersOf(ctx, did, constellation.PaginateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch followers: %w", err)
+	}
+
+	previous, err := loadSnapshot(stateFile)
+	if err != nil {
+		return fmt.Errorf("failed to load snapshot: %w", err)
+	}
+
+	if previous != nil {
+		for _, follower := range previous.Difference(current).Sorted() {
+			fmt.Fprintf(out, "unfollowed: %s\n", follower)
+		}
+		for _, follower := range current.Difference(previous).Sorted() {
+			fmt.Fprintf(out, "new follower: %s\n", follower)
+		}
+	}
+
+	if err := saveSnapshot(stateFile, current); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return nil
+}
+
+// loadSnapshot reads a follower snapshot, returning nil if none exists yet
+func loadSnapshot(path string) (constellation.DIDSet, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var dids []string
+	if err := json.Unmarshal(data, &dids); err != nil {
+		return nil, err
+	}
+	return constellation.NewDIDSet(dids...), nil
+}
+
+// saveSnapshot writes a follower snapshot to disk
+func saveSnapshot(path string, followers constellation.DIDSet) error {
+	data, err := json.Marshal(followers.Sorted())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
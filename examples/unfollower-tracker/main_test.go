@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+	"github.com/tanner-caffrey/constellation-go/constellationtest"
+)
+
+// TestRun tests reporting follower changes between runs
+func TestRun(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	ctx := context.Background()
+
+	first := constellationtest.NewServer()
+	first.AddLink("did:plc:me", constellation.CollectionFollow, ".subject", "did:plc:a", "did:plc:b")
+	var out strings.Builder
+	if err := run(ctx, first.Client(), "did:plc:me", stateFile, &out); err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected no report without a previous snapshot, got:\n%s", out.String())
+	}
+
+	second := constellationtest.NewServer()
+	second.AddLink("did:plc:me", constellation.CollectionFollow, ".subject", "did:plc:b", "did:plc:c")
+	out.Reset()
+	if err := run(ctx, second.Client(), "did:plc:me", stateFile, &out); err != nil {
+		t.Fatalf("second run failed: %v", err)
+	}
+	if want := "unfollowed: did:plc:a\nnew follower: did:plc:c\n"; out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+}
@@ -0,0 +1,88 @@
+package constellation
+
+import (
+	"context"
+	"sync"
+)
+
+// listExclusion holds the cached members of an excluded list
+type listExclusion struct {
+	mu      sync.RWMutex
+	members DIDSet
+}
+
+// contains reports whether did is a cached member of the list
+func (l *listExclusion) contains(did string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.members.Contains(did)
+}
+
+// ExcludeDIDs makes the client drop records and DIDs from the accounts in dids in
+// every listing response, e.g. to keep known spam accounts out of analytics.
+// dids is copied, so later changes to it have no effect. Totals reported by the
+// server are left unchanged.
+func (c *Client) ExcludeDIDs(dids DIDSet) *Client {
+	c.addExclusion(NewDIDSet().Union(dids).Contains)
+	return c
+}
+
+// ExcludeList makes the client drop records and DIDs from the members of the list
+// at listURI, such as a moderation list, in every listing response. Members are
+// fetched now and cached on the client; call ExcludeList again to refresh them.
+// If fetching fails, any members cached earlier stay excluded.
+func (c *Client) ExcludeList(ctx context.Context, listURI string) error {
+	members, err := c.ListMembers(ctx, listURI, PaginateOptions{})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	exclusion, ok := c.excludedLists[listURI]
+	if !ok {
+		exclusion = &listExclusion{}
+		if c.excludedLists == nil {
+			c.excludedLists = make(map[string]*listExclusion)
+		}
+		c.excludedLists[listURI] = exclusion
+	}
+	c.mu.Unlock()
+
+	exclusion.mu.Lock()
+	exclusion.members = members
+	exclusion.mu.Unlock()
+
+	if !ok {
+		c.addExclusion(exclusion.contains)
+	}
+	return nil
+}
+
+// ListMembers returns the accounts on the list at listURI, read from the .subject
+// of each list item the list's owner created. Values the API doesn't include are
+// fetched from the owner's PDS; items that can't be fetched are skipped.
+func (c *Client) ListMembers(ctx context.Context, listURI string, opts PaginateOptions) (DIDSet, error) {
+	list, err := ParseATURI(listURI)
+	if err != nil {
+		return nil, err
+	}
+
+	// Anyone can create a list item pointing at a list, so only the owner's count
+	params := LinksParams{Target: listURI, Collection: CollectionListItem, Path: PathList, FromDID: list.DID}
+	items, err := c.GetAllLinks(ctx, params, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(DIDSet)
+	for _, item := range items {
+		value, err := c.recordValue(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+		if subject, ok := value["subject"].(string); ok {
+			members.Add(subject)
+		}
+	}
+	return members, nil
+}
@@ -0,0 +1,98 @@
+package constellation_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestExcludeDIDs tests dropping linkers in a DID set from listings
+func TestExcludeDIDs(t *testing.T) {
+	server := newPagedServer(t, 5)
+	spam := constellation.NewDIDSet("did:plc:user1", "did:plc:user3")
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second).ExcludeDIDs(spam)
+	spam.Add("did:plc:user0") // Later changes have no effect
+
+	records, err := client.GetAllLinks(context.Background(), constellation.LinksParams{Target: "did:plc:example"}, constellation.PaginateOptions{})
+	if err != nil {
+		t.Fatalf("GetAllLinks failed: %v", err)
+	}
+	if len(records) != 3 {
+		t.Errorf("Expected 3 records after exclusion, got %+v", records)
+	}
+	for _, record := range records {
+		if record.DID == "did:plc:user1" || record.DID == "did:plc:user3" {
+			t.Errorf("Expected %s to be excluded", record.DID)
+		}
+	}
+}
+
+// TestExcludeList tests excluding the members of a list, ignoring items from other accounts
+func TestExcludeList(t *testing.T) {
+	const listURI = "at://did:plc:mod/app.bsky.graph.list/spam"
+	members := []string{"did:plc:user2"}
+	linkers := newPagedServer(t, 5)
+
+	lists := newListServer(t, listURI, &members)
+	client := constellation.NewClientWithConfig(lists.URL, 5*time.Second)
+	ctx := context.Background()
+
+	got, err := client.ListMembers(ctx, listURI, constellation.PaginateOptions{})
+	if err != nil {
+		t.Fatalf("ListMembers failed: %v", err)
+	}
+	if got.Len() != 1 || !got.Contains("did:plc:user2") {
+		t.Errorf("Expected only the owner's items to count, got %v", got.Sorted())
+	}
+
+	if err := client.ExcludeList(ctx, listURI); err != nil {
+		t.Fatalf("ExcludeList failed: %v", err)
+	}
+	client.BaseURL = linkers.URL
+	records, err := client.GetAllLinks(ctx, constellation.LinksParams{Target: "did:plc:example"}, constellation.PaginateOptions{})
+	if err != nil || len(records) != 4 {
+		t.Errorf("Expected 4 records without the list member, got %d, %v", len(records), err)
+	}
+
+	// Refreshing replaces the cached members
+	members = []string{"did:plc:user2", "did:plc:user4"}
+	client.BaseURL = lists.URL
+	if err := client.ExcludeList(ctx, listURI); err != nil {
+		t.Fatalf("ExcludeList failed: %v", err)
+	}
+	client.BaseURL = linkers.URL
+	records, err = client.GetAllLinks(ctx, constellation.LinksParams{Target: "did:plc:example"}, constellation.PaginateOptions{})
+	if err != nil || len(records) != 3 {
+		t.Errorf("Expected 3 records after refreshing the list, got %d, %v", len(records), err)
+	}
+}
+
+// newListServer serves the items of the list at listURI: one per member from the
+// list's owner, plus an item another account pointed at the list
+func newListServer(t *testing.T, listURI string, members *[]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("target") != listURI || r.URL.Query().Get("path") != constellation.PathList {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		records := []constellation.LinkRecord{{
+			DID: "did:plc:mallory", Collection: constellation.CollectionListItem, RKey: "x",
+			Value: map[string]any{"subject": "did:plc:user0", "list": listURI},
+		}}
+		for i, member := range *members {
+			records = append(records, constellation.LinkRecord{
+				DID: "did:plc:mod", Collection: constellation.CollectionListItem, RKey: strconv.Itoa(i),
+				Value: map[string]any{"subject": member, "list": listURI},
+			})
+		}
+		json.NewEncoder(w).Encode(constellation.LinksResponse{LinkingRecords: records})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
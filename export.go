@@ -0,0 +1,79 @@
+package constellation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// BlobStore is a destination for exports. FileStore, S3Store, and GCSStore
+// implement it.
+type BlobStore interface {
+	// PutBlob stores everything read from r under name, replacing any existing
+	// blob. A failed read must not leave a partial blob behind.
+	PutBlob(ctx context.Context, name string, r io.Reader) error
+}
+
+// FileStore is a BlobStore writing files under a local directory
+type FileStore struct {
+	Dir string
+}
+
+// PutBlob writes r to Dir/name, creating parent directories as needed. The file
+// is written to a temporary file and renamed, so readers never see a partial export.
+// Names that are absolute or escape Dir, such as "../x", are rejected.
+func (s *FileStore) PutBlob(ctx context.Context, name string, r io.Reader) error {
+	if !filepath.IsLocal(filepath.FromSlash(name)) {
+		return fmt.Errorf("invalid blob name %q: must be a relative path within the directory", name)
+	}
+	path := filepath.Join(s.Dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Export writes every record linking to a target as JSON lines to dest under
+// name and returns the number of records written. Records are streamed page by
+// page; stores that need the whole blob, such as S3Store, buffer it themselves.
+// If fetching fails, nothing is stored.
+func (c *Client) Export(ctx context.Context, params LinksParams, opts PaginateOptions, dest BlobStore, name string) (int, error) {
+	reader, writer := io.Pipe()
+	exported := 0
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		encoder := json.NewEncoder(writer)
+		err := c.GetLinksEach(ctx, params, opts, func(record LinkRecord) error {
+			exported++
+			return encoder.Encode(record)
+		})
+		writer.CloseWithError(err)
+	}()
+
+	err := dest.PutBlob(ctx, name, reader)
+	reader.CloseWithError(err)
+	<-done
+	if err != nil {
+		return 0, fmt.Errorf("failed to export: %w", err)
+	}
+	return exported, nil
+}
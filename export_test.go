@@ -0,0 +1,88 @@
+package constellation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestExportToFileStore tests exporting every page as JSON lines to a file
+func TestExportToFileStore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"total": 3, "linking_records": [{"did": "did:plc:a", "rkey": "1"}, {"did": "did:plc:b", "rkey": "2"}], "cursor": "next"}`))
+			return
+		}
+		w.Write([]byte(`{"total": 3, "linking_records": [{"did": "did:plc:c", "rkey": "3"}]}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "at://a", Collection: constellation.CollectionLike}
+
+	n, err := client.Export(context.Background(), params, constellation.PaginateOptions{}, &constellation.FileStore{Dir: dir}, "exports/likes.jsonl")
+	if err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Expected 3 records, got %d", n)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "exports", "likes.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 3 || !strings.Contains(lines[2], "did:plc:c") {
+		t.Errorf("Unexpected export contents:\n%s", data)
+	}
+}
+
+// TestExportFailureStoresNothing tests that a failed fetch leaves no partial blob
+func TestExportFailureStoresNothing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"total": 2, "linking_records": [{"did": "did:plc:a", "rkey": "1"}], "cursor": "next"}`))
+			return
+		}
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "at://a", Collection: constellation.CollectionLike}
+
+	if _, err := client.Export(context.Background(), params, constellation.PaginateOptions{}, &constellation.FileStore{Dir: dir}, "likes.jsonl"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected no files, found %d", len(entries))
+	}
+}
+
+// TestFileStoreRejectsEscapingNames tests that blob names can't write outside Dir
+func TestFileStoreRejectsEscapingNames(t *testing.T) {
+	parent := t.TempDir()
+	store := &constellation.FileStore{Dir: filepath.Join(parent, "exports")}
+
+	for _, name := range []string{"../escaped.jsonl", "a/../../escaped.jsonl", "/tmp/escaped.jsonl", ""} {
+		if err := store.PutBlob(context.Background(), name, strings.NewReader("data")); err == nil {
+			t.Errorf("Expected an error for name %q", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(parent, "escaped.jsonl")); !os.IsNotExist(err) {
+		t.Errorf("Expected no file outside Dir, got %v", err)
+	}
+
+	if err := store.PutBlob(context.Background(), "a/../kept.jsonl", strings.NewReader("data")); err != nil {
+		t.Errorf("Expected a name that stays within Dir to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Filter names advertised by instances that support server-side filtering
+const (
+	FilterDID   = "did"
+	FilterSince = "since"
+)
+
+// ErrUnsupportedFilter is returned when a filter can't be applied server-side and
+// has no client-side emulation for the endpoint
+var ErrUnsupportedFilter = errors.New("filter not supported for this endpoint")
+
+// addExclusion registers a predicate; DIDs for which it returns true are removed
+// from all listing responses
+func (c *Client) addExclusion(exclude func(did string) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.exclusions = append(c.exclusions, exclude)
+}
+
+// excluded reports whether any registered exclusion matches did
+func (c *Client) excluded(did string) bool {
+	c.mu.Lock()
+	exclusions := c.exclusions
+	c.mu.Unlock()
+
+	for _, exclude := range exclusions {
+		if exclude(did) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterRecords removes records authored by excluded DIDs
+func (c *Client) filterRecords(records []LinkRecord) []LinkRecord {
+	kept := records[:0]
+	for _, record := range records {
+		if !c.excluded(record.DID) {
+			kept = append(kept, record)
+		}
+	}
+	return kept
+}
+
+// excluding reports whether any exclusions are configured
+func (c *Client) excluding() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.exclusions) > 0
+}
+
+// filterDIDs removes excluded DIDs
+func (c *Client) filterDIDs(dids []string) []string {
+	kept := dids[:0]
+	for _, did := range dids {
+		if !c.excluded(did) {
+			kept = append(kept, did)
+		}
+	}
+	return kept
+}
+
+// supportsFilter reports whether the instance advertises a server-side filter
+func (c *Client) supportsFilter(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.capabilities == nil {
+		return false
+	}
+	for _, filter := range c.capabilities.Filters {
+		if filter == name {
+			return true
+		}
+	}
+	return false
+}
+
+// needsClientFilter reports whether params uses a filter the instance can't apply
+func (c *Client) needsClientFilter(params LinksParams) bool {
+	return (params.FromDID != "" && !c.supportsFilter(FilterDID)) ||
+		(!params.Since.IsZero() && !c.supportsFilter(FilterSince))
+}
+
+// recordFilter emulates the record filters an instance can't apply itself
+type recordFilter struct {
+	fromDID string
+	since   time.Time
+}
+
+// keep reports whether record passes the filter
+func (f *recordFilter) keep(record LinkRecord) bool {
+	if f.fromDID != "" && record.DID != f.fromDID {
+		return false
+	}
+	if !f.since.IsZero() {
+		created, ok := createdTime(record)
+		if !ok || created.Before(f.since) {
+			return false
+		}
+	}
+	return true
+}
+
+// exhausted reports whether a page reached records created before since. Pages
+// are newest first, so later pages would be filtered out entirely.
+func (f *recordFilter) exhausted(records []LinkRecord) bool {
+	if f.since.IsZero() || len(records) == 0 {
+		return false
+	}
+	created, ok := createdTime(records[len(records)-1])
+	return ok && created.Before(f.since)
+}
+
+// splitFilters removes filters the instance can't apply from params and returns a
+// filter emulating them client-side, or nil if none are needed
+func (c *Client) splitFilters(params LinksParams) (LinksParams, *recordFilter) {
+	var filter recordFilter
+
+	if params.FromDID != "" && !c.supportsFilter(FilterDID) {
+		filter.fromDID, params.FromDID = queryTarget(params.FromDID), ""
+	}
+	if !params.Since.IsZero() && !c.supportsFilter(FilterSince) {
+		filter.since, params.Since = params.Since, time.Time{}
+	}
+	if filter.fromDID == "" && filter.since.IsZero() {
+		return params, nil
+	}
+	return params, &filter
+}
+
+// createdTime returns when a record was created, from its TID record key or,
+// failing that, its createdAt field
+func createdTime(record LinkRecord) (time.Time, bool) {
+	if created, ok := tidTime(record.RKey); ok {
+		return created, true
+	}
+	createdAt, _ := record.Value["createdAt"].(string)
+	created, err := time.Parse(time.RFC3339, createdAt)
+	return created, err == nil
+}
+
+// splitDIDFilters is splitFilters for distinct-DID endpoints, where only the DID
+// filter can be emulated. It returns the DID to keep, or "" for no filtering.
+func (c *Client) splitDIDFilters(params LinksParams) (LinksParams, string, error) {
+	if !params.Since.IsZero() && !c.supportsFilter(FilterSince) {
+		return params, "", fmt.Errorf("%w: since on distinct DIDs", ErrUnsupportedFilter)
+	}
+
+	var fromDID string
+	if params.FromDID != "" && !c.supportsFilter(FilterDID) {
+		fromDID, params.FromDID = queryTarget(params.FromDID), ""
+	}
+	return params, fromDID, nil
+}
+
+// keepRecords returns the records matching keep
+func keepRecords(records []LinkRecord, keep func(LinkRecord) bool) []LinkRecord {
+	kept := records[:0]
+	for _, record := range records {
+		if keep(record) {
+			kept = append(kept, record)
+		}
+	}
+	return kept
+}
+
+// keepDIDs returns dids equal to did
+func keepDIDs(dids []string, did string) []string {
+	kept := dids[:0]
+	for _, candidate := range dids {
+		if candidate == did {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
+}
+
+// DefaultCountScanLimit is the number of links or DIDs counted by paging, when
+// an instance can't count a query itself, before giving up with a lower bound
+const DefaultCountScanLimit = 10000
+
+// countScanLimit returns Client.CountScanLimit or its default
+func (c *Client) countScanLimit() int {
+	if c.CountScanLimit > 0 {
+		return c.CountScanLimit
+	}
+	return DefaultCountScanLimit
+}
+
+// countByPaginating emulates a links count by paginating, stopping at the count
+// scan limit. capped reports whether the limit was reached, making total a
+// lower bound.
+func (c *Client) countByPaginating(ctx context.Context, params LinksParams) (total int, capped bool, err error) {
+	limit := c.countScanLimit()
+	err = c.GetLinksEach(ctx, params, PaginateOptions{MaxRecords: limit}, func(LinkRecord) error {
+		total++
+		return nil
+	})
+	return total, total >= limit, err
+}
+
+// countDistinctByPaginating emulates a distinct DIDs count by paginating, like
+// countByPaginating
+func (c *Client) countDistinctByPaginating(ctx context.Context, params LinksParams) (total int, capped bool, err error) {
+	limit := c.countScanLimit()
+	err = c.GetDistinctDIDsEach(ctx, params, PaginateOptions{MaxRecords: limit}, func(string) error {
+		total++
+		return nil
+	})
+	if err != nil {
+		return -1, false, err
+	}
+	return total, total >= limit, nil
+}
+
+// countUnsupported reports whether err shows the instance has no count endpoint
+// for a query, either from preflight detection or a 404 response
+func countUnsupported(err error) bool {
+	var apiErr *APIError
+	return errors.Is(err, ErrUnsupportedEndpoint) ||
+		(errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound)
+}
+
+// fallbackParams prepares params for counting by paging, using the largest page
+// size the instance accepts to minimize requests
+func (c *Client) fallbackParams(params LinksParams) LinksParams {
+	c.mu.Lock()
+	caps := c.capabilities
+	c.mu.Unlock()
+
+	params.Cursor = ""
+	params.Limit = DefaultMaxLimit
+	if caps != nil && caps.MaxLimit > 0 {
+		params.Limit = caps.MaxLimit
+	}
+	return params
+}
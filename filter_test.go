@@ -0,0 +1,185 @@
+package constellation_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestFromDIDClientSideFallback tests filter emulation on instances without support
+func TestFromDIDClientSideFallback(t *testing.T) {
+	server := newPagedServer(t, 25)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10, FromDID: "did:plc:user13"}
+
+	records, err := client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get all links: %v", err)
+	}
+	if len(records) != 1 || records[0].DID != "did:plc:user13" {
+		t.Errorf("Expected only the record from did:plc:user13, got %+v", records)
+	}
+
+	count, err := client.GetLinksCount(params)
+	if err != nil {
+		t.Fatalf("Failed to get links count: %v", err)
+	}
+	if count.Total != 1 {
+		t.Errorf("Expected emulated count 1, got %d", count.Total)
+	}
+
+	// The DID is normalized before filtering, as it would be by the server
+	params.FromDID = " DID:PLC:USER13 "
+	if count, err := client.GetLinksCount(params); err != nil || count.Total != 1 {
+		t.Errorf("Expected a non-canonical DID to match, got %+v, %v", count, err)
+	}
+
+	params.Since = time.Now()
+	if _, err := client.GetDistinctDIDs(params); !errors.Is(err, constellation.ErrUnsupportedFilter) {
+		t.Errorf("Expected ErrUnsupportedFilter for since on distinct DIDs, got: %v", err)
+	}
+}
+
+// TestSinceClientSide tests that emulated Since filters by creation time, not
+// IndexedAt, and stops paging once pages reach older records
+func TestSinceClientSide(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	var pages atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := int(pages.Add(1)) - 1
+		// Two records per page, created an hour apart, newest first, all
+		// indexed just now as after a backfill
+		fmt.Fprintf(w, `{"linking_records": [{"did": "did:plc:a", "rkey": "%s", "indexedAt": "%s"}, {"did": "did:plc:b", "rkey": "%s", "indexedAt": "%s"}], "cursor": "p%d"}`,
+			testTID(now.Add(-time.Duration(2*page)*time.Hour)), now.Format(time.RFC3339),
+			testTID(now.Add(-time.Duration(2*page+1)*time.Hour)), now.Format(time.RFC3339), page+1)
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Since: now.Add(-150 * time.Minute)}
+
+	records, err := client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get all links: %v", err)
+	}
+	if len(records) != 3 {
+		t.Errorf("Expected the 3 records created in the last 150 minutes, got %d", len(records))
+	}
+	if pages.Load() != 2 {
+		t.Errorf("Expected paging to stop after 2 pages, got %d", pages.Load())
+	}
+}
+
+// testTID encodes t as a TID record key
+func testTID(t time.Time) string {
+	const alphabet = "234567abcdefghijklmnopqrstuvwxyz"
+	value := uint64(t.UnixMicro()) << 10
+	tid := make([]byte, 13)
+	for i := len(tid) - 1; i >= 0; i-- {
+		tid[i] = alphabet[value&31]
+		value >>= 5
+	}
+	return string(tid)
+}
+
+// TestFromDIDServerSide tests that supported filters are sent to the server
+func TestFromDIDServerSide(t *testing.T) {
+	var did string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		did = r.URL.Query().Get("did")
+		w.Write([]byte(`{"total": 4}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.UseCapabilities(&constellation.Capabilities{Filters: []string{constellation.FilterDID}})
+
+	count, err := client.GetLinksCount(constellation.LinksParams{Target: "did:plc:example", FromDID: "DID:PLC:Author"})
+	if err != nil {
+		t.Fatalf("Failed to get links count: %v", err)
+	}
+	if did != "did:plc:author" {
+		t.Errorf("Expected normalized did filter to be sent, got '%s'", did)
+	}
+	if count.Total != 4 {
+		t.Errorf("Expected server count 4, got %d", count.Total)
+	}
+}
+
+// TestCountFallback tests deriving counts by paging when the count endpoint is missing
+func TestCountFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/links":
+			if r.URL.Query().Get("limit") != "100" {
+				t.Errorf("Expected maximum page size, got %s", r.URL.RawQuery)
+			}
+			if r.URL.Query().Get("cursor") == "" {
+				w.Write([]byte(`{"linking_records": [{"did": "did:plc:a"}, {"did": "did:plc:b"}], "cursor": "next"}`))
+				return
+			}
+			w.Write([]byte(`{"linking_records": [{"did": "did:plc:c"}]}`))
+		case "/links/distinct-dids":
+			w.Write([]byte(`{"linking_dids": ["did:plc:a", "did:plc:b"]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example"}
+
+	count, err := client.GetLinksCount(params)
+	if err != nil {
+		t.Fatalf("Expected fallback count, got error: %v", err)
+	}
+	if count.Total != 3 || !count.LowerBound {
+		t.Errorf("Expected lower-bound count of 3, got %+v", count)
+	}
+
+	distinct, err := client.GetDistinctDIDsCount(params)
+	if err != nil || distinct != 2 {
+		t.Errorf("Expected fallback distinct count of 2, got %d, %v", distinct, err)
+	}
+
+	distinctCount, err := client.GetDistinctDIDsCountResponse(context.Background(), params)
+	if err != nil || distinctCount.Total != 2 || !distinctCount.LowerBound {
+		t.Errorf("Expected lower-bound distinct count of 2, got %+v, %v", distinctCount, err)
+	}
+}
+
+// TestCountFallbackScanLimit tests that counting by paging stops at
+// CountScanLimit rather than paging through the whole link set
+func TestCountFallbackScanLimit(t *testing.T) {
+	var pages atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/links" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"linking_records": [{"did": "did:plc:a"}, {"did": "did:plc:b"}], "cursor": "p%d"}`, pages.Add(1))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.CountScanLimit = 5
+
+	count, err := client.GetLinksCount(constellation.LinksParams{Target: "did:plc:example"})
+	if err != nil {
+		t.Fatalf("Expected fallback count, got error: %v", err)
+	}
+	if count.Total != 5 || !count.LowerBound {
+		t.Errorf("Expected a lower-bound count of 5, got %+v", count)
+	}
+	if pages.Load() != 3 {
+		t.Errorf("Expected 3 pages for a scan limit of 5, got %d", pages.Load())
+	}
+}
@@ -0,0 +1,55 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoRecords is returned when an operation needs at least one linking record
+var ErrNoRecords = errors.New("no linking records found")
+
+// Freshness estimates how up to date the index is for a query
+type Freshness struct {
+	Newest   time.Time     // Creation time of the most recent linking record
+	Lag      time.Duration // Time since the most recent linking record
+	IndexLag time.Duration // Indexing delay reported by the server, if any
+}
+
+// EstimateFreshness estimates index lag for a query by comparing the most recent
+// linking record's timestamp with the current time. For actively linked targets
+// this approximates how far the index trails the firehose; for quiet targets it
+// is an upper bound. The server-reported indexing delay is included when known.
+func (c *Client) EstimateFreshness(ctx context.Context, params LinksParams) (*Freshness, error) {
+	params.Cursor = ""
+	resp, err := c.GetLinksContext(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var newest time.Time
+	for _, record := range resp.LinkingRecords {
+		if created, ok := recordTime(record); ok && created.After(newest) {
+			newest = created
+		}
+	}
+	if newest.IsZero() {
+		return nil, ErrNoRecords
+	}
+
+	freshness := &Freshness{
+		Newest: newest,
+		Lag:    time.Since(newest),
+	}
+	if freshness.Lag < 0 {
+		freshness.Lag = 0
+	}
+
+	c.mu.Lock()
+	if c.capabilities != nil {
+		freshness.IndexLag = c.capabilities.IndexLag
+	}
+	c.mu.Unlock()
+
+	return freshness, nil
+}
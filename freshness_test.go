@@ -0,0 +1,46 @@
+package constellation_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestEstimateFreshness tests lag estimation from the newest record
+func TestEstimateFreshness(t *testing.T) {
+	newest := time.Now().Add(-time.Hour).UTC()
+	empty := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if empty {
+			w.Write([]byte(`{"total": 0, "linking_records": []}`))
+			return
+		}
+		fmt.Fprintf(w, `{"total": 2, "linking_records": [
+			{"did": "did:plc:a", "rkey": "self", "indexedAt": "%s"},
+			{"did": "did:plc:b", "rkey": "self", "indexedAt": "%s"}
+		]}`, newest.Add(-time.Hour).Format(time.RFC3339), newest.Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example"}
+
+	freshness, err := client.EstimateFreshness(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Failed to estimate freshness: %v", err)
+	}
+	if freshness.Lag < 59*time.Minute || freshness.Lag > 62*time.Minute {
+		t.Errorf("Expected lag of about an hour, got %s", freshness.Lag)
+	}
+
+	empty = true
+	if _, err := client.EstimateFreshness(context.Background(), params); !errors.Is(err, constellation.ErrNoRecords) {
+		t.Errorf("Expected ErrNoRecords, got: %v", err)
+	}
+}
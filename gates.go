@@ -0,0 +1,50 @@
+package constellation
+
+import (
+	"context"
+	"fmt"
+)
+
+// ThreadgateOf returns the URI of the threadgate restricting replies to the post
+// at postURI, or an empty string if replies are unrestricted. Only a gate from
+// the post's author counts, so gates other accounts create are ignored.
+func (c *Client) ThreadgateOf(ctx context.Context, postURI string) (string, error) {
+	return c.gateOf(ctx, CollectionThreadgate, postURI)
+}
+
+// PostgateOf returns the URI of the postgate restricting how the post at postURI
+// may be embedded, or an empty string if it has none. Only a gate from the
+// post's author counts.
+func (c *Client) PostgateOf(ctx context.Context, postURI string) (string, error) {
+	return c.gateOf(ctx, CollectionPostgate, postURI)
+}
+
+// IsQuoteDetached reports whether the author of the post at quotedURI detached
+// the quote post at quoteURI through their postgate, which hides the quoted post
+// from the quote
+func (c *Client) IsQuoteDetached(ctx context.Context, quoteURI, quotedURI string) (bool, error) {
+	quoted, err := ParseATURI(quotedURI)
+	if err != nil {
+		return false, err
+	}
+	detached, _, err := c.HasLink(ctx, quoted.DID, LinksParams{
+		Target:     quoteURI,
+		Collection: CollectionPostgate,
+		Path:       PathDetachedEmbeddingURIs,
+	})
+	return detached, err
+}
+
+// gateOf returns the URI of the author's gate record from collection for the
+// post at postURI
+func (c *Client) gateOf(ctx context.Context, collection, postURI string) (string, error) {
+	post, err := ParseATURI(postURI)
+	if err != nil {
+		return "", err
+	}
+	if post.Collection != CollectionPost {
+		return "", fmt.Errorf("not a post URI: %s", postURI)
+	}
+	_, uri, err := c.HasLink(ctx, post.DID, LinksParams{Target: postURI, Collection: collection, Path: PathPost})
+	return uri, err
+}
@@ -0,0 +1,60 @@
+package constellation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestGates tests threadgate, postgate, and detached quote lookups
+func TestGates(t *testing.T) {
+	const post = "at://did:plc:alice/app.bsky.feed.post/1"
+	const quote = "at://did:plc:bob/app.bsky.feed.post/2"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("collection") == constellation.CollectionThreadgate && query.Get("path") == constellation.PathPost:
+			// A stranger's gate is listed first and must be ignored
+			w.Write([]byte(`{"linking_records": [
+				{"did": "did:plc:mallory", "collection": "app.bsky.feed.threadgate", "rkey": "1"},
+				{"did": "did:plc:alice", "collection": "app.bsky.feed.threadgate", "rkey": "1"}
+			]}`))
+		case query.Get("collection") == constellation.CollectionPostgate && query.Get("path") == constellation.PathDetachedEmbeddingURIs && query.Get("target") == quote:
+			w.Write([]byte(`{"linking_records": [{"did": "did:plc:alice", "collection": "app.bsky.feed.postgate", "rkey": "1"}]}`))
+		default:
+			w.Write([]byte(`{"linking_records": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	ctx := context.Background()
+
+	gate, err := client.ThreadgateOf(ctx, post)
+	if err != nil {
+		t.Fatalf("ThreadgateOf failed: %v", err)
+	}
+	if want := "at://did:plc:alice/app.bsky.feed.threadgate/1"; gate != want {
+		t.Errorf("Expected threadgate %s, got %q", want, gate)
+	}
+
+	if gate, err := client.PostgateOf(ctx, post); err != nil || gate != "" {
+		t.Errorf("Expected no postgate, got %q, %v", gate, err)
+	}
+
+	if detached, err := client.IsQuoteDetached(ctx, quote, post); err != nil || !detached {
+		t.Errorf("Expected the quote to be detached, got %v, %v", detached, err)
+	}
+	if detached, err := client.IsQuoteDetached(ctx, "at://did:plc:carol/app.bsky.feed.post/3", post); err != nil || detached {
+		t.Errorf("Expected the quote not to be detached, got %v, %v", detached, err)
+	}
+
+	if _, err := client.ThreadgateOf(ctx, "at://did:plc:alice/app.bsky.feed.like/1"); err == nil {
+		t.Error("Expected an error for a non-post URI")
+	}
+}
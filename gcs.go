@@ -0,0 +1,64 @@
+package constellation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultGCSEndpoint is the Google Cloud Storage JSON API endpoint
+const DefaultGCSEndpoint = "https://storage.googleapis.com"
+
+// GCSStore is a BlobStore for Google Cloud Storage using the JSON API's media
+// upload. Blobs are streamed without buffering.
+type GCSStore struct {
+	Bucket string
+
+	// Token returns an OAuth 2.0 access token with a storage write scope, e.g.
+	// from golang.org/x/oauth2/google's token source
+	Token func(ctx context.Context) (string, error)
+
+	Endpoint   string       // Defaults to DefaultGCSEndpoint
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+}
+
+// PutBlob uploads everything read from r to the object name
+func (s *GCSStore) PutBlob(ctx context.Context, name string, r io.Reader) error {
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultGCSEndpoint
+	}
+	query := url.Values{"uploadType": {"media"}, "name": {name}}
+	uploadURL := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?%s", strings.TrimSuffix(endpoint, "/"), url.PathEscape(s.Bucket), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, r)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if s.Token != nil {
+		token, err := s.Token(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get access token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package constellation_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestGCSStorePutBlob tests the media upload sent to Cloud Storage
+func TestGCSStorePutBlob(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/upload/storage/v1/b/lake/o" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("uploadType") != "media" || r.URL.Query().Get("name") != "exports/likes.jsonl" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Unexpected Authorization %q", r.Header.Get("Authorization"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "{}\n" {
+			t.Errorf("Unexpected body %q", body)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	store := &constellation.GCSStore{
+		Bucket:   "lake",
+		Endpoint: server.URL,
+		Token:    func(context.Context) (string, error) { return "token", nil },
+	}
+	if err := store.PutBlob(context.Background(), "exports/likes.jsonl", strings.NewReader("{}\n")); err != nil {
+		t.Fatalf("Failed to put blob: %v", err)
+	}
+}
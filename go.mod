@@ -1,3 +1,3 @@
 module github.com/tanner-caffrey/constellation-go
 
-go 1.22.2
+go 1.20
@@ -0,0 +1,197 @@
+package constellation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultPLCDirectory is the default directory for resolving did:plc identities
+const DefaultPLCDirectory = "https://plc.directory"
+
+// ErrHandleMismatch is returned by VerifiedHandle when the handle a DID document
+// claims doesn't resolve back to the DID
+var ErrHandleMismatch = errors.New("handle does not resolve to its DID")
+
+// DIDDocument is the subset of a DID document used by this library
+type DIDDocument struct {
+	ID                 string               `json:"id"`
+	AlsoKnownAs        []string             `json:"alsoKnownAs"`
+	VerificationMethod []VerificationMethod `json:"verificationMethod"`
+	Service            []DIDService         `json:"service"`
+}
+
+// VerificationMethod is a public key listed in a DID document
+type VerificationMethod struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	Controller         string `json:"controller"`
+	PublicKeyMultibase string `json:"publicKeyMultibase"`
+}
+
+// DIDService is a service endpoint listed in a DID document, such as the PDS
+type DIDService struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+// Handle returns the handle claimed by the document, or "" if it claims none.
+// The claim isn't verified against the handle's DNS or well-known record; see
+// Client.VerifiedHandle.
+func (d *DIDDocument) Handle() string {
+	for _, aka := range d.AlsoKnownAs {
+		if handle, ok := strings.CutPrefix(aka, "at://"); ok {
+			return handle
+		}
+	}
+	return ""
+}
+
+// ResolveDID fetches the DID document for a did:plc or did:web identity
+func (c *Client) ResolveDID(ctx context.Context, did string) (*DIDDocument, error) {
+	var docURL string
+	switch {
+	case strings.HasPrefix(did, "did:plc:"):
+		directory := c.PLCDirectory
+		if directory == "" {
+			directory = DefaultPLCDirectory
+		}
+		docURL = directory + "/" + did
+	case strings.HasPrefix(did, "did:web:"):
+		var err error
+		if docURL, err = didWebURL(did); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported DID method: %s", did)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", docURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", did, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	var doc DIDDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode DID document: %w", err)
+	}
+	return &doc, nil
+}
+
+// didWebURL returns the URL of a did:web document. The first colon-separated
+// segment is the percent-encoded host, with any port as %3A; further segments
+// form a path holding did.json, and without them the document is served from
+// /.well-known/did.json.
+func didWebURL(did string) (string, error) {
+	segments := strings.Split(strings.TrimPrefix(did, "did:web:"), ":")
+	host, err := url.PathUnescape(segments[0])
+	if err != nil || host == "" || strings.ContainsAny(host, "/?#@") {
+		return "", fmt.Errorf("invalid did:web host: %s", did)
+	}
+	if len(segments) == 1 {
+		return "https://" + host + "/.well-known/did.json", nil
+	}
+
+	path := make([]string, len(segments)-1)
+	for i, segment := range segments[1:] {
+		if path[i], err = url.PathUnescape(segment); err != nil || path[i] == "" {
+			return "", fmt.Errorf("invalid did:web path: %s", did)
+		}
+		path[i] = url.PathEscape(path[i])
+	}
+	return "https://" + host + "/" + strings.Join(path, "/") + "/did.json", nil
+}
+
+// ResolveHandle returns the handle claimed by did's DID document, without
+// checking the claim; use VerifiedHandle before displaying it to others
+func (c *Client) ResolveHandle(ctx context.Context, did string) (string, error) {
+	doc, err := c.ResolveDID(ctx, did)
+	if err != nil {
+		return "", err
+	}
+	return doc.Handle(), nil
+}
+
+// ResolveHandleDID returns the DID a handle points to, from its _atproto DNS TXT
+// record or, failing that, its /.well-known/atproto-did HTTPS endpoint
+func (c *Client) ResolveHandleDID(ctx context.Context, handle string) (string, error) {
+	handle = strings.ToLower(strings.TrimSuffix(handle, "."))
+	if handle == "" || !strings.Contains(handle, ".") || strings.ContainsAny(handle, "/?#@: ") {
+		return "", fmt.Errorf("invalid handle: %q", handle)
+	}
+
+	if records, err := net.DefaultResolver.LookupTXT(ctx, "_atproto."+handle); err == nil {
+		for _, record := range records {
+			if did, ok := strings.CutPrefix(record, "did="); ok {
+				return strings.TrimSpace(did), nil
+			}
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://"+handle+"/.well-known/atproto-did", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve handle %s: %w", handle, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", &APIError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 2048))
+	if err != nil {
+		return "", fmt.Errorf("failed to read handle DID: %w", err)
+	}
+	did := strings.TrimSpace(string(body))
+	if !strings.HasPrefix(did, "did:") {
+		return "", fmt.Errorf("handle %s has no DID", handle)
+	}
+	return did, nil
+}
+
+// VerifiedHandle returns the handle claimed by did's DID document after checking
+// that the handle resolves back to did, as atproto requires before displaying a
+// handle. It returns an error wrapping ErrHandleMismatch when it doesn't, since
+// any account can claim any handle in its own document.
+func (c *Client) VerifiedHandle(ctx context.Context, did string) (string, error) {
+	handle, err := c.ResolveHandle(ctx, did)
+	if err != nil {
+		return "", err
+	}
+	if handle == "" {
+		return "", fmt.Errorf("%s claims no handle", did)
+	}
+
+	resolved, err := c.ResolveHandleDID(ctx, handle)
+	if err != nil {
+		return "", err
+	}
+	if resolved != did {
+		return "", fmt.Errorf("%w: %s resolves to %s, not %s", ErrHandleMismatch, handle, resolved, did)
+	}
+	return handle, nil
+}
@@ -0,0 +1,85 @@
+package constellation_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// TestResolveDIDWeb tests mapping did:web identifiers to document URLs
+func TestResolveDIDWeb(t *testing.T) {
+	tests := []struct {
+		did  string
+		want string
+	}{
+		{"did:web:example.com", "https://example.com/.well-known/did.json"},
+		{"did:web:localhost%3A8080", "https://localhost:8080/.well-known/did.json"},
+		{"did:web:example.com:user:alice", "https://example.com/user/alice/did.json"},
+		{"did:web:example.com%3A443:u:a%20b", "https://example.com:443/u/a%20b/did.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.did, func(t *testing.T) {
+			client := constellation.NewClient()
+			var requested string
+			client.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				requested = r.URL.String()
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"id": "` + tt.did + `"}`)),
+				}, nil
+			})}
+
+			if _, err := client.ResolveDID(context.Background(), tt.did); err != nil {
+				t.Fatalf("Failed to resolve: %v", err)
+			}
+			if requested != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, requested)
+			}
+		})
+	}
+
+	if _, err := constellation.NewClient().ResolveDID(context.Background(), "did:web:"); err == nil {
+		t.Error("Expected an error for an empty host")
+	}
+}
+
+// TestVerifiedHandle tests that a claimed handle is only returned when it
+// resolves back to the DID
+func TestVerifiedHandle(t *testing.T) {
+	client := constellation.NewClient()
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		body := ""
+		switch r.URL.String() {
+		case constellation.DefaultPLCDirectory + "/did:plc:alice":
+			body = `{"id": "did:plc:alice", "alsoKnownAs": ["at://alice.test"]}`
+		case constellation.DefaultPLCDirectory + "/did:plc:mallory":
+			body = `{"id": "did:plc:mallory", "alsoKnownAs": ["at://alice.test"]}`
+		case "https://alice.test/.well-known/atproto-did":
+			body = "did:plc:alice\n"
+		default:
+			return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: io.NopCloser(strings.NewReader(""))}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+
+	ctx := context.Background()
+	if handle, err := client.VerifiedHandle(ctx, "did:plc:alice"); err != nil || handle != "alice.test" {
+		t.Errorf("Expected alice.test, got %q, %v", handle, err)
+	}
+	if handle, err := client.VerifiedHandle(ctx, "did:plc:mallory"); !errors.Is(err, constellation.ErrHandleMismatch) {
+		t.Errorf("Expected ErrHandleMismatch for a spoofed handle, got %q, %v", handle, err)
+	}
+}
@@ -0,0 +1,184 @@
+package constellation
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// LocalIndex is a reverse index from link targets to the records in a JSON Lines
+// export that link to them. Build it once and save it beside the export, so
+// repeated analyses look records up by target instead of rescanning the export.
+// Links are found by walking each record's Value, so records exported without
+// values aren't indexed.
+type LocalIndex struct {
+	Entries map[string][]IndexEntry `json:"entries"` // Keyed by target
+}
+
+// IndexEntry locates a record linking to a target
+type IndexEntry struct {
+	Collection string `json:"collection"`
+	Path       string `json:"path"`   // Path to the link within the record, e.g. ".subject.uri"
+	Offset     int64  `json:"offset"` // Byte offset of the record's line in the export
+}
+
+// BuildLocalIndex indexes a JSON Lines export of LinkRecords read from r
+func BuildLocalIndex(r io.Reader) (*LocalIndex, error) {
+	index := &LocalIndex{Entries: make(map[string][]IndexEntry)}
+	reader := bufio.NewReader(r)
+
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			var record LinkRecord
+			if err := json.Unmarshal(line, &record); err != nil {
+				return nil, fmt.Errorf("failed to decode export line at offset %d: %w", offset, err)
+			}
+			walkLinks(record.Value, "", func(path, target string) {
+				index.Entries[target] = append(index.Entries[target], IndexEntry{
+					Collection: record.Collection,
+					Path:       path,
+					Offset:     offset,
+				})
+			})
+		}
+		offset += int64(len(line))
+
+		if errors.Is(err, io.EOF) {
+			return index, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read export: %w", err)
+		}
+	}
+}
+
+// BuildLocalIndexFile indexes the JSON Lines export at path
+func BuildLocalIndexFile(path string) (*LocalIndex, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open export: %w", err)
+	}
+	defer file.Close()
+
+	return BuildLocalIndex(file)
+}
+
+// walkLinks calls fn for every string in value that looks like a link target,
+// with the path to it in Constellation's notation, where array elements with a
+// $type, such as facet features, are named by it: ".features[app.bsky.richtext.facet#link]"
+func walkLinks(value any, path string, fn func(path, target string)) {
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			walkLinks(v[key], path+"."+key, fn)
+		}
+	case []any:
+		for _, item := range v {
+			element := "[]"
+			if object, ok := item.(map[string]any); ok {
+				if typ, ok := object["$type"].(string); ok && typ != "" {
+					element = "[" + typ + "]"
+				}
+			}
+			walkLinks(item, path+element, fn)
+		}
+	case string:
+		if isLinkTarget(v) {
+			fn(path, v)
+		}
+	}
+}
+
+// isLinkTarget reports whether s is an AT URI, DID, or web URL
+func isLinkTarget(s string) bool {
+	return strings.HasPrefix(s, "at://") || strings.HasPrefix(s, "did:") ||
+		strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
+
+// Lookup returns the entries for params.Target, restricted to params.Collection
+// and params.Path when they are set. Path is inferred as for API queries.
+func (ix *LocalIndex) Lookup(params LinksParams) []IndexEntry {
+	params = params.withInferredPath()
+	var entries []IndexEntry
+	for _, entry := range ix.Entries[params.Target] {
+		if params.Collection != "" && entry.Collection != params.Collection {
+			continue
+		}
+		if params.Path != "" && entry.Path != params.Path {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Count returns the number of entries matching params, like GetLinksCount
+func (ix *LocalIndex) Count(params LinksParams) int {
+	return len(ix.Lookup(params))
+}
+
+// Targets returns the number of distinct targets in the index
+func (ix *LocalIndex) Targets() int {
+	return len(ix.Entries)
+}
+
+// Save writes the index to path atomically
+func (ix *LocalIndex) Save(path string) error {
+	data, err := json.Marshal(ix)
+	if err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to save index: %w", err)
+	}
+	return nil
+}
+
+// LoadLocalIndex reads an index saved with Save
+func LoadLocalIndex(path string) (*LocalIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load index: %w", err)
+	}
+
+	var index LocalIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to decode index: %w", err)
+	}
+	if index.Entries == nil {
+		index.Entries = make(map[string][]IndexEntry)
+	}
+	return &index, nil
+}
+
+// ReadIndexedRecords reads the records located by entries from the export they
+// were indexed from
+func ReadIndexedRecords(export io.ReaderAt, entries []IndexEntry) ([]LinkRecord, error) {
+	records := make([]LinkRecord, 0, len(entries))
+	for _, entry := range entries {
+		reader := bufio.NewReader(io.NewSectionReader(export, entry.Offset, 1<<62))
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read record at offset %d: %w", entry.Offset, err)
+		}
+
+		var record LinkRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, fmt.Errorf("failed to decode record at offset %d: %w", entry.Offset, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
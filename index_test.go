@@ -0,0 +1,57 @@
+package constellation_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestLocalIndex tests building, saving, and querying a reverse index of an export
+func TestLocalIndex(t *testing.T) {
+	export := `{"did": "did:plc:alice", "collection": "app.bsky.feed.like", "rkey": "1", "value": {"subject": {"uri": "at://did:plc:creator/app.bsky.feed.post/1"}}}
+{"did": "did:plc:bob", "collection": "app.bsky.feed.post", "rkey": "2", "value": {"text": "hi @creator example.com", "facets": [{"index": {"byteStart": 3, "byteEnd": 11}, "features": [{"$type": "app.bsky.richtext.facet#mention", "did": "did:plc:creator"}]}, {"index": {"byteStart": 12, "byteEnd": 23}, "features": [{"$type": "app.bsky.richtext.facet#link", "uri": "https://example.com/a"}]}]}}
+
+{"did": "did:plc:carol", "collection": "app.bsky.feed.like", "rkey": "3", "value": {"subject": {"uri": "at://did:plc:creator/app.bsky.feed.post/1"}}}
+`
+	index, err := constellation.BuildLocalIndex(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("Failed to build index: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "export.idx")
+	if err := index.Save(path); err != nil {
+		t.Fatalf("Failed to save index: %v", err)
+	}
+	index, err = constellation.LoadLocalIndex(path)
+	if err != nil {
+		t.Fatalf("Failed to load index: %v", err)
+	}
+
+	if index.Targets() != 3 {
+		t.Errorf("Expected 3 targets, got %d", index.Targets())
+	}
+
+	likes := index.Lookup(constellation.LinksParams{
+		Target:     "at://did:plc:creator/app.bsky.feed.post/1",
+		Collection: "app.bsky.feed.like",
+		Path:       ".subject.uri",
+	})
+	records, err := constellation.ReadIndexedRecords(strings.NewReader(export), likes)
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if len(records) != 2 || records[0].DID != "did:plc:alice" || records[1].DID != "did:plc:carol" {
+		t.Errorf("Expected alice's and carol's likes, got %+v", records)
+	}
+
+	mentions := index.Count(constellation.LinksParams{Target: "did:plc:creator", Path: ".facets[].features[app.bsky.richtext.facet#mention].did"})
+	if mentions != 1 {
+		t.Errorf("Expected 1 mention, got %d", mentions)
+	}
+	links := index.Count(constellation.LinksParams{Target: "https://example.com/a", Collection: constellation.CollectionPost, Path: constellation.PathFacetLinkURI})
+	if links != 1 {
+		t.Errorf("Expected 1 link at the well-known facet path, got %d", links)
+	}
+}
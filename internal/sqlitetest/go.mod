@@ -0,0 +1,25 @@
+module github.com/tanner-caffrey/constellation-go/internal/sqlitetest
+
+go 1.23.0
+
+require (
+	github.com/tanner-caffrey/constellation-go v0.0.0
+	modernc.org/sqlite v1.38.2
+)
+
+require (
+	github.com/dustin/go-humanize v1.0.1 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/ncruces/go-strftime v0.1.9 // indirect
+	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
+	golang.org/x/exp v0.0.0-20250620022241-b7579e27df2b // indirect
+	golang.org/x/sys v0.34.0 // indirect
+	modernc.org/libc v1.66.3 // indirect
+	modernc.org/mathutil v1.7.1 // indirect
+	modernc.org/memory v1.11.0 // indirect
+)
+
+// This module only holds tests and is never required by another module, so it
+// always tests the client in this tree
+replace github.com/tanner-caffrey/constellation-go => ../..
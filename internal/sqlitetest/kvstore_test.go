@@ -0,0 +1,48 @@
+package sqlitetest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+	"github.com/tanner-caffrey/constellation-go/constellationtest"
+	"github.com/tanner-caffrey/constellation-go/internal/sqlitetest"
+)
+
+// TestSQLKV runs the store conformance checks against SQLKV, including
+// overwriting keys through its upsert
+func TestSQLKV(t *testing.T) {
+	store, err := constellation.NewSQLKV(context.Background(), sqlitetest.Open(t))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	constellationtest.TestKVStore(t, store)
+}
+
+// TestSQLCursorStore tests the SQL cursor store against SQLite,
+// including reopening a database whose table already exists
+func TestSQLCursorStore(t *testing.T) {
+	ctx := context.Background()
+	db := sqlitetest.Open(t)
+
+	store, err := constellation.NewSQLCursorStore(ctx, db)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	constellationtest.TestCursorStore(t, store)
+
+	if err := store.SaveCursor(ctx, "export", "c1"); err != nil {
+		t.Fatalf("Failed to save cursor: %v", err)
+	}
+	if err := store.SaveCursor(ctx, "export", "c2"); err != nil {
+		t.Fatalf("Failed to replace cursor: %v", err)
+	}
+
+	reopened, err := constellation.NewSQLCursorStore(ctx, db)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	if cursor, err := reopened.LoadCursor(ctx, "export"); err != nil || cursor != "c2" {
+		t.Errorf("Expected the replaced cursor to survive reopening, got %q, %v", cursor, err)
+	}
+}
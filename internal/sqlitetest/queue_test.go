@@ -0,0 +1,384 @@
+package sqlitetest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+	"github.com/tanner-caffrey/constellation-go/internal/sqlitetest"
+	"github.com/tanner-caffrey/constellation-go/queue"
+)
+
+// newQueue creates a queue on a new database with short delays
+func newQueue(t *testing.T) *queue.Queue {
+	t.Helper()
+	q, err := queue.New(context.Background(), sqlitetest.Open(t))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	q.PollInterval = time.Millisecond
+	q.RetryDelay = 20 * time.Millisecond
+	return q
+}
+
+// TestClaim tests claiming tasks in order, each once
+func TestClaim(t *testing.T) {
+	q := newQueue(t)
+	ctx := context.Background()
+	first, _ := q.Enqueue(ctx, "export", []byte("a"))
+	second, _ := q.Enqueue(ctx, "export", []byte("b"))
+
+	for _, want := range []int64{first, second} {
+		task, err := q.Claim(ctx)
+		if err != nil || task == nil {
+			t.Fatalf("Claim failed: %v", err)
+		}
+		if task.ID != want || task.Attempts != 1 || task.Kind != "export" {
+			t.Errorf("Expected task %d on its first attempt, got %+v", want, task)
+		}
+	}
+	if task, err := q.Claim(ctx); task != nil || err != nil {
+		t.Errorf("Expected no visible tasks, got %+v, %v", task, err)
+	}
+}
+
+// TestFailRetry tests backoff between attempts and failing after MaxAttempts
+func TestFailRetry(t *testing.T) {
+	q := newQueue(t)
+	q.MaxAttempts = 2
+	ctx := context.Background()
+	q.Enqueue(ctx, "export", nil)
+
+	task, _ := q.Claim(ctx)
+	if err := q.Fail(ctx, task, errors.New("boom")); err != nil {
+		t.Fatalf("Fail failed: %v", err)
+	}
+	if retry, _ := q.Claim(ctx); retry != nil {
+		t.Errorf("Expected the retry to wait out its backoff, got %+v", retry)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	task, _ = q.Claim(ctx)
+	if task == nil || task.Attempts != 2 || task.LastError != "boom" {
+		t.Fatalf("Expected a second attempt after the backoff, got %+v", task)
+	}
+	if err := q.Fail(ctx, task, errors.New("boom again")); err != nil {
+		t.Fatalf("Fail failed: %v", err)
+	}
+	if counts, _ := q.Counts(ctx); counts[queue.StateFailed] != 1 {
+		t.Errorf("Expected the task to fail after MaxAttempts, got %v", counts)
+	}
+
+	// Tasks that were never claimed can be failed without panicking
+	id, _ := q.Enqueue(ctx, "export", nil)
+	if err := q.Fail(ctx, &queue.Task{ID: id}, errors.New("rejected")); err != nil {
+		t.Errorf("Expected an unclaimed task to fail cleanly, got %v", err)
+	}
+}
+
+// TestComplete tests completing a task, and refusing once its claim is lost
+func TestComplete(t *testing.T) {
+	q := newQueue(t)
+	q.VisibilityTimeout = 10 * time.Millisecond
+	ctx := context.Background()
+	q.Enqueue(ctx, "export", nil)
+
+	stale, _ := q.Claim(ctx)
+	time.Sleep(20 * time.Millisecond)
+	current, _ := q.Claim(ctx)
+	if current == nil || current.ID != stale.ID {
+		t.Fatalf("Expected the expired claim to be taken over, got %+v", current)
+	}
+
+	if err := q.Complete(ctx, stale); !errors.Is(err, queue.ErrClaimLost) {
+		t.Errorf("Expected ErrClaimLost for the stale claim, got %v", err)
+	}
+	if err := q.Fail(ctx, stale, errors.New("late")); !errors.Is(err, queue.ErrClaimLost) {
+		t.Errorf("Expected ErrClaimLost failing the stale claim, got %v", err)
+	}
+	if err := q.Complete(ctx, current); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if counts, _ := q.Counts(ctx); counts[queue.StateDone] != 1 || counts[queue.StatePending] != 0 {
+		t.Errorf("Expected the task to be done, got %v", counts)
+	}
+}
+
+// TestExtend tests renewing a claim past the original visibility timeout
+func TestExtend(t *testing.T) {
+	q := newQueue(t)
+	q.VisibilityTimeout = 30 * time.Millisecond
+	ctx := context.Background()
+	q.Enqueue(ctx, "export", nil)
+
+	task, _ := q.Claim(ctx)
+	time.Sleep(20 * time.Millisecond)
+	// Extending a copy, as a handler would, keeps the original's claim current
+	handlerCopy := *task
+	if err := q.Extend(ctx, &handlerCopy); err != nil {
+		t.Fatalf("Extend failed: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if other, _ := q.Claim(ctx); other != nil {
+		t.Errorf("Expected the extended claim to stay hidden, got %+v", other)
+	}
+	if err := q.Complete(ctx, task); err != nil {
+		t.Errorf("Complete failed after Extend: %v", err)
+	}
+
+	if err := q.Extend(ctx, &queue.Task{ID: task.ID}); err == nil {
+		t.Error("Expected an error extending an unclaimed task")
+	}
+}
+
+// TestClaimAbandoned tests that a task whose claims keep expiring is failed
+// rather than reclaimed forever
+func TestClaimAbandoned(t *testing.T) {
+	q := newQueue(t)
+	q.MaxAttempts = 2
+	q.VisibilityTimeout = 5 * time.Millisecond
+	ctx := context.Background()
+	q.Enqueue(ctx, "export", nil)
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		if task, _ := q.Claim(ctx); task == nil || task.Attempts != attempt {
+			t.Fatalf("Expected attempt %d, got %+v", attempt, task)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if task, err := q.Claim(ctx); task != nil || err != nil {
+		t.Errorf("Expected no claim after MaxAttempts, got %+v, %v", task, err)
+	}
+	if counts, _ := q.Counts(ctx); counts[queue.StateFailed] != 1 {
+		t.Errorf("Expected the abandoned task to be failed, got %v", counts)
+	}
+}
+
+// TestClaimConcurrent tests that workers on separate connections claiming at
+// once each get a different task, without lock errors
+func TestClaimConcurrent(t *testing.T) {
+	q := newQueue(t)
+	ctx := context.Background()
+	const tasks = 20
+	for i := 0; i < tasks; i++ {
+		q.Enqueue(ctx, "export", nil)
+	}
+
+	var mu sync.Mutex
+	claimed := make(map[int64]bool)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				task, err := q.Claim(ctx)
+				if err != nil {
+					t.Errorf("Claim failed: %v", err)
+					return
+				}
+				if task == nil {
+					return
+				}
+				mu.Lock()
+				if claimed[task.ID] {
+					t.Errorf("Task %d was claimed twice", task.ID)
+				}
+				claimed[task.ID] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(claimed) != tasks {
+		t.Errorf("Expected all %d tasks claimed, got %d", tasks, len(claimed))
+	}
+}
+
+// TestClaimRollback tests that a claim failing partway leaves no trace: tasks
+// it marked abandoned stay pending and get no dead letter until a claim commits
+func TestClaimRollback(t *testing.T) {
+	q := newQueue(t)
+	q.MaxAttempts = 1
+	q.VisibilityTimeout = 5 * time.Millisecond
+	store := constellation.NewMemoryKV()
+	q.DeadLetters = store
+	ctx := context.Background()
+	abandoned, _ := q.Enqueue(ctx, "export", nil)
+	q.Claim(ctx)
+	time.Sleep(10 * time.Millisecond)
+	next, _ := q.Enqueue(ctx, "export", nil)
+
+	// Make claiming the next task fail after the abandoned one is marked failed
+	_, err := q.DB.ExecContext(ctx, `CREATE TRIGGER fail_claim BEFORE UPDATE OF attempts ON constellation_tasks
+		WHEN NEW.attempts > OLD.attempts BEGIN SELECT RAISE(ABORT, 'injected'); END`)
+	if err != nil {
+		t.Fatalf("Failed to create trigger: %v", err)
+	}
+	if task, err := q.Claim(ctx); err == nil {
+		t.Fatalf("Expected the claim to fail, got %+v", task)
+	}
+	if counts, _ := q.Counts(ctx); counts[queue.StatePending] != 2 || counts[queue.StateFailed] != 0 {
+		t.Errorf("Expected the failed claim to be rolled back, got %v", counts)
+	}
+	if _, err := store.Get(ctx, "deadletter/"+strconv.FormatInt(abandoned, 10)); !errors.Is(err, constellation.ErrNotFound) {
+		t.Errorf("Expected no dead letter for a rolled back claim, got %v", err)
+	}
+
+	if _, err := q.DB.ExecContext(ctx, `DROP TRIGGER fail_claim`); err != nil {
+		t.Fatalf("Failed to drop trigger: %v", err)
+	}
+	if task, err := q.Claim(ctx); err != nil || task == nil || task.ID != next {
+		t.Fatalf("Expected to claim task %d, got %+v, %v", next, task, err)
+	}
+	if _, err := store.Get(ctx, "deadletter/"+strconv.FormatInt(abandoned, 10)); err != nil {
+		t.Errorf("Expected a dead letter once the claim committed, got %v", err)
+	}
+}
+
+// TestDeadLetters tests that failed and abandoned tasks are written to the
+// dead-letter store
+func TestDeadLetters(t *testing.T) {
+	q := newQueue(t)
+	q.MaxAttempts = 1
+	q.VisibilityTimeout = 5 * time.Millisecond
+	store := constellation.NewMemoryKV()
+	q.DeadLetters = store
+	ctx := context.Background()
+	failed, _ := q.Enqueue(ctx, "export", []byte("a"))
+	abandoned, _ := q.Enqueue(ctx, "export", []byte("b"))
+
+	task, _ := q.Claim(ctx)
+	q.Fail(ctx, task, errors.New("boom"))
+	q.Claim(ctx)
+	time.Sleep(10 * time.Millisecond)
+	q.Claim(ctx)
+
+	for id, want := range map[int64]string{failed: "boom", abandoned: "claim expired after 1 attempts"} {
+		data, err := store.Get(ctx, "deadletter/"+strconv.FormatInt(id, 10))
+		if err != nil {
+			t.Fatalf("Expected a dead letter for task %d: %v", id, err)
+		}
+		var letter queue.DeadLetter
+		if err := json.Unmarshal(data, &letter); err != nil {
+			t.Fatalf("Failed to decode dead letter: %v", err)
+		}
+		if letter.ID != id || letter.LastError != want || letter.FailedAt.IsZero() {
+			t.Errorf("Expected task %d failed with %q, got %+v", id, want, letter)
+		}
+	}
+}
+
+// TestRun tests processing tasks until cancelled
+func TestRun(t *testing.T) {
+	q := newQueue(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		q.Enqueue(ctx, "export", nil)
+	}
+
+	var handled atomic.Int64
+	err := q.Run(ctx, 2, func(ctx context.Context, task queue.Task) error {
+		if handled.Add(1) == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || handled.Load() != 3 {
+		t.Errorf("Expected 3 tasks handled before cancellation, got %d, %v", handled.Load(), err)
+	}
+}
+
+// TestRunStopsOnQueueError tests that a queue error stops the other workers
+func TestRunStopsOnQueueError(t *testing.T) {
+	db := sqlitetest.Open(t)
+	q, err := queue.New(context.Background(), db)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	q.PollInterval = time.Millisecond
+	q.Enqueue(context.Background(), "export", nil)
+
+	// One worker blocks in its handler until cancelled; closing the database
+	// makes the other's next claim fail
+	claimed := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- q.Run(context.Background(), 2, func(ctx context.Context, task queue.Task) error {
+			close(claimed)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}()
+
+	<-claimed
+	db.Close()
+	select {
+	case err := <-done:
+		if err == nil || errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the queue error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run didn't stop after a queue error")
+	}
+}
+
+// TestRunSkipsLostClaim tests that a lost claim is logged without stopping Run
+func TestRunSkipsLostClaim(t *testing.T) {
+	q := newQueue(t)
+	var logs bytes.Buffer
+	q.Logger = log.New(&logs, "", 0)
+	q.VisibilityTimeout = 5 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first, _ := q.Enqueue(ctx, "export", nil)
+
+	err := q.Run(ctx, 1, func(ctx context.Context, task queue.Task) error {
+		if task.ID == first && task.Attempts == 1 {
+			// Simulate another worker taking over after the claim expired
+			time.Sleep(10 * time.Millisecond)
+			q.VisibilityTimeout = time.Hour
+			if stolen, _ := q.Claim(ctx); stolen == nil || stolen.ID != first {
+				t.Errorf("Expected to take over task %d, got %+v", first, stolen)
+			}
+			q.Enqueue(ctx, "export", nil)
+			return nil
+		}
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Run to continue past the lost claim, got %v", err)
+	}
+	if !strings.Contains(logs.String(), "claim expired") {
+		t.Errorf("Expected the lost claim to be logged, got %q", logs.String())
+	}
+}
+
+// TestRunReleasesOnShutdown tests that interrupted tasks don't burn an attempt
+func TestRunReleasesOnShutdown(t *testing.T) {
+	q := newQueue(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	q.Enqueue(ctx, "export", nil)
+
+	q.Run(ctx, 1, func(ctx context.Context, task queue.Task) error {
+		cancel()
+		return ctx.Err()
+	})
+
+	task, err := q.Claim(context.Background())
+	if err != nil || task == nil || task.Attempts != 1 || task.LastError != "" {
+		t.Errorf("Expected the interrupted task back on its first attempt, got %+v, %v", task, err)
+	}
+}
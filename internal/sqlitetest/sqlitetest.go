@@ -0,0 +1,28 @@
+// Package sqlitetest runs the queue and the SQL-backed stores against a real
+// SQLite engine, modernc.org/sqlite, so transactions, locking, and SQL syntax
+// are checked the way they run in production. It's its own module so the client
+// doesn't depend on a SQLite driver.
+package sqlitetest
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// Open opens a new SQLite database file in the test's temporary directory,
+// closed when the test ends. It uses immediate transactions and a busy timeout,
+// as the queue documentation recommends, so concurrent connections wait for
+// each other's writes.
+func Open(t testing.TB) *sql.DB {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite", "file:"+path+"?_txlock=immediate&_pragma=busy_timeout(5000)")
+	if err != nil {
+		t.Fatalf("Failed to open SQLite database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
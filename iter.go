@@ -0,0 +1,89 @@
+//go:build go1.23
+
+// Iterator APIs need range-over-func from Go 1.23. Each has a callback and a
+// channel equivalent that builds with older toolchains: Links has GetLinksEach
+// and GetLinksChan, LinkingDIDs has GetDistinctDIDsEach and GetDistinctDIDsChan,
+// TypedLinks has TypedLinksEach, and DecodedLinks has DecodedLinksEach.
+
+package constellation
+
+import (
+	"context"
+	"iter"
+)
+
+// Links returns an iterator over every record linking to a target, fetching pages
+// lazily as the caller ranges. Iteration ends when the results are exhausted, a
+// limit in opts is reached, or the caller breaks. A failed request is yielded once
+// as a non-nil error, after which iteration stops.
+//
+//	for record, err := range client.Links(ctx, params, constellation.PaginateOptions{}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(record.URI)
+//	}
+func (c *Client) Links(ctx context.Context, params LinksParams, opts PaginateOptions) iter.Seq2[LinkRecord, error] {
+	return seq(func(emit func(LinkRecord) error) error {
+		return c.eachLink(ctx, params, opts, emit)
+	})
+}
+
+// LinkingDIDs returns an iterator over the distinct DIDs linking to a target,
+// fetching pages lazily as the caller ranges. It behaves like Links.
+func (c *Client) LinkingDIDs(ctx context.Context, params LinksParams, opts PaginateOptions) iter.Seq2[string, error] {
+	return seq(func(emit func(string) error) error {
+		return paginate(ctx, opts, params.Cursor, c.distinctDIDsFetcher(params), emit)
+	})
+}
+
+// seq adapts a paginating function to a range-over-func iterator
+func seq[T any](run func(emit func(T) error) error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		err := run(func(item T) error {
+			if !yield(item, nil) {
+				return errStopPagination
+			}
+			return nil
+		})
+		if err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// TypedLinks returns an iterator like Client.Links that decodes each record's
+// value as T. Decoding failures are yielded as errors without stopping iteration.
+func TypedLinks[T any](ctx context.Context, c *Client, params LinksParams, opts PaginateOptions) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for record, err := range c.Links(ctx, params, opts) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if !yield(decodeTyped[T](record)) {
+				return
+			}
+		}
+	}
+}
+
+// DecodedLinks returns an iterator like Client.Links that pairs each record with
+// its value decoded by DecodeValue, into the type registered for its collection.
+// Decoding failures are yielded as errors without stopping iteration.
+func (c *Client) DecodedLinks(ctx context.Context, params LinksParams, opts PaginateOptions) iter.Seq2[DecodedRecord, error] {
+	return func(yield func(DecodedRecord, error) bool) {
+		for record, err := range c.Links(ctx, params, opts) {
+			if err != nil {
+				yield(DecodedRecord{}, err)
+				return
+			}
+			value, err := record.DecodeValue()
+			if !yield(DecodedRecord{Record: record, Value: value}, err) {
+				return
+			}
+		}
+	}
+}
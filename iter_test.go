@@ -0,0 +1,81 @@
+//go:build go1.23
+
+package constellation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestLinksIterator tests lazy iteration across pages and early break
+func TestLinksIterator(t *testing.T) {
+	server := newPagedServer(t, 25)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	count := 0
+	for _, err := range client.Links(context.Background(), params, constellation.PaginateOptions{}) {
+		if err != nil {
+			t.Fatalf("Iteration failed: %v", err)
+		}
+		count++
+	}
+	if count != 25 {
+		t.Errorf("Expected 25 records, got %d", count)
+	}
+
+	requestsBefore := client.Metrics().Requests
+	count = 0
+	for _, err := range client.Links(context.Background(), params, constellation.PaginateOptions{}) {
+		if err != nil {
+			t.Fatalf("Iteration failed: %v", err)
+		}
+		count++
+		if count == 5 {
+			break
+		}
+	}
+	if requests := client.Metrics().Requests - requestsBefore; requests != 1 {
+		t.Errorf("Expected breaking early to fetch 1 page, got %d", requests)
+	}
+}
+
+// TestLinksIteratorError tests that request failures are yielded as errors
+func TestLinksIteratorError(t *testing.T) {
+	client := constellation.NewClientWithConfig("http://127.0.0.1:0", time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example"}
+
+	for _, err := range client.LinkingDIDs(context.Background(), params, constellation.PaginateOptions{}) {
+		if err == nil {
+			t.Fatal("Expected an error from an unreachable server")
+		}
+	}
+}
+
+// TestLinksBreakWithSort tests breaking out of a sorted range loop, both while
+// the window is filling and once it's flushing
+func TestLinksBreakWithSort(t *testing.T) {
+	server := newPagedServer(t, 30)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	for _, window := range []int{5, 100} {
+		opts := constellation.PaginateOptions{Sort: constellation.SortRKeyDesc, SortWindow: window}
+		seen := 0
+		for _, err := range client.Links(context.Background(), params, opts) {
+			if err != nil {
+				t.Fatalf("Failed to iterate: %v", err)
+			}
+			seen++
+			if seen == 3 {
+				break
+			}
+		}
+		if seen != 3 {
+			t.Errorf("Expected to stop after 3 records with window %d, got %d", window, seen)
+		}
+	}
+}
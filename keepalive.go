@@ -0,0 +1,76 @@
+package constellation
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultKeepAliveInterval is a keep-alive interval comfortably below common idle
+// connection timeouts, including net/http's default of 90 seconds
+const DefaultKeepAliveInterval = 30 * time.Second
+
+// StartKeepAlive keeps a connection to the instance warm for daemons with long
+// idle gaps between polls, so small polls don't pay for a fresh TLS handshake.
+// Whenever no request has been made for interval, a lightweight HEAD request is
+// sent to the root endpoint. Keep-alive is off unless started; it runs until ctx
+// is done or the returned function is called.
+func (c *Client) StartKeepAlive(ctx context.Context, interval time.Duration) context.CancelFunc {
+	if interval <= 0 {
+		interval = DefaultKeepAliveInterval
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	c.markActive()
+
+	go func() {
+		ticker := time.NewTicker(interval / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if time.Since(c.lastActive()) >= interval {
+					c.sendKeepAlive(ctx)
+				}
+			}
+		}
+	}()
+
+	return cancel
+}
+
+// markActive records that a request was just made
+func (c *Client) markActive() {
+	c.lastRequest.Store(time.Now().UnixNano())
+}
+
+// lastActive returns when the last request was made
+func (c *Client) lastActive() time.Time {
+	return time.Unix(0, c.lastRequest.Load())
+}
+
+// sendKeepAlive sends a HEAD request to the root endpoint, ignoring the outcome.
+// It bypasses rate limiting, metrics, and the audit log.
+func (c *Client) sendKeepAlive(ctx context.Context) {
+	c.markActive()
+
+	req, err := http.NewRequestWithContext(ctx, "HEAD", c.BaseURL+"/", nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.debug("keep-alive request failed", "error", err)
+		}
+		return
+	}
+	// Drain the body so the connection returns to the idle pool
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
@@ -0,0 +1,42 @@
+package constellation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestStartKeepAlive tests that idle clients send HEAD requests until stopped
+func TestStartKeepAlive(t *testing.T) {
+	var heads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "HEAD" && r.URL.Path == "/" {
+			heads.Add(1)
+		}
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	stop := client.StartKeepAlive(context.Background(), 20*time.Millisecond)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for heads.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	stop()
+	if heads.Load() < 2 {
+		t.Fatalf("Expected keep-alive requests, got %d", heads.Load())
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	stopped := heads.Load()
+	time.Sleep(100 * time.Millisecond)
+	if heads.Load() != stopped {
+		t.Errorf("Expected no keep-alive requests after stopping")
+	}
+}
@@ -0,0 +1,256 @@
+package constellation
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by KVStore.Get when no value is stored under a key
+var ErrNotFound = errors.New("constellation: key not found")
+
+// KVStore is the persistence backend shared by the client's stateful features:
+// KVCursorStore keeps pagination checkpoints in it, KVCache keeps cached counts
+// and lookups, SaveSnapshot keeps DID snapshots, NewKVAuditLog keeps the audit
+// log, and queue.Queue.DeadLetters keeps failed tasks. MemoryKV, FileKV, SQLKV,
+// and RedisKV implement it; a custom backend can be checked with
+// constellationtest.TestKVStore. Implementations must be safe for concurrent use.
+type KVStore interface {
+	// Get returns the value stored under key, or an error wrapping ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put stores value under key, replacing any previous value
+	Put(ctx context.Context, key string, value []byte) error
+	// Delete removes the value stored under key; deleting a missing key is not
+	// an error
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryKV is a KVStore held in process memory, for tests and short-lived runs
+type MemoryKV struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+// NewMemoryKV creates an empty in-memory store
+func NewMemoryKV() *MemoryKV {
+	return &MemoryKV{values: make(map[string][]byte)}
+}
+
+// Get implements KVStore
+func (m *MemoryKV) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.values[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte{}, value...), nil
+}
+
+// Put implements KVStore
+func (m *MemoryKV) Put(_ context.Context, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = append([]byte{}, value...)
+	return nil
+}
+
+// Delete implements KVStore
+func (m *MemoryKV) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.values, key)
+	return nil
+}
+
+// FileKV stores each value in its own file within a directory, named by a hash
+// of its key. Writes are atomic, so a crash never leaves a partial value.
+type FileKV struct {
+	Dir string
+}
+
+// NewFileKV creates a file store in dir, creating the directory if needed
+func NewFileKV(dir string) (*FileKV, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	return &FileKV{Dir: dir}, nil
+}
+
+// path returns the file holding the value for key
+func (s *FileKV) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.Dir, hex.EncodeToString(sum[:])+".kv")
+}
+
+// Get implements KVStore
+func (s *FileKV) Get(_ context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// Put implements KVStore
+func (s *FileKV) Put(_ context.Context, key string, value []byte) error {
+	return writeFileAtomic(s.path(key), value)
+}
+
+// Delete implements KVStore
+func (s *FileKV) Delete(_ context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// SQLKV stores values in a SQLite database table. The caller opens the
+// database with the SQLite driver of their choice.
+type SQLKV struct {
+	DB *sql.DB
+}
+
+// NewSQLKV creates a SQL store and ensures its table exists
+func NewSQLKV(ctx context.Context, db *sql.DB) (*SQLKV, error) {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS constellation_kv (
+		key TEXT PRIMARY KEY,
+		value BLOB NOT NULL,
+		updated_at INTEGER NOT NULL
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store table: %w", err)
+	}
+	return &SQLKV{DB: db}, nil
+}
+
+// Get implements KVStore
+func (s *SQLKV) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := s.DB.QueryRowContext(ctx, `SELECT value FROM constellation_kv WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+// Put implements KVStore
+func (s *SQLKV) Put(ctx context.Context, key string, value []byte) error {
+	if value == nil {
+		value = []byte{}
+	}
+	_, err := s.DB.ExecContext(ctx, `INSERT INTO constellation_kv (key, value, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		key, value, time.Now().Unix())
+	return err
+}
+
+// Delete implements KVStore
+func (s *SQLKV) Delete(ctx context.Context, key string) error {
+	_, err := s.DB.ExecContext(ctx, `DELETE FROM constellation_kv WHERE key = ?`, key)
+	return err
+}
+
+// KVCursorStore is a CursorStore keeping cursors in a KVStore under "cursor/"
+// keys, so checkpoints can share a backend with the caches. NewFileCursorStore
+// and NewSQLCursorStore create one over a FileKV or SQLKV.
+type KVCursorStore struct {
+	Store KVStore
+}
+
+// LoadCursor implements CursorStore
+func (s *KVCursorStore) LoadCursor(ctx context.Context, key string) (string, error) {
+	data, err := s.Store.Get(ctx, "cursor/"+key)
+	if errors.Is(err, ErrNotFound) {
+		return "", nil
+	}
+	return string(data), err
+}
+
+// SaveCursor implements CursorStore
+func (s *KVCursorStore) SaveCursor(ctx context.Context, key, cursor string) error {
+	return s.Store.Put(ctx, "cursor/"+key, []byte(cursor))
+}
+
+// DeleteCursor implements CursorStore
+func (s *KVCursorStore) DeleteCursor(ctx context.Context, key string) error {
+	return s.Store.Delete(ctx, "cursor/"+key)
+}
+
+// KVCache is a read-through cache with a fixed TTL kept in a KVStore, so cached
+// counts, memberships, and liveness checks can survive restarts or be shared
+// between processes. Values are stored under "cache/" keys, prefixed with their
+// expiry time.
+type KVCache struct {
+	Store  KVStore
+	TTL    time.Duration
+	Source Getter // Loads values on a miss
+}
+
+// NewKVCache creates a read-through cache in store in front of source
+func NewKVCache(store KVStore, ttl time.Duration, source Getter) *KVCache {
+	return &KVCache{Store: store, TTL: ttl, Source: source}
+}
+
+// Get returns the cached value for key, loading it from Source on a miss. A
+// failure to read or write the store is treated as a miss, so a store outage
+// only costs extra requests.
+func (k *KVCache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := k.Store.Get(ctx, "cache/"+key)
+	if err == nil && len(data) >= 8 {
+		expires := time.Unix(0, int64(binary.BigEndian.Uint64(data)))
+		if time.Now().Before(expires) {
+			return data[8:], nil
+		}
+	}
+
+	value, err := k.Source.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := make([]byte, 8, 8+len(value))
+	binary.BigEndian.PutUint64(entry, uint64(time.Now().Add(k.TTL).UnixNano()))
+	_ = k.Store.Put(ctx, "cache/"+key, append(entry, value...))
+	return value, nil
+}
+
+// SaveSnapshot stores dids in store under name, replacing any previous snapshot,
+// so a later run can diff against it with LoadSnapshot
+func SaveSnapshot(ctx context.Context, store KVStore, name string, dids DIDSet) error {
+	data, err := json.Marshal(dids.Sorted())
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := store.Put(ctx, "snapshot/"+name, data); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot returns the snapshot saved under name, or nil if there is none
+func LoadSnapshot(ctx context.Context, store KVStore, name string) (DIDSet, error) {
+	data, err := store.Get(ctx, "snapshot/"+name)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot: %w", err)
+	}
+
+	var dids []string
+	if err := json.Unmarshal(data, &dids); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return NewDIDSet(dids...), nil
+}
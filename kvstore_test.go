@@ -0,0 +1,92 @@
+package constellation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+	"github.com/tanner-caffrey/constellation-go/constellationtest"
+)
+
+// TestMemoryKV runs the store conformance checks against MemoryKV
+func TestMemoryKV(t *testing.T) {
+	constellationtest.TestKVStore(t, constellation.NewMemoryKV())
+}
+
+// TestFileKV runs the store conformance checks against FileKV
+func TestFileKV(t *testing.T) {
+	store, err := constellation.NewFileKV(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	constellationtest.TestKVStore(t, store)
+}
+
+// TestCursorStoreConformance runs the cursor store conformance checks against
+// the built-in cursor stores
+func TestCursorStoreConformance(t *testing.T) {
+	fileStore, err := constellation.NewFileCursorStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	constellationtest.TestCursorStore(t, fileStore)
+	constellationtest.TestCursorStore(t, &constellation.KVCursorStore{Store: constellation.NewMemoryKV()})
+}
+
+// TestKVCache tests reading through a cache kept in a store, and expiry
+func TestKVCache(t *testing.T) {
+	ctx := context.Background()
+	loads := 0
+	source := constellation.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		loads++
+		return []byte("42"), nil
+	})
+	store := constellation.NewMemoryKV()
+
+	cache := constellation.NewKVCache(store, time.Hour, source)
+	for i := 0; i < 2; i++ {
+		if data, err := cache.Get(ctx, "links?target=x"); err != nil || string(data) != "42" {
+			t.Fatalf("Expected 42, got %q, %v", data, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("Expected one load, got %d", loads)
+	}
+
+	// A second cache on the same store shares its entries
+	shared := constellation.NewKVCache(store, time.Hour, source)
+	shared.Get(ctx, "links?target=x")
+	if loads != 1 {
+		t.Errorf("Expected the shared store to serve the cached value, got %d loads", loads)
+	}
+
+	expired := constellation.NewKVCache(constellation.NewMemoryKV(), -time.Second, source)
+	expired.Get(ctx, "links?target=x")
+	expired.Get(ctx, "links?target=x")
+	if loads != 3 {
+		t.Errorf("Expected expired entries to be reloaded, got %d loads", loads)
+	}
+}
+
+// TestSnapshot tests saving and loading DID snapshots
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	store := constellation.NewMemoryKV()
+
+	if dids, err := constellation.LoadSnapshot(ctx, store, "followers"); err != nil || dids != nil {
+		t.Fatalf("Expected no snapshot, got %v, %v", dids, err)
+	}
+
+	saved := constellation.NewDIDSet("did:plc:b", "did:plc:a")
+	if err := constellation.SaveSnapshot(ctx, store, "followers", saved); err != nil {
+		t.Fatalf("Failed to save snapshot: %v", err)
+	}
+	dids, err := constellation.LoadSnapshot(ctx, store, "followers")
+	if err != nil {
+		t.Fatalf("Failed to load snapshot: %v", err)
+	}
+	if dids.Len() != 2 || !dids.Contains("did:plc:a") || !dids.Contains("did:plc:b") {
+		t.Errorf("Expected the saved DIDs, got %v", dids.Sorted())
+	}
+}
@@ -0,0 +1,153 @@
+package constellation
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Metric counts one kind of engagement for a target
+type Metric func(ctx context.Context, c *Client, target string) (int, error)
+
+// Metrics for common Bluesky leaderboards
+var (
+	MetricLikes Metric = func(ctx context.Context, c *Client, target string) (int, error) {
+		return metricCount(ctx, c, likesParams(target))
+	}
+	MetricReposts Metric = func(ctx context.Context, c *Client, target string) (int, error) {
+		return metricCount(ctx, c, repostsParams(target))
+	}
+	MetricQuotes Metric = func(ctx context.Context, c *Client, target string) (int, error) {
+		return c.QuoteCount(ctx, target)
+	}
+	MetricReplies Metric = func(ctx context.Context, c *Client, target string) (int, error) {
+		return c.ReplyCount(ctx, target)
+	}
+	MetricFollowers Metric = func(ctx context.Context, c *Client, target string) (int, error) {
+		return c.FollowerCount(ctx, target)
+	}
+)
+
+// LinksMetric returns a Metric counting links from collection at path
+func LinksMetric(collection, path string) Metric {
+	return func(ctx context.Context, c *Client, target string) (int, error) {
+		return metricCount(ctx, c, LinksParams{Target: target, Collection: collection, Path: path})
+	}
+}
+
+// metricCount returns the links count for params
+func metricCount(ctx context.Context, c *Client, params LinksParams) (int, error) {
+	count, err := c.GetLinksCountContext(ctx, params)
+	if err != nil {
+		return -1, err
+	}
+	return count.Total, nil
+}
+
+// LeaderboardEntry is a target and its count
+type LeaderboardEntry struct {
+	Target string `json:"target"`
+	Count  int    `json:"count"`
+}
+
+// Leaderboard ranks targets by a Metric. It keeps every target's last count so
+// Refresh only needs to re-fetch the targets that changed.
+type Leaderboard struct {
+	client *Client
+	metric Metric
+	n      int
+
+	mu     sync.Mutex
+	counts map[string]int
+	errors map[string]error
+}
+
+// Leaderboard fetches metric for each target concurrently and ranks the top n.
+// Targets that fail to load are left out and reported by Errors.
+func (c *Client) Leaderboard(ctx context.Context, targets []string, metric Metric, n int) (*Leaderboard, error) {
+	board := &Leaderboard{
+		client: c,
+		metric: metric,
+		n:      n,
+		counts: make(map[string]int),
+		errors: make(map[string]error),
+	}
+	if err := board.Refresh(ctx, targets...); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// Refresh re-fetches the counts of targets, adding any not yet on the board.
+// Other targets keep their last count. A target that fails keeps its previous
+// count, if any, and is reported by Errors. The context error is returned if ctx
+// is cancelled.
+func (b *Leaderboard) Refresh(ctx context.Context, targets ...string) error {
+	counts := make([]int, len(targets))
+	errs := make([]error, len(targets))
+
+	parallelEach(len(targets), DefaultParallelism, func(i int) {
+		counts[i], errs[i] = b.metric(ctx, b.client, targets[i])
+	})
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, target := range targets {
+		if errs[i] != nil {
+			b.errors[target] = errs[i]
+			continue
+		}
+		delete(b.errors, target)
+		b.counts[target] = counts[i]
+	}
+	return nil
+}
+
+// Remove drops targets from the board, e.g. posts that aged out of the window
+func (b *Leaderboard) Remove(targets ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, target := range targets {
+		delete(b.counts, target)
+		delete(b.errors, target)
+	}
+}
+
+// Top returns the top n targets by count, highest first, with ties ordered by
+// target. All targets are returned when n is zero or negative.
+func (b *Leaderboard) Top() []LeaderboardEntry {
+	b.mu.Lock()
+	entries := make([]LeaderboardEntry, 0, len(b.counts))
+	for target, count := range b.counts {
+		entries = append(entries, LeaderboardEntry{Target: target, Count: count})
+	}
+	b.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Target < entries[j].Target
+	})
+	if b.n > 0 && len(entries) > b.n {
+		entries = entries[:b.n]
+	}
+	return entries
+}
+
+// Errors returns the targets whose last fetch failed, with their errors
+func (b *Leaderboard) Errors() map[string]error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	errs := make(map[string]error, len(b.errors))
+	for target, err := range b.errors {
+		errs[target] = err
+	}
+	return errs
+}
@@ -0,0 +1,87 @@
+package constellation_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestLeaderboard tests ranking, incremental refresh, and removal
+func TestLeaderboard(t *testing.T) {
+	var mu sync.Mutex
+	likes := map[string]int{"at://a": 5, "at://b": 9, "at://c": 5, "at://d": 1}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		target := r.URL.Query().Get("target")
+		if target == "at://broken" {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"total": %d}`, likes[target])
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	ctx := context.Background()
+
+	board, err := client.Leaderboard(ctx, []string{"at://a", "at://b", "at://c", "at://d", "at://broken"}, constellation.MetricLikes, 3)
+	if err != nil {
+		t.Fatalf("Failed to build leaderboard: %v", err)
+	}
+	want := []constellation.LeaderboardEntry{{"at://b", 9}, {"at://a", 5}, {"at://c", 5}}
+	if got := board.Top(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if errs := board.Errors(); len(errs) != 1 || errs["at://broken"] == nil {
+		t.Errorf("Expected at://broken to fail, got %v", errs)
+	}
+
+	mu.Lock()
+	likes["at://d"] = 20
+	requests = 0
+	mu.Unlock()
+	if err := board.Refresh(ctx, "at://d"); err != nil {
+		t.Fatalf("Failed to refresh: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected refresh to fetch one target, made %d requests", requests)
+	}
+	want = []constellation.LeaderboardEntry{{"at://d", 20}, {"at://b", 9}, {"at://a", 5}}
+	if got := board.Top(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v after refresh, got %v", want, got)
+	}
+
+	board.Remove("at://d", "at://broken")
+	want = []constellation.LeaderboardEntry{{"at://b", 9}, {"at://a", 5}, {"at://c", 5}}
+	if got := board.Top(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v after removal, got %v", want, got)
+	}
+	if errs := board.Errors(); len(errs) != 0 {
+		t.Errorf("Expected no errors after removal, got %v", errs)
+	}
+}
+
+// TestLinksMetric tests a custom collection and path metric
+func TestLinksMetric(t *testing.T) {
+	server := newShortcutServer(t, "com.example.vote", ".subject")
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	board, err := client.Leaderboard(context.Background(), []string{"at://a"}, constellation.LinksMetric("com.example.vote", ".subject"), 0)
+	if err != nil {
+		t.Fatalf("Failed to build leaderboard: %v", err)
+	}
+	if got := board.Top(); len(got) != 1 || got[0].Count != 1 {
+		t.Errorf("Expected one entry with count 1, got %v", got)
+	}
+}
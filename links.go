@@ -1,19 +1,78 @@
 package constellation
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 // LinksParams represents parameters for links-related API calls
 type LinksParams struct {
-	Target     string // Required: The target URI to find links for
+	// Target is required: the target to find links for. The server matches it
+	// exactly as records link it, so the client only trims whitespace and
+	// lowercases the AT URI scheme, DID method, and did:plc identifier, which
+	// records always store in lowercase. See NormalizeTarget for full rewriting.
+	Target     string
 	Collection string // Optional: Filter by collection type
-	Path       string // Optional: JSONPath to the target within records
+	Path       string // Optional: JSONPath to the target within records, inferred for well-known collections
 	Limit      int    // Optional: Maximum number of results to return
 	Cursor     string // Optional: Cursor for pagination
+	Offset     int    // Optional: Offset for pagination, on instances that support it
+
+	// Server-side filters, sent to instances that advertise support for them and
+	// applied client-side otherwise
+	FromDID string    // Optional: Only links from records authored by this DID, prepared like Target
+	Since   time.Time // Optional: Only links from records created at or after this time, by their TID rkey or createdAt
+
+	// Extra holds arbitrary query parameters appended to the request, for server
+	// features this library doesn't model yet. A key already sent by one of the
+	// fields above, such as "target" or "limit", is ignored: the field wins.
+	Extra url.Values
+}
+
+// queryValues builds the URL query parameters for a links-related API call.
+// Limit and Cursor are only included when paginated is true. The target is
+// prepared by queryTarget, and an empty Path is inferred for well-known collections.
+func (p LinksParams) queryValues(paginated bool) url.Values {
+	p = p.withInferredPath()
+	urlParams := url.Values{}
+	urlParams.Add("target", queryTarget(p.Target))
+
+	if p.Collection != "" {
+		urlParams.Add("collection", p.Collection)
+	}
+	if p.Path != "" {
+		urlParams.Add("path", p.Path)
+	}
+	if paginated {
+		if p.Limit > 0 {
+			urlParams.Add("limit", strconv.Itoa(p.Limit))
+		}
+		if p.Cursor != "" {
+			urlParams.Add("cursor", p.Cursor)
+		}
+		if p.Offset > 0 {
+			urlParams.Add("offset", strconv.Itoa(p.Offset))
+		}
+	}
+	if p.FromDID != "" {
+		urlParams.Add("did", queryTarget(p.FromDID))
+	}
+	if !p.Since.IsZero() {
+		urlParams.Add("since", p.Since.UTC().Format(time.RFC3339))
+	}
+	for key, values := range p.Extra {
+		if urlParams.Has(key) {
+			continue
+		}
+		for _, value := range values {
+			urlParams.Add(key, value)
+		}
+	}
+
+	return urlParams
 }
 
 // LinksResponse represents the response from links endpoints
@@ -26,6 +85,11 @@ type LinksResponse struct {
 // CountResponse represents the response from count endpoints
 type CountResponse struct {
 	Total int `json:"total"`
+
+	// LowerBound is set when Total was derived by paging and may undercount:
+	// always when the instance lacks a count endpoint for the query, and when a
+	// client-side filtered count stopped at Client.CountScanLimit
+	LowerBound bool `json:"-"`
 }
 
 // DistinctDIDsResponse represents the response from distinct DIDs endpoints
@@ -38,36 +102,36 @@ type DistinctDIDsResponse struct {
 // GetLinks retrieves a list of records linking to a target
 // Endpoint: GET /links
 func (c *Client) GetLinks(params LinksParams) (*LinksResponse, error) {
+	return c.GetLinksContext(context.Background(), params)
+}
+
+// GetLinksContext is like GetLinks but bound to ctx
+func (c *Client) GetLinksContext(ctx context.Context, params LinksParams) (*LinksResponse, error) {
 	if params.Target == "" {
 		return nil, fmt.Errorf("target parameter is required")
 	}
 
-	urlParams := url.Values{}
-	urlParams.Add("target", params.Target)
-
-	if params.Collection != "" {
-		urlParams.Add("collection", params.Collection)
-	}
-	if params.Path != "" {
-		urlParams.Add("path", params.Path)
-	}
-	if params.Limit > 0 {
-		urlParams.Add("limit", strconv.Itoa(params.Limit))
-	}
-	if params.Cursor != "" {
-		urlParams.Add("cursor", params.Cursor)
-	}
+	params = c.applyCapabilities(params)
+	params, filter := c.splitFilters(params)
+	urlParams := params.queryValues(true)
 
-	resp, err := c.makeRequest("/links", urlParams)
+	resp, err := c.makeRequestContext(ctx, "/links", urlParams)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var linksResp LinksResponse
-	if err := json.NewDecoder(resp.Body).Decode(&linksResp); err != nil {
+	if err := c.decodeResponse(resp, &linksResp); err != nil {
 		return nil, fmt.Errorf("failed to decode links response: %w", err)
 	}
+	if filter != nil && filter.exhausted(linksResp.LinkingRecords) {
+		linksResp.Cursor = ""
+	}
+	linksResp.LinkingRecords = c.filterRecords(linksResp.LinkingRecords)
+	if filter != nil {
+		linksResp.LinkingRecords = keepRecords(linksResp.LinkingRecords, filter.keep)
+	}
 
 	return &linksResp, nil
 }
@@ -75,28 +139,60 @@ func (c *Client) GetLinks(params LinksParams) (*LinksResponse, error) {
 // GetLinksCount retrieves the total number of links pointing at a given target
 // Endpoint: GET /links/count
 func (c *Client) GetLinksCount(params LinksParams) (*CountResponse, error) {
+	return c.GetLinksCountContext(context.Background(), params)
+}
+
+// GetLinksCountContext is like GetLinksCount but bound to ctx
+func (c *Client) GetLinksCountContext(ctx context.Context, params LinksParams) (*CountResponse, error) {
 	if params.Target == "" {
 		return nil, fmt.Errorf("target parameter is required")
 	}
 
-	urlParams := url.Values{}
-	urlParams.Add("target", params.Target)
+	if c.needsClientFilter(params) {
+		total, capped, err := c.countByPaginating(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return &CountResponse{Total: total, LowerBound: capped}, nil
+	}
 
-	if params.Collection != "" {
-		urlParams.Add("collection", params.Collection)
+	var countResp *CountResponse
+	var err error
+	if cache := c.getCountCache(); cache != nil {
+		var total int
+		total, err = cachedCount(ctx, cache, CountKey(params, false))
+		countResp = &CountResponse{Total: total}
+	} else {
+		countResp, err = c.fetchLinksCount(ctx, params)
+	}
+	if countUnsupported(err) {
+		c.warn("links count endpoint unavailable; counting by paging, result is a lower bound",
+			"target", params.Target)
+		total, _, err := c.countByPaginating(ctx, c.fallbackParams(params))
+		if err != nil {
+			return nil, err
+		}
+		return &CountResponse{Total: total, LowerBound: true}, nil
 	}
-	if params.Path != "" {
-		urlParams.Add("path", params.Path)
+	if err != nil {
+		return nil, err
 	}
+	return countResp, nil
+}
 
-	resp, err := c.makeRequest("/links/count", urlParams)
+// fetchLinksCount requests the links count from the API, bypassing any count cache
+func (c *Client) fetchLinksCount(ctx context.Context, params LinksParams) (*CountResponse, error) {
+	params = c.applyCapabilities(params)
+	urlParams := params.queryValues(false)
+
+	resp, err := c.makeRequestContext(ctx, "/links/count", urlParams)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var countResp CountResponse
-	if err := json.NewDecoder(resp.Body).Decode(&countResp); err != nil {
+	if err := c.decodeResponse(resp, &countResp); err != nil {
 		return nil, fmt.Errorf("failed to decode count response: %w", err)
 	}
 
@@ -106,71 +202,108 @@ func (c *Client) GetLinksCount(params LinksParams) (*CountResponse, error) {
 // GetDistinctDIDs retrieves a list of distinct DIDs linking to a target
 // Endpoint: GET /links/distinct-dids
 func (c *Client) GetDistinctDIDs(params LinksParams) (*DistinctDIDsResponse, error) {
+	return c.GetDistinctDIDsContext(context.Background(), params)
+}
+
+// GetDistinctDIDsContext is like GetDistinctDIDs but bound to ctx
+func (c *Client) GetDistinctDIDsContext(ctx context.Context, params LinksParams) (*DistinctDIDsResponse, error) {
 	if params.Target == "" {
 		return nil, fmt.Errorf("target parameter is required")
 	}
 
-	urlParams := url.Values{}
-	urlParams.Add("target", params.Target)
-
-	if params.Collection != "" {
-		urlParams.Add("collection", params.Collection)
-	}
-	if params.Path != "" {
-		urlParams.Add("path", params.Path)
-	}
-	if params.Limit > 0 {
-		urlParams.Add("limit", strconv.Itoa(params.Limit))
-	}
-	if params.Cursor != "" {
-		urlParams.Add("cursor", params.Cursor)
+	params = c.applyCapabilities(params)
+	params, keepDID, err := c.splitDIDFilters(params)
+	if err != nil {
+		return nil, err
 	}
+	urlParams := params.queryValues(true)
 
-	resp, err := c.makeRequest("/links/distinct-dids", urlParams)
+	resp, err := c.makeRequestContext(ctx, "/links/distinct-dids", urlParams)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var didsResp DistinctDIDsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&didsResp); err != nil {
+	if err := c.decodeResponse(resp, &didsResp); err != nil {
 		return nil, fmt.Errorf("failed to decode distinct DIDs response: %w", err)
 	}
+	didsResp.DIDs = c.filterDIDs(didsResp.DIDs)
+	if keepDID != "" {
+		didsResp.DIDs = keepDIDs(didsResp.DIDs, keepDID)
+	}
 
 	return &didsResp, nil
 }
 
-// GetDistinctDIDs retrieves a list of distinct DIDs linking to a target
-// Endpoint: GET /links/distinct-dids
+// GetDistinctDIDsCount retrieves the total number of distinct DIDs linking to a target
+// Endpoint: GET /links/count/distinct-dids
 func (c *Client) GetDistinctDIDsCount(params LinksParams) (int, error) {
-	if params.Target == "" {
-		return -1, fmt.Errorf("target parameter is required")
+	return c.GetDistinctDIDsCountContext(context.Background(), params)
+}
+
+// GetDistinctDIDsCountContext is like GetDistinctDIDsCount but bound to ctx. Use
+// GetDistinctDIDsCountResponse to learn whether the count is only a lower bound.
+func (c *Client) GetDistinctDIDsCountContext(ctx context.Context, params LinksParams) (int, error) {
+	count, err := c.GetDistinctDIDsCountResponse(ctx, params)
+	if err != nil {
+		return -1, err
 	}
+	return count.Total, nil
+}
 
-	urlParams := url.Values{}
-	urlParams.Add("target", params.Target)
+// GetDistinctDIDsCountResponse is like GetDistinctDIDsCountContext but returns a
+// CountResponse, with LowerBound set when the instance lacks the count endpoint
+// and the count was derived by paging
+func (c *Client) GetDistinctDIDsCountResponse(ctx context.Context, params LinksParams) (*CountResponse, error) {
+	if params.Target == "" {
+		return nil, fmt.Errorf("target parameter is required")
+	}
 
-	if params.Collection != "" {
-		urlParams.Add("collection", params.Collection)
+	if c.needsClientFilter(params) {
+		total, capped, err := c.countDistinctByPaginating(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return &CountResponse{Total: total, LowerBound: capped}, nil
 	}
-	if params.Path != "" {
-		urlParams.Add("path", params.Path)
+
+	var total int
+	var err error
+	if cache := c.getCountCache(); cache != nil {
+		total, err = cachedCount(ctx, cache, CountKey(params, true))
+	} else {
+		total, err = c.fetchDistinctDIDsCount(ctx, params)
 	}
-	if params.Limit > 0 {
-		urlParams.Add("limit", strconv.Itoa(params.Limit))
+	if countUnsupported(err) {
+		c.warn("distinct DIDs count endpoint unavailable; counting by paging, result is a lower bound",
+			"target", params.Target)
+		total, _, err := c.countDistinctByPaginating(ctx, c.fallbackParams(params))
+		if err != nil {
+			return nil, err
+		}
+		return &CountResponse{Total: total, LowerBound: true}, nil
 	}
-	if params.Cursor != "" {
-		urlParams.Add("cursor", params.Cursor)
+	if err != nil {
+		return nil, err
 	}
+	return &CountResponse{Total: total}, nil
+}
+
+// fetchDistinctDIDsCount requests the distinct DIDs count from the API, bypassing
+// any count cache
+func (c *Client) fetchDistinctDIDsCount(ctx context.Context, params LinksParams) (int, error) {
+	params = c.applyCapabilities(params)
+	urlParams := params.queryValues(true)
 
-	resp, err := c.makeRequest("/links/count/distinct-dids", urlParams)
+	resp, err := c.makeRequestContext(ctx, "/links/count/distinct-dids", urlParams)
 	if err != nil {
 		return -1, err
 	}
 	defer resp.Body.Close()
 
 	var didsResp DistinctDIDsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&didsResp); err != nil {
+	if err := c.decodeResponse(resp, &didsResp); err != nil {
 		return -1, fmt.Errorf("failed to decode distinct DIDs response: %w", err)
 	}
 
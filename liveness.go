@@ -0,0 +1,106 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RecordExists reports whether the record at uri still exists on its author's
+// PDS, for catching deleted likes and follows the index hasn't pruned yet.
+// Records in repos that no longer exist count as deleted. Results are cached
+// when a liveness cache is set.
+func (c *Client) RecordExists(ctx context.Context, uri string) (bool, error) {
+	c.mu.Lock()
+	cache := c.livenessCache
+	c.mu.Unlock()
+	if cache == nil {
+		return c.recordExists(ctx, uri)
+	}
+
+	data, err := cache.Get(ctx, LivenessKey(uri))
+	if err != nil {
+		return false, err
+	}
+	return string(data) == "1", nil
+}
+
+// FilterLive returns the records that still exist on their authors' PDSes,
+// checking up to parallelism records at once, or DefaultParallelism if
+// parallelism isn't positive. It fails if any check fails.
+func (c *Client) FilterLive(ctx context.Context, records []LinkRecord, parallelism int) ([]LinkRecord, error) {
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+
+	live := make([]bool, len(records))
+	errs := make([]error, len(records))
+	parallelEach(len(records), parallelism, func(i int) {
+		live[i], errs[i] = c.RecordExists(ctx, records[i].RecordURI())
+	})
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	var kept []LinkRecord
+	for i, record := range records {
+		if live[i] {
+			kept = append(kept, record)
+		}
+	}
+	return kept, nil
+}
+
+// UseLivenessCache routes RecordExists and FilterLive through a read-through
+// cache. On a miss the cache should load values with the getter from
+// LivenessGetter.
+func (c *Client) UseLivenessCache(cache Getter) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.livenessCache = cache
+	return c
+}
+
+// LivenessGetter returns a Getter that checks records for keys built by
+// LivenessKey on their authors' PDSes, bypassing the liveness cache. Values are
+// "1" for records that exist and "0" for deleted ones.
+func (c *Client) LivenessGetter() Getter {
+	return GetterFunc(c.loadLiveness)
+}
+
+// LivenessKey builds the cache key for checking whether the record at uri exists
+func LivenessKey(uri string) string {
+	return "live?" + uri
+}
+
+// loadLiveness answers the liveness check described by key from the record's PDS
+func (c *Client) loadLiveness(ctx context.Context, key string) ([]byte, error) {
+	uri, ok := strings.CutPrefix(key, "live?")
+	if !ok {
+		return nil, fmt.Errorf("invalid liveness key: %s", key)
+	}
+
+	exists, err := c.recordExists(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return []byte("1"), nil
+	}
+	return []byte("0"), nil
+}
+
+// recordExists fetches the record at uri, treating ErrRecordNotFound and missing
+// repos or DIDs (404 or 410) as deleted. Other errors, such as a 400 for a
+// malformed request, are returned.
+func (c *Client) recordExists(ctx context.Context, uri string) (bool, error) {
+	_, err := c.GetRecord(ctx, uri)
+	if notFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
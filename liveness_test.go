@@ -0,0 +1,77 @@
+package constellation_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestFilterLive tests dropping records deleted from their PDS, with cached checks
+func TestFilterLive(t *testing.T) {
+	var mu sync.Mutex
+	fetches := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/did:plc:"):
+			fmt.Fprintf(w, `{"id": %q, "service": [{"id": "#atproto_pds", "serviceEndpoint": %q}]}`, r.URL.Path[1:], server.URL)
+		case r.URL.Path == "/xrpc/com.atproto.repo.getRecord":
+			mu.Lock()
+			fetches++
+			mu.Unlock()
+			switch r.URL.Query().Get("rkey") {
+			case "deleted":
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": "RecordNotFound"}`))
+				return
+			case "invalid":
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": "InvalidRequest"}`))
+				return
+			}
+			w.Write([]byte(`{"uri": "at://x", "value": {}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.PLCDirectory = server.URL
+	client.UseLivenessCache(constellation.NewMemoryCache(time.Minute, client.LivenessGetter()))
+
+	records := []constellation.LinkRecord{
+		{DID: "did:plc:alice", Collection: constellation.CollectionLike, RKey: "kept"},
+		{DID: "did:plc:bob", Collection: constellation.CollectionLike, RKey: "deleted"},
+		{DID: "did:plc:carol", Collection: constellation.CollectionLike, RKey: "also-kept"},
+	}
+	ctx := context.Background()
+
+	live, err := client.FilterLive(ctx, records, 2)
+	if err != nil {
+		t.Fatalf("FilterLive failed: %v", err)
+	}
+	if len(live) != 2 || live[0].RKey != "kept" || live[1].RKey != "also-kept" {
+		t.Errorf("Expected the deleted record to be dropped in order, got %+v", live)
+	}
+
+	if exists, err := client.RecordExists(ctx, records[1].RecordURI()); err != nil || exists {
+		t.Errorf("Expected the deleted record to be reported missing, got %v, %v", exists, err)
+	}
+	if fetches != 3 {
+		t.Errorf("Expected cached checks not to refetch, got %d fetches", fetches)
+	}
+
+	// Other client errors aren't mistaken for deletions
+	invalid := constellation.LinkRecord{DID: "did:plc:dave", Collection: constellation.CollectionLike, RKey: "invalid"}
+	if exists, err := client.RecordExists(ctx, invalid.RecordURI()); err == nil || exists {
+		t.Errorf("Expected an error for a 400 other than RecordNotFound, got %v, %v", exists, err)
+	}
+}
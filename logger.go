@@ -0,0 +1,34 @@
+//go:build go1.21
+
+package constellation
+
+import "log/slog"
+
+// clientLogger adds Client.Logger, which needs log/slog and so only exists on
+// Go 1.21 and later
+type clientLogger struct {
+	// Logger receives warnings and debug messages from the client; logging is
+	// disabled when nil
+	Logger *slog.Logger
+}
+
+// warn logs a warning with key-value attributes, if logging is enabled
+func (c *Client) warn(msg string, args ...any) {
+	if c.Logger == nil && c.OnWarning == nil {
+		return
+	}
+	args = c.redactArgs(args)
+	if c.Logger != nil {
+		c.Logger.Warn(msg, args...)
+	}
+	if c.OnWarning != nil {
+		c.OnWarning(msg, args...)
+	}
+}
+
+// debug logs a debug message with key-value attributes, if logging is enabled
+func (c *Client) debug(msg string, args ...any) {
+	if c.Logger != nil {
+		c.Logger.Debug(msg, c.redactArgs(args)...)
+	}
+}
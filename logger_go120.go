@@ -0,0 +1,17 @@
+//go:build !go1.21
+
+package constellation
+
+// clientLogger is empty before Go 1.21, which has no log/slog for Client.Logger;
+// use Client.OnWarning instead
+type clientLogger struct{}
+
+// warn reports a warning with key-value attributes to OnWarning, if set
+func (c *Client) warn(msg string, args ...any) {
+	if c.OnWarning != nil {
+		c.OnWarning(msg, c.redactArgs(args)...)
+	}
+}
+
+// debug is a no-op, since only Logger receives debug messages
+func (c *Client) debug(msg string, args ...any) {}
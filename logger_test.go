@@ -0,0 +1,62 @@
+//go:build go1.21
+
+package constellation_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestQueryWarningsOnce tests that query warnings are logged once per query, not per page
+func TestQueryWarningsOnce(t *testing.T) {
+	server := newPagedServer(t, 25)
+	var buf bytes.Buffer
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.Logger = slog.New(slog.NewTextHandler(&buf, nil))
+	client.UseCapabilities(&constellation.Capabilities{MaxLimit: 100, DaysIndexed: 1})
+
+	oldTarget := "at://did:plc:vc7f4oafdgxsihk4cry2xpze/app.bsky.feed.post/3lgwdn7vd722r" // January 2025
+	params := constellation.LinksParams{Target: oldTarget, Limit: 10}
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{}); err != nil {
+			t.Fatalf("GetAllLinks failed: %v", err)
+		}
+	}
+	if n := strings.Count(buf.String(), "predates indexed history"); n != 1 {
+		t.Errorf("Expected one warning across pages and calls, got %d:\n%s", n, buf.String())
+	}
+
+	params.Collection = constellation.CollectionLike
+	client.GetLinks(params)
+	if n := strings.Count(buf.String(), "predates indexed history"); n != 2 {
+		t.Errorf("Expected a new warning for a different query, got %d", n)
+	}
+}
+
+// TestRedactedWarnings tests that each client redacts its own log lines
+func TestRedactedWarnings(t *testing.T) {
+	server := newPagedServer(t, 5)
+	oldTarget := "at://did:plc:vc7f4oafdgxsihk4cry2xpze/app.bsky.feed.post/3lgwdn7vd722r"
+	logs := func(client *constellation.Client) string {
+		var buf bytes.Buffer
+		client.Logger = slog.New(slog.NewTextHandler(&buf, nil))
+		client.UseCapabilities(&constellation.Capabilities{MaxLimit: 100, DaysIndexed: 1})
+		client.GetLinks(constellation.LinksParams{Target: oldTarget})
+		return buf.String()
+	}
+
+	private := constellation.NewClientWithConfig(server.URL, 5*time.Second).UseRedactor(constellation.HashRedactor("salt", 12))
+	if got := logs(private); !strings.Contains(got, "target=sha256:") || strings.Contains(got, "vc7f4oaf") {
+		t.Errorf("Expected the target to be redacted, got %s", got)
+	}
+	public := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	if got := logs(public); !strings.Contains(got, oldTarget) {
+		t.Errorf("Expected another client's logs to keep the target, got %s", got)
+	}
+}
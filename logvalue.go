@@ -0,0 +1,90 @@
+//go:build go1.21
+
+package constellation
+
+import (
+	"log/slog"
+	"time"
+)
+
+// LogValue implements slog.LogValuer with the fields of String
+func (p LinksParams) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("target", p.Target)}
+	if p.Collection != "" {
+		attrs = append(attrs, slog.String("collection", p.Collection))
+	}
+	if p.Path != "" {
+		attrs = append(attrs, slog.String("path", p.Path))
+	}
+	if p.Limit > 0 {
+		attrs = append(attrs, slog.Int("limit", p.Limit))
+	}
+	if p.Cursor != "" {
+		attrs = append(attrs, slog.String("cursor", shortCursor(p.Cursor)))
+	}
+	if p.Offset > 0 {
+		attrs = append(attrs, slog.Int("offset", p.Offset))
+	}
+	if p.FromDID != "" {
+		attrs = append(attrs, slog.String("did", p.FromDID))
+	}
+	if !p.Since.IsZero() {
+		attrs = append(attrs, slog.String("since", p.Since.UTC().Format(time.RFC3339)))
+	}
+	if len(p.Extra) > 0 {
+		attrs = append(attrs, slog.Int("extra", len(p.Extra)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer, omitting the record's value
+func (r LinkRecord) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("uri", r.RecordURI()),
+		slog.String("indexed_at", r.IndexedAt),
+	)
+}
+
+// LogValue implements slog.LogValuer, omitting the records
+func (r *LinksResponse) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("records", len(r.LinkingRecords)),
+		slog.Int("total", r.Total),
+		slog.Bool("more", r.Cursor != ""),
+	)
+}
+
+// LogValue implements slog.LogValuer, omitting the DIDs
+func (r *DistinctDIDsResponse) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("dids", len(r.DIDs)),
+		slog.Int("total", r.Total),
+		slog.Bool("more", r.Cursor != ""),
+	)
+}
+
+// LogValue implements slog.LogValuer
+func (r *CountResponse) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("total", r.Total),
+		slog.Bool("lower_bound", r.LowerBound),
+	)
+}
+
+// LogValue implements slog.LogValuer
+func (e *APIError) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("status_code", e.StatusCode),
+		slog.String("status", e.Status),
+	)
+}
+
+// LogValue implements slog.LogValuer
+func (e *BudgetExceededError) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("limit", e.Limit),
+		slog.Int("requests", e.Requests),
+		slog.Int("records", e.Records),
+		slog.Duration("elapsed", e.Elapsed),
+	)
+}
@@ -0,0 +1,45 @@
+//go:build go1.21
+
+package constellation_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestLogValue tests that logged params and responses are compact
+func TestLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	record := constellation.LinkRecord{
+		DID: "did:plc:a", Collection: constellation.CollectionLike, RKey: "3l",
+		Value: map[string]any{"secret": "payload"},
+	}
+	logger.Info("fetched",
+		"params", constellation.LinksParams{Target: "did:plc:a", Collection: constellation.CollectionFollow},
+		"page", &constellation.LinksResponse{Total: 1, LinkingRecords: []constellation.LinkRecord{record}},
+		"record", record,
+		"err", &constellation.APIError{StatusCode: 503, Status: "503 Service Unavailable"},
+	)
+
+	out := buf.String()
+	for _, want := range []string{
+		"params.target=did:plc:a",
+		"params.collection=app.bsky.graph.follow",
+		"page.records=1",
+		"record.uri=at://did:plc:a/app.bsky.feed.like/3l",
+		"err.status_code=503",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in %s", want, out)
+		}
+	}
+	if strings.Contains(out, "payload") {
+		t.Errorf("Expected record values to be omitted, got %s", out)
+	}
+}
@@ -0,0 +1,296 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DefaultMembershipScanLimit is the number of links membership checks scan on
+// instances without the server-side DID filter before giving up
+const DefaultMembershipScanLimit = 10000
+
+// ErrMembershipUnknown is returned by membership checks on instances without the
+// server-side DID filter when the DID isn't among the links scanned and the scan
+// stopped at Client.MembershipScanLimit before reaching the end
+var ErrMembershipUnknown = errors.New("membership unknown: scan limit reached")
+
+// IsLiker reports whether did has liked the post at postURI, for gating content on
+// a visitor's engagement. Results are cached when a membership cache is set.
+func (c *Client) IsLiker(ctx context.Context, did, postURI string) (bool, error) {
+	liked, _, err := c.DidLike(ctx, did, postURI)
+	return liked, err
+}
+
+// IsFollower reports whether did follows accountDID. Results are cached when a
+// membership cache is set.
+func (c *Client) IsFollower(ctx context.Context, did, accountDID string) (bool, error) {
+	follows, _, err := c.HasLink(ctx, did, followersParams(accountDID))
+	return follows, err
+}
+
+// Follows reports whether account a follows account b, checking for a among the
+// follow records linking to b. Results are cached when a membership cache is set.
+func (c *Client) Follows(ctx context.Context, a, b string) (bool, error) {
+	return c.IsFollower(ctx, a, b)
+}
+
+// MutualFollow reports whether accounts a and b follow each other. The reverse
+// follow is only checked if a follows b.
+func (c *Client) MutualFollow(ctx context.Context, a, b string) (bool, error) {
+	follows, err := c.Follows(ctx, a, b)
+	if err != nil || !follows {
+		return false, err
+	}
+	return c.Follows(ctx, b, a)
+}
+
+// IsBlockedBy reports whether account b blocks account a, so clients can avoid
+// interacting with accounts that block them. Results are cached when a membership
+// cache is set.
+func (c *Client) IsBlockedBy(ctx context.Context, a, b string) (bool, error) {
+	blocked, _, err := c.HasLink(ctx, b, blockersParams(a))
+	return blocked, err
+}
+
+// DidLike reports whether did has liked the post at postURI and returns the URI
+// of the like record when it has
+func (c *Client) DidLike(ctx context.Context, did, postURI string) (bool, string, error) {
+	return c.HasLink(ctx, did, likesParams(postURI))
+}
+
+// DidRepost reports whether did has reposted the post at postURI and returns the
+// URI of the repost record when it has
+func (c *Client) DidRepost(ctx context.Context, did, postURI string) (bool, string, error) {
+	return c.HasLink(ctx, did, repostsParams(postURI))
+}
+
+// HasLink reports whether did has a record linking to params.Target from
+// params.Collection at params.Path, and returns the URI of the first such record.
+// It uses the server-side DID filter when available and otherwise pages through
+// up to Client.MembershipScanLimit links, returning ErrMembershipUnknown if did
+// isn't among them. Results are cached when a membership cache is set, which
+// also caches the DIDs scanned so checks of other DIDs against the same target
+// don't scan again.
+func (c *Client) HasLink(ctx context.Context, did string, params LinksParams) (bool, string, error) {
+	params.FromDID = did
+	return c.isMember(ctx, params)
+}
+
+// UseMembershipCache routes HasLink and the checks built on it through a
+// read-through cache. On a miss the cache should load values with the getter from
+// MembershipGetter.
+func (c *Client) UseMembershipCache(cache Getter) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.membershipCache = cache
+	return c
+}
+
+// MembershipGetter returns a Getter that answers membership checks for keys built
+// by MembershipKey from the API, bypassing the membership cache. Values are "0"
+// for non-members, and "1" followed by a space and the matching record's URI for
+// members. It also loads the linking DIDs scanned for keys built by LinkersKey.
+func (c *Client) MembershipGetter() Getter {
+	return GetterFunc(c.loadMembership)
+}
+
+// MembershipKey builds the cache key for checking whether params.FromDID has a
+// record linking to params.Target
+func MembershipKey(params LinksParams) string {
+	return "member?" + params.queryValues(false).Encode()
+}
+
+// LinkersKey builds the cache key for the DIDs scanned when checking membership
+// against params.Target on an instance without the server-side DID filter
+func LinkersKey(params LinksParams) string {
+	params.FromDID = ""
+	return "linkers?" + params.queryValues(false).Encode()
+}
+
+// isMember checks membership through the membership cache, if any, returning the
+// matching record's URI for members
+func (c *Client) isMember(ctx context.Context, params LinksParams) (bool, string, error) {
+	if params.Target == "" || params.FromDID == "" {
+		return false, "", fmt.Errorf("DID and target are required")
+	}
+
+	c.mu.Lock()
+	cache := c.membershipCache
+	c.mu.Unlock()
+	if cache == nil {
+		return c.findLinkFrom(ctx, params)
+	}
+
+	data, err := cache.Get(ctx, MembershipKey(params))
+	if err != nil {
+		return false, "", err
+	}
+	uri, member := strings.CutPrefix(string(data), "1")
+	if !member {
+		return false, "", nil
+	}
+	return true, strings.TrimPrefix(uri, " "), nil
+}
+
+// loadMembership answers the membership check or linkers scan described by key
+// from the API
+func (c *Client) loadMembership(ctx context.Context, key string) ([]byte, error) {
+	if query, ok := strings.CutPrefix(key, "linkers?"); ok {
+		params, err := membershipParams(query)
+		if err != nil {
+			return nil, err
+		}
+		linkers, err := c.scanLinkers(ctx, params, "")
+		if err != nil {
+			return nil, err
+		}
+		return linkers.encode(), nil
+	}
+
+	query, ok := strings.CutPrefix(key, "member?")
+	if !ok {
+		return nil, fmt.Errorf("invalid membership key: %s", key)
+	}
+	params, err := membershipParams(query)
+	if err != nil {
+		return nil, err
+	}
+
+	member, uri, err := c.findLinkFrom(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if member {
+		return []byte("1 " + uri), nil
+	}
+	return []byte("0"), nil
+}
+
+// membershipParams parses the query of a membership cache key
+func membershipParams(query string) (LinksParams, error) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return LinksParams{}, fmt.Errorf("invalid membership key: %w", err)
+	}
+	return LinksParams{
+		Target:     values.Get("target"),
+		Collection: values.Get("collection"),
+		Path:       values.Get("path"),
+		FromDID:    values.Get("did"),
+	}, nil
+}
+
+// findLinkFrom reports whether params.FromDID has any record matching params and
+// returns the first match's URI. On instances without the server-side DID filter
+// it scans the links instead, through the membership cache when one is set.
+func (c *Client) findLinkFrom(ctx context.Context, params LinksParams) (bool, string, error) {
+	if !c.supportsFilter(FilterDID) {
+		return c.scanForLinkFrom(ctx, params)
+	}
+
+	params.Limit = 1
+	var match *LinkRecord
+	err := c.GetLinksEach(ctx, params, PaginateOptions{}, func(record LinkRecord) error {
+		match = &record
+		return errStopPagination
+	})
+	if err != nil || match == nil {
+		return false, "", err
+	}
+	return true, match.RecordURI(), nil
+}
+
+// scanForLinkFrom finds params.FromDID among the links to params.Target. With a
+// membership cache the whole scan is cached for later checks; otherwise it stops
+// at the first match.
+func (c *Client) scanForLinkFrom(ctx context.Context, params LinksParams) (bool, string, error) {
+	c.mu.Lock()
+	cache := c.membershipCache
+	c.mu.Unlock()
+
+	var scanned linkers
+	if cache != nil {
+		data, err := cache.Get(ctx, LinkersKey(params))
+		if err != nil {
+			return false, "", err
+		}
+		scanned = decodeLinkers(data)
+	} else {
+		var err error
+		if scanned, err = c.scanLinkers(ctx, params, params.FromDID); err != nil {
+			return false, "", err
+		}
+	}
+
+	if uri, ok := scanned.uris[params.FromDID]; ok {
+		return true, uri, nil
+	}
+	if !scanned.complete {
+		return false, "", ErrMembershipUnknown
+	}
+	return false, "", nil
+}
+
+// linkers maps the DIDs found linking to a target to the URI of their first
+// linking record
+type linkers struct {
+	uris     map[string]string
+	complete bool // Every link was scanned
+}
+
+// scanLinkers pages through up to the scan limit of links to params.Target
+// without the DID filter, stopping early once stopAt is found if it's set
+func (c *Client) scanLinkers(ctx context.Context, params LinksParams, stopAt string) (linkers, error) {
+	limit := c.MembershipScanLimit
+	if limit <= 0 {
+		limit = DefaultMembershipScanLimit
+	}
+	params.FromDID = ""
+
+	found := linkers{uris: make(map[string]string)}
+	scanned := 0
+	err := c.GetLinksEach(ctx, params, PaginateOptions{MaxRecords: limit + 1}, func(record LinkRecord) error {
+		if scanned++; scanned > limit {
+			return errStopPagination
+		}
+		if _, ok := found.uris[record.DID]; !ok {
+			found.uris[record.DID] = record.RecordURI()
+		}
+		if stopAt != "" && record.DID == stopAt {
+			return errStopPagination
+		}
+		return nil
+	})
+	found.complete = scanned <= limit && (stopAt == "" || found.uris[stopAt] == "")
+	return found, err
+}
+
+// encode serializes the scan for the membership cache as a "complete" or
+// "partial" line followed by a "did uri" line per DID
+func (l linkers) encode() []byte {
+	var b strings.Builder
+	if l.complete {
+		b.WriteString("complete\n")
+	} else {
+		b.WriteString("partial\n")
+	}
+	for did, uri := range l.uris {
+		b.WriteString(did + " " + uri + "\n")
+	}
+	return []byte(b.String())
+}
+
+// decodeLinkers parses a scan serialized by encode
+func decodeLinkers(data []byte) linkers {
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	scanned := linkers{uris: make(map[string]string), complete: lines[0] == "complete"}
+	for _, line := range lines[1:] {
+		if did, uri, ok := strings.Cut(line, " "); ok {
+			scanned.uris[did] = uri
+		}
+	}
+	return scanned
+}
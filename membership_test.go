@@ -0,0 +1,197 @@
+package constellation_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+	"github.com/tanner-caffrey/constellation-go/constellationtest"
+)
+
+// TestIsLiker tests like membership checks with client-side DID filtering and caching
+func TestIsLiker(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"linking_records": [{"did": "did:plc:alice"}], "cursor": "next"}`))
+			return
+		}
+		w.Write([]byte(`{"linking_records": [{"did": "did:plc:bob"}]}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.UseMembershipCache(constellation.NewMemoryCache(time.Minute, client.MembershipGetter()))
+	ctx := context.Background()
+	post := "at://did:plc:creator/app.bsky.feed.post/1"
+
+	liked, err := client.IsLiker(ctx, "did:plc:bob", post)
+	if err != nil || !liked {
+		t.Fatalf("Expected bob to be a liker, got %v, %v", liked, err)
+	}
+	liked, err = client.IsLiker(ctx, "did:plc:carol", post)
+	if err != nil || liked {
+		t.Fatalf("Expected carol not to be a liker, got %v, %v", liked, err)
+	}
+
+	before := requests
+	if liked, _ := client.IsLiker(ctx, "did:plc:bob", post); !liked || requests != before {
+		t.Errorf("Expected cached result without requests, got %v after %d requests", liked, requests-before)
+	}
+}
+
+// TestIsFollowerServerFilter tests follower checks using the server-side DID filter
+func TestIsFollowerServerFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("did") != "did:plc:alice" || query.Get("limit") != "1" || query.Get("path") != ".subject" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"linking_records": [{"did": "did:plc:alice"}]}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.UseCapabilities(&constellation.Capabilities{Filters: []string{constellation.FilterDID}})
+
+	follows, err := client.IsFollower(context.Background(), "did:plc:alice", "did:plc:creator")
+	if err != nil || !follows {
+		t.Errorf("Expected alice to follow, got %v, %v", follows, err)
+	}
+}
+
+// TestDidLike tests that membership checks return the matching record's URI,
+// including through the cache
+func TestDidLike(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("collection") {
+		case constellation.CollectionLike:
+			w.Write([]byte(`{"linking_records": [{"did": "did:plc:alice", "collection": "app.bsky.feed.like", "rkey": "3l"}]}`))
+		default:
+			w.Write([]byte(`{"linking_records": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.UseMembershipCache(constellation.NewMemoryCache(time.Minute, client.MembershipGetter()))
+	ctx := context.Background()
+	post := "at://did:plc:creator/app.bsky.feed.post/1"
+
+	for i := 0; i < 2; i++ {
+		liked, uri, err := client.DidLike(ctx, "did:plc:alice", post)
+		if err != nil || !liked || uri != "at://did:plc:alice/app.bsky.feed.like/3l" {
+			t.Errorf("Expected alice's like, got %v, %q, %v", liked, uri, err)
+		}
+	}
+
+	reposted, uri, err := client.DidRepost(ctx, "did:plc:alice", post)
+	if err != nil || reposted || uri != "" {
+		t.Errorf("Expected no repost, got %v, %q, %v", reposted, uri, err)
+	}
+
+	voted, _, err := client.HasLink(ctx, "did:plc:alice", constellation.LinksParams{Target: post, Collection: "com.example.vote", Path: ".subject.uri"})
+	if err != nil || voted {
+		t.Errorf("Expected no vote, got %v, %v", voted, err)
+	}
+}
+
+// TestMutualFollow tests follow checks in both directions with early exit
+func TestMutualFollow(t *testing.T) {
+	follows := map[string][]string{
+		"did:plc:alice": {"did:plc:bob"},
+		"did:plc:bob":   {"did:plc:alice", "did:plc:carol"},
+		"did:plc:carol": {},
+	}
+	var queried []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := r.URL.Query().Get("target")
+		queried = append(queried, target)
+		w.Write([]byte(`{"linking_records": [`))
+		for i, follower := range follows[target] {
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			w.Write([]byte(`{"did": "` + follower + `", "collection": "app.bsky.graph.follow", "rkey": "1"}`))
+		}
+		w.Write([]byte(`]}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	ctx := context.Background()
+
+	if ok, err := client.Follows(ctx, "did:plc:carol", "did:plc:bob"); err != nil || !ok {
+		t.Errorf("Expected carol to follow bob, got %v, %v", ok, err)
+	}
+	if ok, err := client.MutualFollow(ctx, "did:plc:alice", "did:plc:bob"); err != nil || !ok {
+		t.Errorf("Expected alice and bob to be mutuals, got %v, %v", ok, err)
+	}
+
+	queried = nil
+	if ok, err := client.MutualFollow(ctx, "did:plc:bob", "did:plc:carol"); err != nil || ok {
+		t.Errorf("Expected bob and carol not to be mutuals, got %v, %v", ok, err)
+	}
+	if len(queried) != 1 {
+		t.Errorf("Expected one direction to be checked, queried %v", queried)
+	}
+}
+
+// TestIsBlockedBy tests that blocks are looked up among the blocker's records targeting the blocked account
+func TestIsBlockedBy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("target") != "did:plc:alice" || query.Get("collection") != constellation.CollectionBlock {
+			w.Write([]byte(`{"linking_records": []}`))
+			return
+		}
+		w.Write([]byte(`{"linking_records": [{"did": "did:plc:bob", "collection": "app.bsky.graph.block", "rkey": "1"}]}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	ctx := context.Background()
+
+	if blocked, err := client.IsBlockedBy(ctx, "did:plc:alice", "did:plc:bob"); err != nil || !blocked {
+		t.Errorf("Expected bob to block alice, got %v, %v", blocked, err)
+	}
+	if blocked, err := client.IsBlockedBy(ctx, "did:plc:bob", "did:plc:alice"); err != nil || blocked {
+		t.Errorf("Expected alice not to block bob, got %v, %v", blocked, err)
+	}
+	if blocked, err := client.IsBlockedBy(ctx, "did:plc:alice", "did:plc:carol"); err != nil || blocked {
+		t.Errorf("Expected carol not to block alice, got %v, %v", blocked, err)
+	}
+}
+
+// TestMembershipScanLimit tests that scans without the DID filter are bounded,
+// and that a membership cache answers checks of other DIDs from one scan
+func TestMembershipScanLimit(t *testing.T) {
+	server := newPagedServer(t, 50)
+	ctx := context.Background()
+	params := constellation.LinksParams{Target: "at://did:plc:creator/app.bsky.feed.post/1", Collection: "app.bsky.feed.like", Path: ".subject.uri", Limit: 10}
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.MembershipScanLimit = 20
+	if _, _, err := client.HasLink(ctx, "did:plc:user5", params); err != nil {
+		t.Errorf("Expected a DID within the limit to be found, got: %v", err)
+	}
+	if _, _, err := client.HasLink(ctx, "did:plc:user40", params); !errors.Is(err, constellation.ErrMembershipUnknown) {
+		t.Errorf("Expected ErrMembershipUnknown past the limit, got: %v", err)
+	}
+
+	recorder := constellationtest.NewRequestRecorder(server.Config.Handler)
+	cached := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	recorder.Install(cached)
+	cached.UseMembershipCache(constellation.NewMemoryCache(time.Minute, cached.MembershipGetter()))
+	for _, did := range []string{"did:plc:user3", "did:plc:user49", "did:plc:nobody"} {
+		if member, _, err := cached.HasLink(ctx, did, params); err != nil || member == (did == "did:plc:nobody") {
+			t.Errorf("Unexpected result for %s: %v, %v", did, member, err)
+		}
+	}
+	recorder.AssertCount(t, 1)
+}
@@ -0,0 +1,248 @@
+package constellation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxLatencySamples is the number of recent requests kept for latency statistics
+const maxLatencySamples = 1024
+
+// latencySample records the outcome of a single request
+type latencySample struct {
+	endpoint    string
+	concurrency int // Requests in flight when this request started, including itself
+	latency     time.Duration
+	failed      bool
+}
+
+// requestMetrics tracks in-flight concurrency and recent request latencies
+type requestMetrics struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	total    int64
+	errors   int64
+	samples  []latencySample
+	next     int
+}
+
+// MetricsSnapshot is a point-in-time view of the client's request metrics
+type MetricsSnapshot struct {
+	InFlight     int   // Requests currently in flight
+	PeakInFlight int   // Highest number of concurrent requests observed
+	Requests     int64 // Total requests made
+	Errors       int64 // Total failed requests
+	LatencyP50   time.Duration
+	LatencyP95   time.Duration
+	LatencyP99   time.Duration
+}
+
+// Advice is a recommendation for concurrency and request rate settings
+type Advice struct {
+	Concurrency   int     // Recommended maximum concurrent requests
+	RatePerSecond float64 // Recommended sustained request rate
+	Reason        string  // Explanation of the recommendation
+}
+
+// start records a request entering flight and returns the current concurrency
+func (m *requestMetrics) start() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight++
+	if m.inFlight > m.peak {
+		m.peak = m.inFlight
+	}
+	return m.inFlight
+}
+
+// finish records a completed request
+func (m *requestMetrics) finish(sample latencySample) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight--
+	m.total++
+	if sample.failed {
+		m.errors++
+	}
+
+	if len(m.samples) < maxLatencySamples {
+		m.samples = append(m.samples, sample)
+	} else {
+		m.samples[m.next] = sample
+	}
+	m.next = (m.next + 1) % maxLatencySamples
+}
+
+// latencies returns recent successful latencies, optionally filtered by endpoint
+func (m *requestMetrics) latencies(endpoint string) []time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var latencies []time.Duration
+	for _, sample := range m.samples {
+		if sample.failed || (endpoint != "" && sample.endpoint != endpoint) {
+			continue
+		}
+		latencies = append(latencies, sample.latency)
+	}
+	return latencies
+}
+
+// number is the set of types percentile, minOf, and maxOf accept
+type number interface {
+	~int | ~int64 | ~float64
+}
+
+// percentile returns the p-th percentile (0-100) of values
+func percentile[T number](values []T, p float64) T {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]T(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	index := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index]
+}
+
+// Metrics returns a snapshot of the client's request concurrency and latency metrics
+func (c *Client) Metrics() MetricsSnapshot {
+	latencies := c.metrics.latencies("")
+
+	c.metrics.mu.Lock()
+	defer c.metrics.mu.Unlock()
+
+	return MetricsSnapshot{
+		InFlight:     c.metrics.inFlight,
+		PeakInFlight: c.metrics.peak,
+		Requests:     c.metrics.total,
+		Errors:       c.metrics.errors,
+		LatencyP50:   percentile(latencies, 50),
+		LatencyP95:   percentile(latencies, 95),
+		LatencyP99:   percentile(latencies, 99),
+	}
+}
+
+// LatencyPercentile returns the p-th percentile (0-100) of recent successful request
+// latencies for an endpoint such as "/links", or for all endpoints if endpoint is empty
+func (c *Client) LatencyPercentile(endpoint string, p float64) time.Duration {
+	return percentile(c.metrics.latencies(endpoint), p)
+}
+
+// Advise recommends concurrency and rate settings based on observed latencies.
+// It picks the highest observed concurrency level whose mean latency stays within
+// 1.5x of the lowest level's and whose error rate stays below 5%.
+func (c *Client) Advise() Advice {
+	c.metrics.mu.Lock()
+	type level struct {
+		total    time.Duration
+		count    int
+		failures int
+	}
+	levels := make(map[int]*level)
+	for _, sample := range c.metrics.samples {
+		l := levels[sample.concurrency]
+		if l == nil {
+			l = &level{}
+			levels[sample.concurrency] = l
+		}
+		l.count++
+		if sample.failed {
+			l.failures++
+			continue
+		}
+		l.total += sample.latency
+	}
+	c.metrics.mu.Unlock()
+
+	if len(levels) == 0 {
+		return Advice{Concurrency: 1, Reason: "no requests observed yet"}
+	}
+
+	concurrencies := make([]int, 0, len(levels))
+	for concurrency := range levels {
+		concurrencies = append(concurrencies, concurrency)
+	}
+	sort.Ints(concurrencies)
+
+	mean := func(l *level) time.Duration {
+		if successes := l.count - l.failures; successes > 0 {
+			return l.total / time.Duration(successes)
+		}
+		return 0
+	}
+
+	baseline := mean(levels[concurrencies[0]])
+	best, bestLatency := concurrencies[0], baseline
+	for _, concurrency := range concurrencies {
+		l := levels[concurrency]
+		latency := mean(l)
+		if latency == 0 || float64(l.failures)/float64(l.count) >= 0.05 {
+			break
+		}
+		if float64(latency) > 1.5*float64(baseline) {
+			break
+		}
+		best, bestLatency = concurrency, latency
+	}
+
+	advice := Advice{
+		Concurrency: best,
+		Reason: fmt.Sprintf("mean latency %s at concurrency %d (baseline %s)",
+			bestLatency.Round(time.Millisecond), best, baseline.Round(time.Millisecond)),
+	}
+	if bestLatency > 0 {
+		advice.RatePerSecond = float64(best) / bestLatency.Seconds()
+	}
+	return advice
+}
+
+// SetMaxConcurrency limits the number of concurrent requests made by the client.
+// A value of zero or less removes the limit.
+func (c *Client) SetMaxConcurrency(n int) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if n <= 0 {
+		c.semaphore = nil
+	} else {
+		c.semaphore = make(chan struct{}, n)
+	}
+	return c
+}
+
+// ApplyAdvice computes a recommendation with Advise and applies its concurrency limit
+func (c *Client) ApplyAdvice() Advice {
+	advice := c.Advise()
+	c.SetMaxConcurrency(advice.Concurrency)
+	return advice
+}
+
+// acquireSlot waits for a concurrency slot and returns a function that releases it
+func (c *Client) acquireSlot(ctx context.Context) (func(), error) {
+	c.mu.Lock()
+	semaphore := c.semaphore
+	c.mu.Unlock()
+
+	if semaphore == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case semaphore <- struct{}{}:
+		return func() { <-semaphore }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,89 @@
+package constellation_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestMetrics tests request counting and concurrency tracking
+func TestMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`{"days_indexed": 1}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.GetAPIInfo()
+		}()
+	}
+	wg.Wait()
+
+	metrics := client.Metrics()
+	if metrics.Requests != 4 {
+		t.Errorf("Expected 4 requests, got %d", metrics.Requests)
+	}
+	if metrics.InFlight != 0 {
+		t.Errorf("Expected no requests in flight, got %d", metrics.InFlight)
+	}
+	if metrics.PeakInFlight < 1 {
+		t.Errorf("Expected positive peak concurrency, got %d", metrics.PeakInFlight)
+	}
+	if metrics.LatencyP50 <= 0 {
+		t.Errorf("Expected positive median latency, got %s", metrics.LatencyP50)
+	}
+
+	advice := client.ApplyAdvice()
+	if advice.Concurrency < 1 {
+		t.Errorf("Expected positive recommended concurrency, got %d", advice.Concurrency)
+	}
+}
+
+// TestSetMaxConcurrency tests that the concurrency limit is enforced
+func TestSetMaxConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	active, peak := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		if active > peak {
+			peak = active
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second).SetMaxConcurrency(2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.GetAPIInfo()
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", peak)
+	}
+}
@@ -0,0 +1,220 @@
+package constellation
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMirrorUploads is the default number of concurrent mirror uploads
+const DefaultMirrorUploads = 4
+
+// ObjectStore stores mirrored responses. S3Store implements it for S3-compatible
+// storage; other backends only need this one method.
+type ObjectStore interface {
+	// PutObject stores data under key with the given metadata. Storing the same
+	// body key twice must be harmless, since body keys are content-addressed.
+	PutObject(ctx context.Context, key string, data []byte, metadata map[string]string) error
+}
+
+// Mirror archives the raw body of every successful response to an ObjectStore.
+// Bodies are keyed by the SHA-256 of their contents, so identical responses are
+// stored once. Each archived response also gets its own MirrorManifest, recording
+// the endpoint, query, and fetch time and pointing at the body, so responses
+// shared by many queries keep the provenance of every one.
+//
+// Uploads run in the background and never slow down or fail requests. When
+// MaxUploads uploads are already in flight, further responses are dropped and
+// counted rather than queued, which bounds the memory and bandwidth spent.
+//
+// ObjectStore can't list or delete, so the mirror is outside the reach of the
+// retention helpers. Find a DID's requests with ManifestMentionsDID and delete
+// them through the store itself. Bodies can't be scrubbed per DID, since one
+// body may serve many queries and holds records from many authors; give the
+// sha256/ prefix a lifecycle rule that expires objects within your retention
+// period instead.
+type Mirror struct {
+	Store      ObjectStore
+	Prefix     string        // Prepended to every key, e.g. "constellation/"
+	SampleRate float64       // Fraction of responses to archive; zero archives all
+	MaxUploads int           // Concurrent uploads; defaults to DefaultMirrorUploads
+	Timeout    time.Duration // Per-upload timeout; zero means no timeout
+	OnError    func(key string, err error)
+
+	once     sync.Once
+	slots    chan struct{}
+	wg       sync.WaitGroup
+	uploaded atomic.Int64
+	dropped  atomic.Int64
+}
+
+// MirrorManifest records one archived response: the request that produced it
+// and where its body is stored
+type MirrorManifest struct {
+	Endpoint  string    `json:"endpoint"`
+	Query     string    `json:"query"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Object    string    `json:"object"` // Key of the content-addressed body
+	SHA256    string    `json:"sha256"`
+	Bytes     int       `json:"bytes"`
+}
+
+// MirrorKey returns the content-addressed key for a response body
+func MirrorKey(prefix string, body []byte) string {
+	sum := sha256.Sum256(body)
+	digest := hex.EncodeToString(sum[:])
+	return prefix + "sha256/" + digest[:2] + "/" + digest + ".json"
+}
+
+// MirrorManifestKey returns the key for a manifest, grouped by fetch date and
+// unique to its request and fetch time
+func MirrorManifestKey(prefix string, m MirrorManifest) string {
+	fetched := m.FetchedAt.UTC()
+	request := sha256Hex([]byte(m.Endpoint + "?" + m.Query))
+	return fmt.Sprintf("%smanifests/%s/%020d-%s.json", prefix, fetched.Format("2006/01/02"), fetched.UnixNano(), request[:16])
+}
+
+// UseMirror archives the client's successful responses to m
+func (c *Client) UseMirror(m *Mirror) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.mirror = m
+	return c
+}
+
+// Uploaded returns the number of responses stored so far
+func (m *Mirror) Uploaded() int64 {
+	return m.uploaded.Load()
+}
+
+// Dropped returns the number of sampled responses skipped because the upload
+// limit was reached
+func (m *Mirror) Dropped() int64 {
+	return m.dropped.Load()
+}
+
+// Wait blocks until all in-flight uploads finish
+func (m *Mirror) Wait() {
+	m.wg.Wait()
+}
+
+// sampled reports whether the next response should be archived
+func (m *Mirror) sampled() bool {
+	return m.SampleRate <= 0 || m.SampleRate >= 1 || rand.Float64() < m.SampleRate
+}
+
+// wrap returns body, buffering it for upload if the response is sampled
+func (m *Mirror) wrap(endpoint string, params url.Values, body io.ReadCloser) io.ReadCloser {
+	if !m.sampled() {
+		return body
+	}
+	return &mirroredBody{ReadCloser: body, mirror: m, endpoint: endpoint, query: params.Encode(), fetched: time.Now().UTC()}
+}
+
+// upload stores body and its manifest in the background unless the upload limit
+// is reached. The manifest is only written once its body is stored.
+func (m *Mirror) upload(body []byte, manifest MirrorManifest) {
+	m.once.Do(func() {
+		uploads := m.MaxUploads
+		if uploads <= 0 {
+			uploads = DefaultMirrorUploads
+		}
+		m.slots = make(chan struct{}, uploads)
+	})
+
+	select {
+	case m.slots <- struct{}{}:
+	default:
+		m.dropped.Add(1)
+		return
+	}
+
+	manifest.Object = MirrorKey(m.Prefix, body)
+	manifest.SHA256 = sha256Hex(body)
+	manifest.Bytes = len(body)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer func() { <-m.slots }()
+
+		ctx := context.Background()
+		if m.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, m.Timeout)
+			defer cancel()
+		}
+		if err := m.Store.PutObject(ctx, manifest.Object, body, nil); err != nil {
+			m.fail(manifest.Object, err)
+			return
+		}
+
+		key := MirrorManifestKey(m.Prefix, manifest)
+		data, err := json.Marshal(manifest)
+		if err == nil {
+			err = m.Store.PutObject(ctx, key, data, map[string]string{
+				"endpoint":   manifest.Endpoint,
+				"query":      manifest.Query,
+				"fetched-at": manifest.FetchedAt.Format(time.RFC3339),
+			})
+		}
+		if err != nil {
+			m.fail(key, err)
+			return
+		}
+		m.uploaded.Add(1)
+	}()
+}
+
+// fail reports an upload error to OnError, if set
+func (m *Mirror) fail(key string, err error) {
+	if m.OnError != nil {
+		m.OnError(key, err)
+	}
+}
+
+// mirroredBody copies a response body as it's read and uploads it on Close.
+// Decoders may stop before EOF, so Close reads the remainder first; bodies that
+// can't be read to the end are never archived.
+type mirroredBody struct {
+	io.ReadCloser
+	mirror   *Mirror
+	endpoint string
+	query    string
+	fetched  time.Time
+	buf      bytes.Buffer
+	failed   bool
+	once     sync.Once
+}
+
+func (b *mirroredBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if err != nil && err != io.EOF {
+		b.failed = true
+	}
+	return n, err
+}
+
+func (b *mirroredBody) Close() error {
+	b.once.Do(func() {
+		if _, err := io.Copy(&b.buf, b.ReadCloser); err != nil || b.failed {
+			return
+		}
+		b.mirror.upload(b.buf.Bytes(), MirrorManifest{
+			Endpoint:  b.endpoint,
+			Query:     b.query,
+			FetchedAt: b.fetched,
+		})
+	})
+	return b.ReadCloser.Close()
+}
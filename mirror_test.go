@@ -0,0 +1,136 @@
+package constellation_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// memoryStore is an in-memory ObjectStore
+type memoryStore struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	metadata map[string]map[string]string
+	block    chan struct{}
+}
+
+func (s *memoryStore) PutObject(ctx context.Context, key string, data []byte, metadata map[string]string) error {
+	if s.block != nil {
+		<-s.block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.objects == nil {
+		s.objects = make(map[string][]byte)
+		s.metadata = make(map[string]map[string]string)
+	}
+	s.objects[key] = append([]byte(nil), data...)
+	s.metadata[key] = metadata
+	return nil
+}
+
+// TestMirror tests archiving bodies under content-addressed keys, with a
+// manifest per response so identical bodies keep every query's provenance
+func TestMirror(t *testing.T) {
+	body := `{"total": 3}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	store := &memoryStore{}
+	mirror := &constellation.Mirror{Store: store, Prefix: "corpus/"}
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second).UseMirror(mirror)
+
+	for _, target := range []string{"at://a", "at://b"} {
+		params := constellation.LinksParams{Target: target, Collection: "app.bsky.feed.like", Path: ".subject.uri"}
+		if _, err := client.GetLinksCount(params); err != nil {
+			t.Fatalf("Failed to get count: %v", err)
+		}
+	}
+	mirror.Wait()
+
+	key := constellation.MirrorKey("corpus/", []byte(body))
+	if !strings.HasPrefix(key, "corpus/sha256/") {
+		t.Errorf("Unexpected key %q", key)
+	}
+	if string(store.objects[key]) != body {
+		t.Fatalf("Expected the body at %s, got %v", key, store.objects)
+	}
+
+	targets := map[string]bool{}
+	for name, data := range store.objects {
+		if !strings.HasPrefix(name, "corpus/manifests/") {
+			continue
+		}
+		var manifest constellation.MirrorManifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			t.Fatalf("Failed to decode manifest %s: %v", name, err)
+		}
+		if manifest.Object != key || manifest.Endpoint != "/links/count" || manifest.Bytes != len(body) {
+			t.Errorf("Unexpected manifest %+v", manifest)
+		}
+		if name != constellation.MirrorManifestKey("corpus/", manifest) {
+			t.Errorf("Expected manifest %s under its own key", name)
+		}
+		query, _ := url.ParseQuery(manifest.Query)
+		targets[query.Get("target")] = true
+	}
+	if len(store.objects) != 3 || !targets["at://a"] || !targets["at://b"] {
+		t.Errorf("Expected one body and a manifest per query, got %d objects for targets %v", len(store.objects), targets)
+	}
+	if mirror.Uploaded() != 2 {
+		t.Errorf("Expected 2 uploads, got %d", mirror.Uploaded())
+	}
+}
+
+// TestMirrorThrottle tests that responses are dropped while uploads are saturated
+func TestMirrorThrottle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total": 1}`))
+	}))
+	defer server.Close()
+
+	store := &memoryStore{block: make(chan struct{})}
+	mirror := &constellation.Mirror{Store: store, MaxUploads: 1}
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second).UseMirror(mirror)
+
+	params := constellation.LinksParams{Target: "at://a", Collection: "app.bsky.feed.like", Path: ".subject.uri"}
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetLinksCount(params); err != nil {
+			t.Fatalf("Failed to get count: %v", err)
+		}
+	}
+	close(store.block)
+	mirror.Wait()
+
+	if mirror.Uploaded() != 1 || mirror.Dropped() != 2 {
+		t.Errorf("Expected 1 upload and 2 drops, got %d and %d", mirror.Uploaded(), mirror.Dropped())
+	}
+}
+
+// TestMirrorSkipsErrors tests that failed responses aren't archived
+func TestMirrorSkipsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	store := &memoryStore{}
+	mirror := &constellation.Mirror{Store: store}
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second).UseMirror(mirror)
+
+	client.GetAPIInfo()
+	mirror.Wait()
+	if len(store.objects) != 0 {
+		t.Errorf("Expected nothing archived, got %v", store.objects)
+	}
+}
@@ -0,0 +1,177 @@
+package constellation
+
+import (
+	"net/url"
+	"strings"
+)
+
+// NormalizeTarget rewrites target into a canonical form, for comparing targets
+// or building keys outside the client. Queries don't apply it: the server
+// matches the string a record links exactly, and records may link a URL or
+// handle in any form, so rewriting a query's target could stop it matching.
+// Queries only apply the rewriting that can't, described in LinksParams.Target.
+// Normalize a target yourself when you know records use the canonical form.
+//
+//   - DIDs: the method, and the identifier of did:plc and the host of did:web, are
+//     lowercased, the escaped port of did:web uppercased, and trailing slashes
+//     dropped
+//   - AT URIs: the scheme and authority are normalized like DIDs, handles are
+//     lowercased, and trailing slashes dropped; collections and record keys are
+//     case-sensitive and kept
+//   - URLs: the scheme and host are lowercased, default ports dropped, characters
+//     outside ASCII (as in IRIs), spaces, and controls percent-encoded, and
+//     percent-escapes uppercased. Paths, including trailing slashes, are kept,
+//     since sites may treat them differently.
+//
+// Surrounding whitespace is trimmed from every target; other targets are
+// otherwise unchanged.
+func NormalizeTarget(target string) string {
+	target = strings.TrimSpace(target)
+	lower := strings.ToLower(target)
+	switch {
+	case strings.HasPrefix(lower, "at://"):
+		return normalizeATURI(target[len("at://"):])
+	case strings.HasPrefix(lower, "did:"):
+		return normalizeDID(target)
+	case strings.HasPrefix(lower, "https://"), strings.HasPrefix(lower, "http://"):
+		return normalizeURL(target)
+	}
+	return target
+}
+
+// queryTarget prepares a target for a query, rewriting only what can't stop it
+// matching: surrounding whitespace is trimmed, and the scheme of AT URIs and the
+// method of DIDs, alone or as an AT URI's authority, are lowercased, as is a
+// did:plc identifier, since records always store those in lowercase
+func queryTarget(target string) string {
+	target = strings.TrimSpace(target)
+	lower := strings.ToLower(target)
+	switch {
+	case strings.HasPrefix(lower, "at://"):
+		authority, path, found := strings.Cut(target[len("at://"):], "/")
+		uri := "at://" + lowerDID(authority)
+		if found {
+			uri += "/" + path
+		}
+		return uri
+	case strings.HasPrefix(lower, "did:"):
+		return lowerDID(target)
+	}
+	return target
+}
+
+// lowerDID lowercases the parts of a DID that are always stored in lowercase:
+// the method, and the identifier of did:plc. Other strings are unchanged.
+func lowerDID(did string) string {
+	parts := strings.SplitN(did, ":", 3)
+	if len(parts) != 3 || !strings.EqualFold(parts[0], "did") {
+		return did
+	}
+
+	method, id := strings.ToLower(parts[1]), parts[2]
+	if method == "plc" {
+		id = strings.ToLower(id)
+	}
+	return "did:" + method + ":" + id
+}
+
+// normalizeDID lowercases the case-insensitive parts of a DID
+func normalizeDID(did string) string {
+	did = strings.TrimRight(did, "/")
+	parts := strings.SplitN(did, ":", 3)
+	if len(parts) != 3 {
+		return did
+	}
+
+	method, id := strings.ToLower(parts[1]), parts[2]
+	switch method {
+	case "plc":
+		id = strings.ToLower(id)
+	case "web":
+		// The host comes first, with its port percent-encoded; later
+		// colon-separated segments are a path
+		host, path, found := strings.Cut(id, ":")
+		id = escapeIRI(strings.ToLower(host))
+		if found {
+			id += ":" + path
+		}
+	}
+	return "did:" + method + ":" + id
+}
+
+// normalizeATURI normalizes the part of an AT URI after the scheme
+func normalizeATURI(rest string) string {
+	rest = strings.TrimRight(rest, "/")
+	authority, path, found := strings.Cut(rest, "/")
+	if strings.HasPrefix(strings.ToLower(authority), "did:") {
+		authority = normalizeDID(authority)
+	} else {
+		authority = strings.ToLower(authority)
+	}
+
+	uri := "at://" + authority
+	if found {
+		uri += "/" + path
+	}
+	return uri
+}
+
+// normalizeURL normalizes a web URL's scheme and host and percent-encodes the rest
+func normalizeURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	switch {
+	case scheme == "https":
+		host = strings.TrimSuffix(host, ":443")
+	case scheme == "http":
+		host = strings.TrimSuffix(host, ":80")
+	}
+
+	// Keep the path, query, and fragment as written rather than re-encoding
+	// them through url.URL, which would change escapes the site may rely on
+	rest := raw[len(u.Scheme)+len("://"):]
+	if i := strings.IndexAny(rest, "/?#"); i >= 0 {
+		rest = rest[i:]
+	} else {
+		rest = ""
+	}
+
+	authority := host
+	if u.User != nil {
+		authority = u.User.String() + "@" + host
+	}
+	return scheme + "://" + authority + escapeIRI(rest)
+}
+
+// escapeIRI percent-encodes bytes that can't appear in a URI and uppercases
+// existing percent-escapes
+func escapeIRI(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			b.WriteByte('%')
+			b.WriteString(strings.ToUpper(s[i+1 : i+3]))
+			i += 2
+		case c <= ' ' || c >= 0x7f:
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0xf])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// isHex reports whether c is a hexadecimal digit
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
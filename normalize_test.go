@@ -0,0 +1,104 @@
+package constellation_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// normalizeCases maps targets to their normalized form
+var normalizeCases = []struct {
+	name, target, want string
+}{
+	{"plain DID", "did:plc:abc123", "did:plc:abc123"},
+	{"uppercase plc DID", "DID:PLC:ABC123", "did:plc:abc123"},
+	{"did:web host", "did:web:Example.COM", "did:web:example.com"},
+	{"did:web path kept", "did:web:Example.com:Users:Alice", "did:web:example.com:Users:Alice"},
+	{"did:web port", "did:web:localhost%3A8080", "did:web:localhost%3A8080"},
+	{"DID trailing slash", "did:plc:abc/", "did:plc:abc"},
+	{"DID whitespace", "  did:plc:abc\n", "did:plc:abc"},
+	{"unknown method kept", "did:Example:MixedCase", "did:example:MixedCase"},
+
+	{"AT URI", "at://did:plc:abc/app.bsky.feed.post/3k2", "at://did:plc:abc/app.bsky.feed.post/3k2"},
+	{"AT URI uppercase authority", "AT://DID:PLC:ABC/app.bsky.feed.post/3K2", "at://did:plc:abc/app.bsky.feed.post/3K2"},
+	{"AT URI trailing slash", "at://did:plc:abc/app.bsky.feed.post/3k2/", "at://did:plc:abc/app.bsky.feed.post/3k2"},
+	{"AT URI handle", "at://Alice.Bsky.Social/app.bsky.feed.post/1", "at://alice.bsky.social/app.bsky.feed.post/1"},
+	{"AT URI repo only", "at://did:plc:ABC/", "at://did:plc:abc"},
+
+	{"URL", "https://example.com/a/b?c=d#e", "https://example.com/a/b?c=d#e"},
+	{"URL host case", "HTTPS://Example.COM/Path", "https://example.com/Path"},
+	{"URL default port", "https://example.com:443/a", "https://example.com/a"},
+	{"URL http default port", "http://example.com:80/a", "http://example.com/a"},
+	{"URL other port kept", "https://example.com:8443/a", "https://example.com:8443/a"},
+	{"URL trailing slash kept", "https://example.com/a/", "https://example.com/a/"},
+	{"URL no path", "https://example.com", "https://example.com"},
+	{"IRI path", "https://example.com/café", "https://example.com/caf%C3%A9"},
+	{"IRI query", "https://example.com/?q=日本", "https://example.com/?q=%E6%97%A5%E6%9C%AC"},
+	{"space", "https://example.com/a b", "https://example.com/a%20b"},
+	{"lowercase escape", "https://example.com/a%2fb", "https://example.com/a%2Fb"},
+	{"stray percent", "https://example.com/100%", "https://example.com/100%"},
+	{"reserved characters kept", "https://example.com/a+b&c=d;e", "https://example.com/a+b&c=d;e"},
+	{"userinfo", "https://User@Example.com/", "https://User@example.com/"},
+
+	{"unknown scheme", " Some Target ", "Some Target"},
+}
+
+// TestNormalizeTarget tests normalization across target kinds
+func TestNormalizeTarget(t *testing.T) {
+	for _, tc := range normalizeCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := constellation.NormalizeTarget(tc.target)
+			if got != tc.want {
+				t.Errorf("NormalizeTarget(%q) = %q, want %q", tc.target, got, tc.want)
+			}
+			if again := constellation.NormalizeTarget(got); again != got {
+				t.Errorf("Expected normalization to be idempotent, got %q then %q", got, again)
+			}
+		})
+	}
+}
+
+// queryCases maps targets to the form queries send, which only rewrites what
+// can't stop a target matching
+var queryCases = []struct {
+	name, target, want string
+}{
+	{"uppercase plc DID", "DID:PLC:ABC123", "did:plc:abc123"},
+	{"did:web host kept", "did:web:Example.COM", "did:web:Example.COM"},
+	{"DID trailing slash kept", "did:plc:abc/", "did:plc:abc/"},
+	{"DID whitespace", "  did:plc:abc\n", "did:plc:abc"},
+	{"unknown method", "DID:Example:MixedCase", "did:example:MixedCase"},
+	{"AT URI uppercase authority", "AT://DID:PLC:ABC/app.bsky.feed.post/3K2", "at://did:plc:abc/app.bsky.feed.post/3K2"},
+	{"AT URI handle kept", "at://Alice.Bsky.Social/app.bsky.feed.post/1", "at://Alice.Bsky.Social/app.bsky.feed.post/1"},
+	{"AT URI trailing slash kept", "at://did:plc:abc/app.bsky.feed.post/3k2/", "at://did:plc:abc/app.bsky.feed.post/3k2/"},
+	{"URL host case kept", "https://Example.COM/Path", "https://Example.COM/Path"},
+	{"URL default port kept", "https://example.com:443/a", "https://example.com:443/a"},
+	{"IRI kept", "https://example.com/café", "https://example.com/café"},
+	{"URL whitespace", " https://example.com/a ", "https://example.com/a"},
+}
+
+// TestTargetEncoding tests that the server receives targets as records link
+// them, without URL rewriting
+func TestTargetEncoding(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.Query().Get("target")
+		w.Write([]byte(`{"total": 0}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	for _, tc := range queryCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := client.GetLinksCount(constellation.LinksParams{Target: tc.target}); err != nil {
+				t.Fatalf("GetLinksCount failed: %v", err)
+			}
+			if received != tc.want {
+				t.Errorf("Expected the server to receive %q, got %q", tc.want, received)
+			}
+		})
+	}
+}
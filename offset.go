@@ -0,0 +1,167 @@
+package constellation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// supportsOffsetPaging reports whether the client's known capabilities include
+// offset pagination
+func (c *Client) supportsOffsetPaging() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.capabilities != nil && c.capabilities.OffsetPaging
+}
+
+// getAllLinksByOffset fetches the first page to learn the total, then fetches the
+// remaining pages concurrently by offset. Results keep the server's ordering. If
+// MaxDuration elapses, the contiguous prefix of pages fetched so far is returned.
+//
+// When exclusions, client-side filters, or Dedupe may drop records, the pages
+// MaxRecords needs aren't known up front, so pages are fetched in waves of
+// Parallelism until enough records are kept. The first failed page cancels the
+// pages still in flight.
+func (c *Client) getAllLinksByOffset(ctx context.Context, params LinksParams, opts PaginateOptions) ([]LinkRecord, error) {
+	start := time.Now()
+	ctx = WithBudget(ctx, opts.Budget)
+	parent := ctx
+	if opts.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.MaxDuration)
+		defer cancel()
+	}
+
+	if params.Limit <= 0 {
+		params.Limit = DefaultMaxLimit
+	}
+	params = c.applyCapabilities(params)
+
+	first, err := c.GetLinksContext(ctx, params)
+	if err != nil {
+		if ctx.Err() != nil && parent.Err() == nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// The total counts every link, including any filtered from the first page,
+	// so it sizes the crawl even when that page came back empty
+	pageCount := maxOf((first.Total+params.Limit-1)/params.Limit, 1)
+	if opts.MaxPages > 0 && pageCount > opts.MaxPages {
+		pageCount = opts.MaxPages
+	}
+	// MaxRecords only bounds the pages needed when every record fetched is kept
+	dropsRecords := c.excluding() || c.needsClientFilter(params) || opts.Dedupe != nil
+	if opts.MaxRecords > 0 && !dropsRecords {
+		pageCount = minOf(pageCount, (opts.MaxRecords+params.Limit-1)/params.Limit)
+	}
+
+	var progressMu sync.Mutex
+	fetchedPages, fetchedRecords := 1, len(first.LinkingRecords)
+	reportProgress := func(records int) {
+		if opts.OnProgress == nil {
+			return
+		}
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		fetchedPages++
+		fetchedRecords += records
+		opts.OnProgress(newProgress(fetchedRecords, fetchedPages, first.Total, start))
+	}
+	if opts.OnProgress != nil {
+		opts.OnProgress(newProgress(fetchedRecords, fetchedPages, first.Total, start))
+	}
+
+	parallelism := opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+
+	var records []LinkRecord
+	keep := func(page []LinkRecord) error {
+		for _, record := range page {
+			if opts.Dedupe != nil && opts.Dedupe.Seen(recordKey(record)) {
+				continue
+			}
+			if err := spendRecord(ctx); err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
+		return nil
+	}
+	full := func() bool {
+		return opts.MaxRecords > 0 && len(records) >= opts.MaxRecords
+	}
+
+	if err := keep(first.LinkingRecords); err != nil {
+		return records, err
+	}
+	for next := 1; next < pageCount && !full(); {
+		end := pageCount
+		if opts.MaxRecords > 0 && dropsRecords {
+			end = minOf(next+parallelism, pageCount)
+		}
+
+		pages, errs, err := c.fetchOffsetPages(ctx, params, next, end, parallelism, reportProgress)
+		for i, page := range pages {
+			if errs[i] != nil {
+				if ctx.Err() != nil && parent.Err() == nil {
+					return truncateRecords(records, opts.MaxRecords), nil
+				}
+				return records, err
+			}
+			if err := keep(page); err != nil {
+				return records, err
+			}
+		}
+		next = end
+	}
+
+	return truncateRecords(records, opts.MaxRecords), nil
+}
+
+// fetchOffsetPages fetches the pages from index from up to to concurrently,
+// returning each page's records or error and the first error. The first error
+// cancels the pages still in flight, which then fail with context.Canceled.
+func (c *Client) fetchOffsetPages(ctx context.Context, params LinksParams, from, to, parallelism int, reportProgress func(records int)) ([][]LinkRecord, []error, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages := make([][]LinkRecord, to-from)
+	errs := make([]error, len(pages))
+	var errMu sync.Mutex
+	var firstErr error
+	parallelEach(len(pages), parallelism, func(i int) {
+		if ctx.Err() != nil {
+			errs[i] = ctx.Err()
+			return
+		}
+		pageParams := params
+		pageParams.Offset = (from + i) * params.Limit
+		resp, err := c.GetLinksContext(ctx, pageParams)
+		if err != nil {
+			errs[i] = err
+			errMu.Lock()
+			if firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+			errMu.Unlock()
+			return
+		}
+		pages[i] = resp.LinkingRecords
+		reportProgress(len(resp.LinkingRecords))
+	})
+	return pages, errs, firstErr
+}
+
+// truncateRecords cuts records to limit, if positive
+func truncateRecords(records []LinkRecord, limit int) []LinkRecord {
+	if limit > 0 && len(records) > limit {
+		return records[:limit]
+	}
+	return records
+}
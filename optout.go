@@ -0,0 +1,86 @@
+package constellation
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// OptOutList is a set of DIDs whose data should be excluded from results,
+// exports, and analytics
+type OptOutList struct {
+	dids map[string]bool
+}
+
+// ParseOptOutList reads an opt-out list with one DID per line. Blank lines and
+// lines starting with # are ignored.
+func ParseOptOutList(r io.Reader) (*OptOutList, error) {
+	list := &OptOutList{dids: make(map[string]bool)}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		list.dids[line] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read opt-out list: %w", err)
+	}
+
+	return list, nil
+}
+
+// LoadOptOutListFile reads an opt-out list from a file
+func LoadOptOutListFile(path string) (*OptOutList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open opt-out list: %w", err)
+	}
+	defer file.Close()
+
+	return ParseOptOutList(file)
+}
+
+// FetchOptOutList downloads an opt-out list from a URL using the client's HTTP client
+func (c *Client) FetchOptOutList(ctx context.Context, listURL string) (*OptOutList, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", listURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch opt-out list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch opt-out list: %w", &APIError{StatusCode: resp.StatusCode, Status: resp.Status})
+	}
+
+	return ParseOptOutList(resp.Body)
+}
+
+// Contains reports whether did has opted out
+func (l *OptOutList) Contains(did string) bool {
+	return l.dids[did]
+}
+
+// Len returns the number of DIDs in the list
+func (l *OptOutList) Len() int {
+	return len(l.dids)
+}
+
+// UseOptOutList makes the client drop records and DIDs from opted-out accounts in
+// every listing response. Totals reported by the server are left unchanged.
+func (c *Client) UseOptOutList(list *OptOutList) *Client {
+	c.addExclusion(list.Contains)
+	return c
+}
@@ -0,0 +1,38 @@
+package constellation_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestOptOutList tests that opted-out DIDs are removed from results
+func TestOptOutList(t *testing.T) {
+	list, err := constellation.ParseOptOutList(strings.NewReader("# opted out\ndid:plc:user1\n\ndid:plc:user12\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse opt-out list: %v", err)
+	}
+	if list.Len() != 2 {
+		t.Errorf("Expected 2 DIDs, got %d", list.Len())
+	}
+
+	server := newPagedServer(t, 25)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second).UseOptOutList(list)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	records, err := client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get all links: %v", err)
+	}
+	if len(records) != 23 {
+		t.Errorf("Expected 23 records after opt-outs, got %d", len(records))
+	}
+	for _, record := range records {
+		if list.Contains(record.DID) {
+			t.Errorf("Expected opted-out DID %s to be excluded", record.DID)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// errStopPagination is returned by emit callbacks to end pagination early without error
+var errStopPagination = errors.New("stop pagination")
+
+// PaginateOptions controls auto-paginating operations. Zero values mean no limit.
+type PaginateOptions struct {
+	MaxRecords  int           // Stop after this many records
+	MaxPages    int           // Stop after this many pages
+	MaxDuration time.Duration // Stop once this much time has elapsed
+
+	// Parallelism is the number of pages fetched concurrently on instances that
+	// support offset pagination. Defaults to DefaultParallelism.
+	Parallelism int
+
+	// Prefetch is the number of pages fetched ahead in the background while the
+	// caller processes the current page. Zero disables prefetching.
+	Prefetch int
+
+	// OnProgress, if set, is called after each page is processed
+	OnProgress func(Progress)
+
+	// Budget, if set, aborts pagination with a BudgetExceededError when exceeded
+	Budget *Budget
+
+	// Dedupe, if set, drops records or DIDs already returned on an earlier page
+	Dedupe Deduper
+
+	// OnDrift, if set, is called when the server's Total changes between pages,
+	// meaning the result set changed mid-pagination
+	OnDrift func(DriftWarning)
+
+	// Checkpoint, if set, saves the cursor periodically so pagination can resume
+	// after a restart. Offset pagination is not used when checkpointing.
+	Checkpoint *Checkpoint
+
+	// Sort orders records client-side. GetAllLinks sorts the full result; streaming
+	// APIs sort within a window of SortWindow records (default DefaultSortWindow),
+	// which is exact only when records arrive at most that far out of place.
+	// Buffered records aren't covered by Checkpoint until they are emitted.
+	Sort       SortOrder
+	SortWindow int
+}
+
+// DriftWarning reports that the result set changed during pagination, so the
+// collected records aren't a perfectly consistent snapshot
+type DriftWarning struct {
+	Page          int // Page on which the change was observed, starting at 1
+	PreviousTotal int
+	Total         int
+}
+
+// Delta returns the change in Total
+func (w DriftWarning) Delta() int {
+	return w.Total - w.PreviousTotal
+}
+
+// Progress reports the state of a running pagination
+type Progress struct {
+	Records   int           // Records processed so far
+	Pages     int           // Pages fetched so far
+	Total     int           // Total reported by the server; zero if unknown
+	Elapsed   time.Duration // Time since pagination started
+	Remaining time.Duration // Estimated time to finish based on Total; zero if unknown
+}
+
+// newProgress builds a Progress, estimating the remaining time from the rate so far
+func newProgress(records, pages, total int, start time.Time) Progress {
+	progress := Progress{
+		Records: records,
+		Pages:   pages,
+		Total:   total,
+		Elapsed: time.Since(start),
+	}
+	if records > 0 && total > records {
+		progress.Remaining = time.Duration(float64(progress.Elapsed) * float64(total-records) / float64(records))
+	}
+	return progress
+}
+
+// DefaultParallelism is the default number of concurrent page fetches for offset pagination
+const DefaultParallelism = 4
+
+// page is a single page of results from a paginated endpoint
+type page[T any] struct {
+	items  []T
+	cursor string
+	total  int
+}
+
+// pageFetcher fetches the page starting at cursor
+type pageFetcher[T any] func(ctx context.Context, cursor string) (page[T], error)
+
+// paginate follows cursors from the first page until the results are exhausted or
+// a limit in opts is reached, calling emit for each item. Limits end pagination
+// without error; an emit error stops pagination and is returned, except for
+// errStopPagination.
+func paginate[T any](ctx context.Context, opts PaginateOptions, cursor string, fetch pageFetcher[T], emit func(T) error) error {
+	start := time.Now()
+	ctx = WithBudget(ctx, opts.Budget)
+	parent := ctx
+	if opts.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.MaxDuration)
+		defer cancel()
+	}
+
+	cursor, err := opts.Checkpoint.resume(ctx, cursor)
+	if err != nil {
+		return err
+	}
+
+	if opts.Prefetch > 0 {
+		var stop context.CancelFunc
+		fetch, stop = prefetch(ctx, cursor, fetch, opts.Prefetch, opts.MaxPages)
+		defer stop()
+	}
+
+	records, pages, lastTotal := 0, 0, -1
+	seen := make(map[string]bool)
+	for {
+		if opts.MaxPages > 0 && pages >= opts.MaxPages {
+			return nil
+		}
+
+		p, err := fetch(ctx, cursor)
+		if err != nil {
+			// Running out of time is a limit, not a failure
+			if ctx.Err() != nil && parent.Err() == nil && !errors.Is(err, ErrBudgetExceeded) {
+				return nil
+			}
+			return err
+		}
+		pages++
+
+		if lastTotal >= 0 && p.total != lastTotal && opts.OnDrift != nil {
+			opts.OnDrift(DriftWarning{Page: pages, PreviousTotal: lastTotal, Total: p.total})
+		}
+		lastTotal = p.total
+
+		for _, item := range p.items {
+			if opts.Dedupe != nil && opts.Dedupe.Seen(dedupeKey(item)) {
+				continue
+			}
+			if opts.MaxRecords > 0 && records >= opts.MaxRecords {
+				return nil
+			}
+			if err := spendRecord(ctx); err != nil {
+				return err
+			}
+			if err := emit(item); err != nil {
+				if errors.Is(err, errStopPagination) {
+					return nil
+				}
+				return err
+			}
+			records++
+		}
+
+		if opts.OnProgress != nil {
+			opts.OnProgress(newProgress(records, pages, p.total, start))
+		}
+
+		// Stop on exhaustion, and guard against servers repeating a cursor. Pages
+		// may be empty after client-side filtering, so emptiness alone isn't the end.
+		done := p.cursor == "" || seen[p.cursor]
+		next := p.cursor
+		if done {
+			next = ""
+		}
+		if err := opts.Checkpoint.save(ctx, pages, next); err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		seen[p.cursor] = true
+		cursor = p.cursor
+	}
+}
+
+// fetchResult is a page or error delivered by a prefetching goroutine
+type fetchResult[T any] struct {
+	page page[T]
+	err  error
+}
+
+// prefetch starts a goroutine that follows cursors from cursor, fetching up to
+// buffer pages ahead, and returns a fetcher that hands out those pages in order
+// (ignoring its cursor argument) along with a function that stops the goroutine.
+// maxPages, if positive, bounds the number of pages fetched.
+func prefetch[T any](ctx context.Context, cursor string, fetch pageFetcher[T], buffer, maxPages int) (pageFetcher[T], context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	results := make(chan fetchResult[T], buffer)
+
+	go func() {
+		defer close(results)
+
+		seen := make(map[string]bool)
+		for pages := 0; maxPages <= 0 || pages < maxPages; pages++ {
+			p, err := fetch(ctx, cursor)
+			select {
+			case results <- fetchResult[T]{page: p, err: err}:
+			case <-ctx.Done():
+				return
+			}
+
+			if err != nil || p.cursor == "" || seen[p.cursor] {
+				return
+			}
+			seen[p.cursor] = true
+			cursor = p.cursor
+		}
+	}()
+
+	next := func(ctx context.Context, _ string) (page[T], error) {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				return page[T]{}, context.Canceled
+			}
+			return result.page, result.err
+		case <-ctx.Done():
+			return page[T]{}, ctx.Err()
+		}
+	}
+	return next, cancel
+}
+
+// linksFetcher returns a pageFetcher over /links for params
+func (c *Client) linksFetcher(params LinksParams) pageFetcher[LinkRecord] {
+	if params.Limit <= 0 {
+		params.Limit = DefaultMaxLimit
+	}
+	return func(ctx context.Context, cursor string) (page[LinkRecord], error) {
+		params.Cursor = cursor
+		resp, err := c.GetLinksContext(ctx, params)
+		if err != nil {
+			return page[LinkRecord]{}, err
+		}
+		return page[LinkRecord]{items: resp.LinkingRecords, cursor: resp.Cursor, total: resp.Total}, nil
+	}
+}
+
+// distinctDIDsFetcher returns a pageFetcher over /links/distinct-dids for params
+func (c *Client) distinctDIDsFetcher(params LinksParams) pageFetcher[string] {
+	if params.Limit <= 0 {
+		params.Limit = DefaultMaxLimit
+	}
+	return func(ctx context.Context, cursor string) (page[string], error) {
+		params.Cursor = cursor
+		resp, err := c.GetDistinctDIDsContext(ctx, params)
+		if err != nil {
+			return page[string]{}, err
+		}
+		return page[string]{items: resp.DIDs, cursor: resp.Cursor, total: resp.Total}, nil
+	}
+}
+
+// GetAllLinks retrieves every record linking to a target by following cursors
+// until the results are exhausted or a limit in opts is reached. Pagination starts
+// at params.Cursor, and params.Limit is used as the page size (defaulting to
+// DefaultMaxLimit). Requests honor the client's concurrency limit.
+//
+// When the client's capabilities show the instance supports offset pagination,
+// remaining pages are fetched in parallel by offset instead of following cursors.
+func (c *Client) GetAllLinks(ctx context.Context, params LinksParams, opts PaginateOptions) ([]LinkRecord, error) {
+	if c.supportsOffsetPaging() && params.Cursor == "" && opts.Checkpoint == nil {
+		records, err := c.getAllLinksByOffset(ctx, params, opts)
+		SortRecords(records, opts.Sort)
+		return records, err
+	}
+
+	var records []LinkRecord
+	err := paginate(ctx, opts, params.Cursor, c.linksFetcher(params), func(record LinkRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	SortRecords(records, opts.Sort)
+	return records, err
+}
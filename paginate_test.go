@@ -0,0 +1,278 @@
+package constellation_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// newPagedServer serves total link records in pages of the requested limit,
+// using the record offset as the cursor and also accepting an offset parameter
+func newPagedServer(t *testing.T, total int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		offset, _ := strconv.Atoi(query.Get("cursor"))
+		if query.Has("offset") {
+			offset, _ = strconv.Atoi(query.Get("offset"))
+		}
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		if limit <= 0 {
+			limit = 16
+		}
+
+		end := offset + limit
+		if end > total {
+			end = total
+		}
+		fmt.Fprintf(w, `{"total": %d, "linking_records": [`, total)
+		for i := offset; i < end; i++ {
+			if i > offset {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"did": "did:plc:user%d", "collection": "app.bsky.feed.like", "rkey": "rkey%d"}`, i, i)
+		}
+		fmt.Fprint(w, `]`)
+		if end < total {
+			fmt.Fprintf(w, `, "cursor": "%d"`, end)
+		}
+		fmt.Fprint(w, `}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newPagedDIDServer serves total distinct DIDs in pages of the requested limit,
+// using the DID offset as the cursor
+func newPagedDIDServer(t *testing.T, total int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		offset, _ := strconv.Atoi(query.Get("cursor"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		if limit <= 0 {
+			limit = 16
+		}
+
+		end := offset + limit
+		if end > total {
+			end = total
+		}
+		fmt.Fprintf(w, `{"total": %d, "linking_dids": [`, total)
+		for i := offset; i < end; i++ {
+			if i > offset {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `"did:plc:user%d"`, i)
+		}
+		fmt.Fprint(w, `]`)
+		if end < total {
+			fmt.Fprintf(w, `, "cursor": "%d"`, end)
+		}
+		fmt.Fprint(w, `}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestGetAllLinks tests cursor following and stop conditions
+func TestGetAllLinks(t *testing.T) {
+	server := newPagedServer(t, 25)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	records, err := client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get all links: %v", err)
+	}
+	if len(records) != 25 {
+		t.Errorf("Expected 25 records, got %d", len(records))
+	}
+
+	records, err = client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{MaxRecords: 12})
+	if err != nil {
+		t.Fatalf("Failed to get all links: %v", err)
+	}
+	if len(records) != 12 {
+		t.Errorf("Expected 12 records with MaxRecords, got %d", len(records))
+	}
+
+	records, err = client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{MaxPages: 2})
+	if err != nil {
+		t.Fatalf("Failed to get all links: %v", err)
+	}
+	if len(records) != 20 {
+		t.Errorf("Expected 20 records with MaxPages, got %d", len(records))
+	}
+}
+
+// TestGetAllLinksByOffset tests parallel offset pagination on capable instances
+func TestGetAllLinksByOffset(t *testing.T) {
+	server := newPagedServer(t, 95)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.UseCapabilities(&constellation.Capabilities{MaxLimit: 100, OffsetPaging: true})
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	records, err := client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{Parallelism: 3})
+	if err != nil {
+		t.Fatalf("Failed to get all links: %v", err)
+	}
+	if len(records) != 95 {
+		t.Fatalf("Expected 95 records, got %d", len(records))
+	}
+	for i, record := range records {
+		if expected := fmt.Sprintf("did:plc:user%d", i); record.DID != expected {
+			t.Fatalf("Expected record %d to be from %s, got %s", i, expected, record.DID)
+		}
+	}
+
+	records, err = client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{MaxRecords: 25})
+	if err != nil {
+		t.Fatalf("Failed to get all links: %v", err)
+	}
+	if len(records) != 25 {
+		t.Errorf("Expected 25 records with MaxRecords, got %d", len(records))
+	}
+}
+
+// TestGetAllLinksByOffsetWaves tests that MaxRecords bounds an offset crawl
+// even when exclusions may drop records
+func TestGetAllLinksByOffsetWaves(t *testing.T) {
+	server := newPagedServer(t, 100000)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.UseCapabilities(&constellation.Capabilities{MaxLimit: 100, OffsetPaging: true})
+	client.ExcludeDIDs(constellation.NewDIDSet("did:plc:user3"))
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	records, err := client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{MaxRecords: 25, Parallelism: 2})
+	if err != nil {
+		t.Fatalf("Failed to get all links: %v", err)
+	}
+	if len(records) != 25 || records[3].DID != "did:plc:user4" {
+		t.Errorf("Expected the first 25 kept records, got %d", len(records))
+	}
+	if n := client.Metrics().Requests; n > 5 {
+		t.Errorf("Expected the crawl to stop after a few pages, made %d requests", n)
+	}
+}
+
+// TestGetAllLinksByOffsetCancelsOnError tests that a failed page cancels the
+// pages still in flight
+func TestGetAllLinksByOffsetCancelsOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("offset") {
+		case "":
+			w.Write([]byte(`{"total": 100, "linking_records": [], "cursor": "10"}`))
+		case "10":
+			http.Error(w, "bad page", http.StatusBadRequest)
+		default:
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			w.Write([]byte(`{"total": 100, "linking_records": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 10*time.Second)
+	client.UseCapabilities(&constellation.Capabilities{MaxLimit: 100, OffsetPaging: true})
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	start := time.Now()
+	_, err := client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{Parallelism: 4})
+	var apiErr *constellation.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected the failed page's error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the other pages to be cancelled, took %s", elapsed)
+	}
+}
+
+// TestGetAllLinksPrefetch tests that prefetching yields the same records in order
+func TestGetAllLinksPrefetch(t *testing.T) {
+	server := newPagedServer(t, 55)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	records, err := client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{Prefetch: 2})
+	if err != nil {
+		t.Fatalf("Failed to get all links: %v", err)
+	}
+	if len(records) != 55 {
+		t.Fatalf("Expected 55 records, got %d", len(records))
+	}
+	for i, record := range records {
+		if expected := fmt.Sprintf("did:plc:user%d", i); record.DID != expected {
+			t.Fatalf("Expected record %d to be from %s, got %s", i, expected, record.DID)
+		}
+	}
+
+	records, err = client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{Prefetch: 2, MaxPages: 2})
+	if err != nil {
+		t.Fatalf("Failed to get all links: %v", err)
+	}
+	if len(records) != 20 {
+		t.Errorf("Expected 20 records with MaxPages, got %d", len(records))
+	}
+}
+
+// TestGetAllLinksProgress tests progress reporting after each page
+func TestGetAllLinksProgress(t *testing.T) {
+	server := newPagedServer(t, 25)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	var reports []constellation.Progress
+	opts := constellation.PaginateOptions{
+		OnProgress: func(p constellation.Progress) { reports = append(reports, p) },
+	}
+	if _, err := client.GetAllLinks(context.Background(), params, opts); err != nil {
+		t.Fatalf("Failed to get all links: %v", err)
+	}
+
+	if len(reports) != 3 {
+		t.Fatalf("Expected 3 progress reports, got %d", len(reports))
+	}
+	last := reports[len(reports)-1]
+	if last.Records != 25 || last.Pages != 3 || last.Total != 25 {
+		t.Errorf("Unexpected final progress: %+v", last)
+	}
+	if last.Remaining != 0 {
+		t.Errorf("Expected no remaining time when finished, got %s", last.Remaining)
+	}
+}
+
+// TestGetAllLinksByOffsetFilteredFirstPage tests that offset paging continues
+// past a first page whose records were all filtered out
+func TestGetAllLinksByOffsetFilteredFirstPage(t *testing.T) {
+	server := newPagedServer(t, 35)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.UseCapabilities(&constellation.Capabilities{MaxLimit: 100, OffsetPaging: true})
+	client.ExcludeDIDs(constellation.NewDIDSet(
+		"did:plc:user0", "did:plc:user1", "did:plc:user2", "did:plc:user3", "did:plc:user4",
+		"did:plc:user5", "did:plc:user6", "did:plc:user7", "did:plc:user8", "did:plc:user9",
+	))
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	records, err := client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get all links: %v", err)
+	}
+	if len(records) != 25 || records[0].DID != "did:plc:user10" {
+		t.Errorf("Expected the 25 records after the filtered first page, got %d", len(records))
+	}
+
+	records, err = client.GetAllLinks(context.Background(), params, constellation.PaginateOptions{MaxRecords: 15})
+	if err != nil || len(records) != 15 {
+		t.Errorf("Expected MaxRecords to count kept records, got %d, %v", len(records), err)
+	}
+}
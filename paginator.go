@@ -0,0 +1,107 @@
+package constellation
+
+import (
+	"context"
+)
+
+// Paginator walks the pages of a paginated endpoint, keeping the cursor state so
+// that callers don't read the Cursor field themselves:
+//
+//	p := client.LinksPaginator(params)
+//	for p.Next(ctx) {
+//		for _, record := range p.Items() {
+//			fmt.Println(record.URI)
+//		}
+//	}
+//	if err := p.Err(); err != nil {
+//		return err
+//	}
+type Paginator[T any] struct {
+	fetch  pageFetcher[T]
+	cursor string
+	items  []T
+	total  int
+	done   bool
+	err    error
+	seen   map[string]bool
+	pages  int
+	drift  []DriftWarning
+}
+
+// newPaginator creates a paginator starting at cursor
+func newPaginator[T any](cursor string, fetch pageFetcher[T]) *Paginator[T] {
+	return &Paginator[T]{fetch: fetch, cursor: cursor, seen: make(map[string]bool)}
+}
+
+// LinksPaginator returns a paginator over records linking to a target.
+// params.Limit sets the page size, defaulting to DefaultMaxLimit.
+func (c *Client) LinksPaginator(params LinksParams) *Paginator[LinkRecord] {
+	return newPaginator(params.Cursor, c.linksFetcher(params))
+}
+
+// DistinctDIDsPaginator returns a paginator over distinct DIDs linking to a target.
+// params.Limit sets the page size, defaulting to DefaultMaxLimit.
+func (c *Client) DistinctDIDsPaginator(params LinksParams) *Paginator[string] {
+	return newPaginator(params.Cursor, c.distinctDIDsFetcher(params))
+}
+
+// HasNext reports whether another page may be available
+func (p *Paginator[T]) HasNext() bool {
+	return !p.done && p.err == nil
+}
+
+// Next fetches the next page, returning false when there are no more pages or a
+// request fails. Check Err after Next returns false.
+func (p *Paginator[T]) Next(ctx context.Context) bool {
+	if !p.HasNext() {
+		return false
+	}
+
+	result, err := p.fetch(ctx, p.cursor)
+	if err != nil {
+		p.err = err
+		p.items = nil
+		return false
+	}
+
+	p.pages++
+	if p.pages > 1 && result.total != p.total {
+		p.drift = append(p.drift, DriftWarning{Page: p.pages, PreviousTotal: p.total, Total: result.total})
+	}
+	p.items = result.items
+	p.total = result.total
+
+	// Guard against servers repeating a cursor, as paginate does
+	if result.cursor == "" || p.seen[result.cursor] {
+		p.done = true
+	}
+	p.seen[result.cursor] = true
+	p.cursor = result.cursor
+
+	return true
+}
+
+// Items returns the items of the current page
+func (p *Paginator[T]) Items() []T {
+	return p.items
+}
+
+// Err returns the error that stopped pagination, if any
+func (p *Paginator[T]) Err() error {
+	return p.err
+}
+
+// Cursor returns the cursor for the page after the current one
+func (p *Paginator[T]) Cursor() string {
+	return p.cursor
+}
+
+// Total returns the total reported by the most recent page
+func (p *Paginator[T]) Total() int {
+	return p.total
+}
+
+// Drift returns the changes in Total observed between pages so far
+func (p *Paginator[T]) Drift() []DriftWarning {
+	return p.drift
+}
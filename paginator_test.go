@@ -0,0 +1,50 @@
+package constellation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestPaginator tests page-by-page iteration with cursor state
+func TestPaginator(t *testing.T) {
+	server := newPagedServer(t, 25)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	p := client.LinksPaginator(constellation.LinksParams{Target: "did:plc:example", Limit: 10})
+
+	var sizes []int
+	for p.Next(context.Background()) {
+		sizes = append(sizes, len(p.Items()))
+		if p.Total() != 25 {
+			t.Errorf("Expected total 25, got %d", p.Total())
+		}
+	}
+	if err := p.Err(); err != nil {
+		t.Fatalf("Pagination failed: %v", err)
+	}
+
+	if len(sizes) != 3 || sizes[0] != 10 || sizes[2] != 5 {
+		t.Errorf("Expected page sizes [10 10 5], got %v", sizes)
+	}
+	if p.HasNext() {
+		t.Error("Expected no further pages")
+	}
+}
+
+// TestPaginatorError tests that request errors stop pagination
+func TestPaginatorError(t *testing.T) {
+	client := constellation.NewClientWithConfig("http://127.0.0.1:0", time.Second)
+	p := client.DistinctDIDsPaginator(constellation.LinksParams{Target: "did:plc:example"})
+
+	if p.Next(context.Background()) {
+		t.Fatal("Expected Next to fail against an unreachable server")
+	}
+	if p.Err() == nil {
+		t.Error("Expected an error")
+	}
+	if p.HasNext() {
+		t.Error("Expected HasNext to be false after an error")
+	}
+}
@@ -0,0 +1,57 @@
+package constellation
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultDegradedLatency is the round-trip latency above which Ping reports StatusDegraded
+const DefaultDegradedLatency = 2 * time.Second
+
+// HealthStatus classifies the availability of a Constellation instance
+type HealthStatus string
+
+const (
+	StatusUp       HealthStatus = "up"
+	StatusDegraded HealthStatus = "degraded"
+	StatusDown     HealthStatus = "down"
+)
+
+// PingResult reports the outcome of a health check
+type PingResult struct {
+	Status      HealthStatus
+	Latency     time.Duration // Round-trip time of the health check request
+	DaysIndexed int           // Days of history indexed; zero if the instance is down
+	IndexLag    time.Duration // Indexing delay, if the instance reports it
+	Err         error         // Cause of a StatusDown result
+}
+
+// Ping checks the availability of the Constellation instance by requesting the root
+// endpoint and measuring round-trip latency. A failed request yields StatusDown with
+// the cause in Err rather than an error return, so the result can be used directly
+// in readiness probes.
+func (c *Client) Ping(ctx context.Context) *PingResult {
+	start := time.Now()
+	info, err := c.getAPIInfo(ctx)
+	result := &PingResult{Latency: time.Since(start)}
+
+	if err != nil {
+		result.Status = StatusDown
+		result.Err = err
+		return result
+	}
+
+	result.Status = StatusUp
+	if result.Latency > DefaultDegradedLatency {
+		result.Status = StatusDegraded
+	}
+	result.DaysIndexed = info.DaysIndexed
+	result.IndexLag = time.Duration(info.IndexLagSeconds * float64(time.Second))
+
+	return result
+}
+
+// Healthy reports whether the instance is up or degraded but responding
+func (r *PingResult) Healthy() bool {
+	return r.Status != StatusDown
+}
@@ -0,0 +1,36 @@
+package constellation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestPing tests health check classification
+func TestPing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"days_indexed": 42}`))
+	}))
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	result := client.Ping(context.Background())
+	if result.Status != constellation.StatusUp {
+		t.Errorf("Expected status up, got %s (%v)", result.Status, result.Err)
+	}
+	if result.DaysIndexed != 42 {
+		t.Errorf("Expected 42 days indexed, got %d", result.DaysIndexed)
+	}
+
+	server.Close()
+	result = client.Ping(context.Background())
+	if result.Status != constellation.StatusDown || result.Healthy() {
+		t.Errorf("Expected status down after server close, got %s", result.Status)
+	}
+	if result.Err == nil {
+		t.Error("Expected error for down instance")
+	}
+}
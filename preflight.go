@@ -0,0 +1,132 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrUnsupportedEndpoint is returned when preflight detection shows that the
+// configured instance doesn't provide an endpoint
+var ErrUnsupportedEndpoint = errors.New("endpoint not supported by this instance")
+
+// probeTarget is a syntactically valid target used when probing endpoints
+const probeTarget = "at://did:plc:aaaaaaaaaaaaaaaaaaaaaaaa/app.bsky.feed.post/aaaaaaaaaaaaa"
+
+// knownEndpoints lists the endpoints used by this client, probed during detection
+var knownEndpoints = []string{
+	"/links",
+	"/links/count",
+	"/links/distinct-dids",
+	"/links/count/distinct-dids",
+	"/links/all/count",
+}
+
+// DetectCapabilities probes the instance for its capabilities and supported
+// endpoints. Endpoints advertised by the root response are used as-is; otherwise
+// each known endpoint is probed and those answering 404 are marked unsupported.
+// The result is cached on the client, and on disk with UseCapabilitiesCache, and
+// applied as with UseCapabilities.
+func (c *Client) DetectCapabilities(ctx context.Context) (*Capabilities, error) {
+	c.mu.Lock()
+	if c.detected {
+		caps := c.capabilities
+		c.mu.Unlock()
+		return caps, nil
+	}
+	cache := c.capabilitiesCache
+	c.mu.Unlock()
+
+	if cache != nil {
+		if caps, ok := cache.Load(c.BaseURL); ok {
+			c.mu.Lock()
+			c.capabilities = caps
+			c.detected = true
+			c.mu.Unlock()
+			return caps, nil
+		}
+	}
+
+	info, err := c.getAPIInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	caps := capabilitiesFromInfo(info)
+
+	if caps.Endpoints == nil {
+		caps.Endpoints = []string{}
+		for _, endpoint := range knownEndpoints {
+			supported, err := c.probeEndpoint(ctx, endpoint)
+			if err != nil {
+				return nil, fmt.Errorf("failed to probe %s: %w", endpoint, err)
+			}
+			if supported {
+				caps.Endpoints = append(caps.Endpoints, endpoint)
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.capabilities = caps
+	c.detected = true
+	c.mu.Unlock()
+
+	if cache != nil {
+		if err := cache.Save(c.BaseURL, caps); err != nil {
+			c.warn("failed to cache capabilities", "error", err)
+		}
+	}
+	return caps, nil
+}
+
+// EnablePreflight makes the client detect capabilities before its first request
+// (once, then cached) and fail requests to unsupported endpoints with
+// ErrUnsupportedEndpoint instead of surfacing 404 errors
+func (c *Client) EnablePreflight() *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.preflight = true
+	return c
+}
+
+// checkSupported returns ErrUnsupportedEndpoint if preflight is enabled and the
+// instance lacks the endpoint
+func (c *Client) checkSupported(ctx context.Context, endpoint string) error {
+	c.mu.Lock()
+	preflight := c.preflight
+	c.mu.Unlock()
+
+	if !preflight || endpoint == "/" {
+		return nil
+	}
+
+	caps, err := c.DetectCapabilities(ctx)
+	if err != nil {
+		return err
+	}
+	if !caps.SupportsEndpoint(endpoint) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedEndpoint, endpoint)
+	}
+	return nil
+}
+
+// probeEndpoint reports whether the instance serves endpoint
+func (c *Client) probeEndpoint(ctx context.Context, endpoint string) (bool, error) {
+	params := url.Values{}
+	params.Add("target", probeTarget)
+
+	resp, err := c.doRequest(ctx, endpoint, params)
+	if err == nil {
+		resp.Body.Close()
+		return true, nil
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode != http.StatusNotFound, nil
+	}
+	return false, err
+}
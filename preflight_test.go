@@ -0,0 +1,48 @@
+package constellation_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestPreflight tests that unsupported endpoints fail early after detection
+func TestPreflight(t *testing.T) {
+	rootCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			rootCalls++
+			w.Write([]byte(`{"days_indexed": 1}`))
+		case "/links", "/links/count":
+			w.Write([]byte(`{"total": 3}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second).EnablePreflight()
+	params := constellation.LinksParams{Target: "did:plc:example"}
+
+	if _, err := client.GetLinksCount(params); err != nil {
+		t.Fatalf("Expected supported endpoint to succeed, got: %v", err)
+	}
+
+	_, err := client.GetDistinctDIDsCount(params)
+	if !errors.Is(err, constellation.ErrUnsupportedEndpoint) {
+		t.Errorf("Expected ErrUnsupportedEndpoint, got: %v", err)
+	}
+
+	if _, err := client.DetectCapabilities(context.Background()); err != nil {
+		t.Fatalf("Failed to detect capabilities: %v", err)
+	}
+	if rootCalls != 1 {
+		t.Errorf("Expected capabilities to be detected once, got %d root calls", rootCalls)
+	}
+}
@@ -0,0 +1,208 @@
+package constellation
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownPreset is returned when no preset is registered under a name
+var ErrUnknownPreset = errors.New("unknown preset")
+
+// Preset names a well-known kind of link: the collection linking to a kind of
+// target and the path of the link within its records
+type Preset struct {
+	Name        string     // Unique name, conventionally "<lexicon namespace>.<noun>", e.g. "bsky.likes"
+	Collection  string     // Linking collection
+	Path        string     // Path of the link within records
+	TargetKind  TargetKind // Kind of target linked to
+	Description string
+}
+
+// ownsCollection reports whether the preset's name namespace is part of its
+// collection's NSID, e.g. bsky for app.bsky.feed.like. Names without a namespace
+// are assumed to describe their own collection.
+func (p Preset) ownsCollection() bool {
+	namespace, _, ok := strings.Cut(p.Name, ".")
+	if !ok {
+		return true
+	}
+	for _, segment := range strings.Split(p.Collection, ".") {
+		if segment == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Params returns the query for this preset's links to target
+func (p Preset) Params(target string) LinksParams {
+	return LinksParams{Target: target, Collection: p.Collection, Path: p.Path}
+}
+
+// BlueskyPresets are the built-in presets for the Bluesky app lexicons
+var BlueskyPresets = []Preset{
+	{"bsky.likes", CollectionLike, PathSubjectURI, TargetURI, "Likes of a post, feed, or labeler"},
+	{"bsky.reposts", CollectionRepost, PathSubjectURI, TargetURI, "Reposts of a post"},
+	{"bsky.replies", CollectionPost, PathReplyParentURI, TargetURI, "Direct replies to a post"},
+	{"bsky.thread-replies", CollectionPost, PathReplyRootURI, TargetURI, "Replies at any depth in a thread"},
+	{"bsky.quotes", CollectionPost, PathEmbedRecordURI, TargetURI, "Quotes of a post"},
+	{"bsky.quotes-with-media", CollectionPost, PathEmbedMediaURI, TargetURI, "Quotes of a post with attached media"},
+	{"bsky.threadgates", CollectionThreadgate, PathPost, TargetURI, "Reply restrictions on a post"},
+	{"bsky.postgates", CollectionPostgate, PathPost, TargetURI, "Embedding restrictions on a post"},
+	{"bsky.follows", CollectionFollow, PathSubject, TargetDID, "Follows of an account"},
+	{"bsky.blocks", CollectionBlock, PathSubject, TargetDID, "Blocks of an account"},
+	{"bsky.list-memberships", CollectionListItem, PathSubject, TargetDID, "List items adding an account to a list"},
+	{"bsky.list-items", CollectionListItem, PathList, TargetURI, "Items of a list"},
+	{"bsky.list-blocks", CollectionListBlock, PathSubject, TargetURI, "Subscriptions blocking a moderation list"},
+	{"bsky.starter-packs", CollectionStarterPack, PathList, TargetURI, "Starter packs built on a list"},
+}
+
+// WhiteWindPresets are presets for WhiteWind blog entries (com.whtwnd.blog.entry).
+// WhiteWind has no like or comment records of its own; entries are liked with
+// Bluesky likes and discussed in Bluesky posts linking to the entry's web page.
+var WhiteWindPresets = []Preset{
+	{"whtwnd.likes", CollectionLike, PathSubjectURI, TargetURI, "Likes of a WhiteWind entry, by its AT URI"},
+	{"whtwnd.comments", CollectionPost, PathEmbedExternalURI, TargetURL, "Bluesky posts with a link card to a WhiteWind entry, by its web URL"},
+}
+
+// FrontpagePresets are presets for Frontpage (fyi.unravel.frontpage) link
+// submissions, comments, and votes
+var FrontpagePresets = []Preset{
+	{"frontpage.submissions", "fyi.unravel.frontpage.post", ".url", TargetURL, "Frontpage submissions of a link"},
+	{"frontpage.comments", "fyi.unravel.frontpage.comment", ".post.uri", TargetURI, "Comments on a submission"},
+	{"frontpage.replies", "fyi.unravel.frontpage.comment", ".parent.uri", TargetURI, "Replies to a comment"},
+	{"frontpage.votes", "fyi.unravel.frontpage.vote", PathSubjectURI, TargetURI, "Votes on a submission or comment"},
+}
+
+// SmokeSignalPresets are presets for Smoke Signal event RSVPs, under both the
+// original and the community calendar lexicons
+var SmokeSignalPresets = []Preset{
+	{"smokesignal.rsvps", "events.smokesignal.calendar.rsvp", PathSubjectURI, TargetURI, "RSVPs to an event"},
+	{"calendar.rsvps", "community.lexicon.calendar.rsvp", PathSubjectURI, TargetURI, "RSVPs to a community calendar event"},
+}
+
+// Registry holds presets by name. It's safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	presets map[string]Preset
+}
+
+// NewRegistry creates a registry holding presets, which may be empty
+func NewRegistry(presets ...Preset) (*Registry, error) {
+	r := &Registry{presets: make(map[string]Preset)}
+	if err := r.Register(presets...); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// DefaultRegistry holds the built-in packs and any presets registered by
+// applications. DefaultPathFor consults it to infer paths.
+var DefaultRegistry = mustRegistry(builtinPresets()...)
+
+// builtinPresets returns every built-in pack
+func builtinPresets() []Preset {
+	var presets []Preset
+	for _, pack := range [][]Preset{BlueskyPresets, WhiteWindPresets, FrontpagePresets, SmokeSignalPresets} {
+		presets = append(presets, pack...)
+	}
+	return presets
+}
+
+// mustRegistry creates a registry, panicking on invalid built-in presets
+func mustRegistry(presets ...Preset) *Registry {
+	r, err := NewRegistry(presets...)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Register adds presets, such as a third-party lexicon pack. It fails without
+// registering anything if a preset is missing its name, collection, or path, or
+// its name is already taken.
+func (r *Registry) Register(presets ...Preset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make(map[string]bool)
+	for _, preset := range presets {
+		if preset.Name == "" || preset.Collection == "" || preset.Path == "" {
+			return fmt.Errorf("invalid preset %q: name, collection, and path are required", preset.Name)
+		}
+		if _, taken := r.presets[preset.Name]; taken || names[preset.Name] {
+			return fmt.Errorf("preset %q already registered", preset.Name)
+		}
+		names[preset.Name] = true
+	}
+	for _, preset := range presets {
+		r.presets[preset.Name] = preset
+	}
+	return nil
+}
+
+// Lookup returns the preset registered under name
+func (r *Registry) Lookup(name string) (Preset, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	preset, ok := r.presets[name]
+	return preset, ok
+}
+
+// Params returns the query for the named preset's links to target
+func (r *Registry) Params(name, target string) (LinksParams, error) {
+	preset, ok := r.Lookup(name)
+	if !ok {
+		return LinksParams{}, fmt.Errorf("%w: %s", ErrUnknownPreset, name)
+	}
+	return preset.Params(target), nil
+}
+
+// Presets returns every registered preset, ordered by name
+func (r *Registry) Presets() []Preset {
+	r.mu.RLock()
+	presets := make([]Preset, 0, len(r.presets))
+	for _, preset := range r.presets {
+		presets = append(presets, preset)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+	return presets
+}
+
+// ForCollection returns the presets for collection, ordered by name
+func (r *Registry) ForCollection(collection string) []Preset {
+	var presets []Preset
+	for _, preset := range r.Presets() {
+		if preset.Collection == collection {
+			presets = append(presets, preset)
+		}
+	}
+	return presets
+}
+
+// pathFor returns the path collection links a kind of target from, if the
+// registered presets agree on exactly one. Presets describing how one app uses
+// another's collection, such as whtwnd.comments on Bluesky posts, cover only
+// some of the collection's links of that kind and are left out.
+func (r *Registry) pathFor(collection string, kind TargetKind) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	path := ""
+	for _, preset := range r.presets {
+		if preset.Collection != collection || preset.TargetKind != kind || !preset.ownsCollection() {
+			continue
+		}
+		if path != "" && path != preset.Path {
+			return "", false
+		}
+		path = preset.Path
+	}
+	return path, path != ""
+}
@@ -0,0 +1,104 @@
+package constellation_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestRegistry tests registering and querying presets
+func TestRegistry(t *testing.T) {
+	registry, err := constellation.NewRegistry(constellation.BlueskyPresets...)
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+
+	params, err := registry.Params("bsky.likes", "at://did:plc:a/app.bsky.feed.post/1")
+	if err != nil {
+		t.Fatalf("Failed to get params: %v", err)
+	}
+	if params.Collection != constellation.CollectionLike || params.Path != constellation.PathSubjectURI {
+		t.Errorf("Unexpected params %+v", params)
+	}
+	if _, err := registry.Params("bsky.nope", "did:plc:a"); !errors.Is(err, constellation.ErrUnknownPreset) {
+		t.Errorf("Expected ErrUnknownPreset, got %v", err)
+	}
+
+	pack := []constellation.Preset{{Name: "example.votes", Collection: "com.example.vote", Path: ".subject", TargetKind: constellation.TargetURI}}
+	if err := registry.Register(pack...); err != nil {
+		t.Fatalf("Failed to register pack: %v", err)
+	}
+	if got := registry.ForCollection("com.example.vote"); len(got) != 1 || got[0].Name != "example.votes" {
+		t.Errorf("Expected the registered preset, got %v", got)
+	}
+	if got := registry.ForCollection(constellation.CollectionPost); len(got) != 4 {
+		t.Errorf("Expected 4 post presets, got %d", len(got))
+	}
+}
+
+// TestRegistryRejectsInvalid tests that invalid packs register nothing
+func TestRegistryRejectsInvalid(t *testing.T) {
+	registry, _ := constellation.NewRegistry()
+
+	tests := [][]constellation.Preset{
+		{{Name: "a", Collection: "com.example.a"}},
+		{{Name: "a", Collection: "com.example.a", Path: ".x"}, {Name: "a", Collection: "com.example.b", Path: ".y"}},
+	}
+	for _, pack := range tests {
+		if err := registry.Register(pack...); err == nil {
+			t.Errorf("Expected an error registering %v", pack)
+		}
+	}
+	if got := registry.Presets(); len(got) != 0 {
+		t.Errorf("Expected nothing registered, got %v", got)
+	}
+
+	registry.Register(constellation.Preset{Name: "a", Collection: "com.example.a", Path: ".x"})
+	if err := registry.Register(constellation.Preset{Name: "a", Collection: "com.example.a", Path: ".x"}); err == nil {
+		t.Error("Expected an error registering a duplicate name")
+	}
+}
+
+// TestDefaultRegistryInference tests that presets registered in DefaultRegistry
+// extend path inference
+func TestDefaultRegistryInference(t *testing.T) {
+	const collection = "com.example.inference.endorsement"
+	if _, ok := constellation.DefaultPathFor(collection, constellation.TargetDID); ok {
+		t.Fatal("Expected no path before registering")
+	}
+
+	err := constellation.DefaultRegistry.Register(constellation.Preset{
+		Name: "example.endorsements", Collection: collection, Path: ".subject", TargetKind: constellation.TargetDID,
+	})
+	if err != nil {
+		t.Fatalf("Failed to register: %v", err)
+	}
+	if path, ok := constellation.DefaultPathFor(collection, constellation.TargetDID); !ok || path != ".subject" {
+		t.Errorf("Expected .subject, got %q, %v", path, ok)
+	}
+}
+
+// TestBuiltinPacks tests that the non-Bluesky packs are registered by default
+func TestBuiltinPacks(t *testing.T) {
+	tests := map[string]string{
+		"whtwnd.likes":          constellation.CollectionLike,
+		"frontpage.submissions": "fyi.unravel.frontpage.post",
+		"frontpage.votes":       "fyi.unravel.frontpage.vote",
+		"smokesignal.rsvps":     "events.smokesignal.calendar.rsvp",
+		"calendar.rsvps":        "community.lexicon.calendar.rsvp",
+	}
+	for name, collection := range tests {
+		preset, ok := constellation.DefaultRegistry.Lookup(name)
+		if !ok || preset.Collection != collection {
+			t.Errorf("Expected %s for %s, got %+v, %v", collection, name, preset, ok)
+		}
+	}
+
+	if path, ok := constellation.DefaultPathFor("fyi.unravel.frontpage.vote", constellation.TargetURI); !ok || path != ".subject.uri" {
+		t.Errorf("Expected vote path to be inferred, got %q, %v", path, ok)
+	}
+	if _, ok := constellation.DefaultPathFor("fyi.unravel.frontpage.comment", constellation.TargetURI); ok {
+		t.Error("Expected comment path to be ambiguous")
+	}
+}
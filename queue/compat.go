@@ -0,0 +1,22 @@
+package queue
+
+import (
+	"context"
+	"time"
+)
+
+// withoutCancel returns a context carrying parent's values that is never
+// cancelled, like context.WithoutCancel, which needs Go 1.21
+func withoutCancel(parent context.Context) context.Context {
+	return detachedContext{parent}
+}
+
+// detachedContext is a context with a parent's values but no deadline or cancellation
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+func (c detachedContext) Value(key any) any         { return c.parent.Value(key) }
@@ -0,0 +1,444 @@
+// Package queue provides a durable work queue for long-running crawl and export tasks.
+// Tasks are stored in a SQLite database, so queued and in-progress work survives
+// process restarts. The caller opens the database with the SQLite driver of their
+// choice and passes the *sql.DB to New. With more than one connection, open it
+// with a busy timeout and immediate transactions, so concurrent claims wait for
+// each other instead of failing with "database is locked"; with
+// modernc.org/sqlite, for example:
+//
+//	db, err := sql.Open("sqlite", "file:crawl.db?_txlock=immediate&_pragma=busy_timeout(5000)")
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+const (
+	// DefaultVisibilityTimeout is how long a claimed task is hidden from other workers
+	DefaultVisibilityTimeout = 5 * time.Minute
+	// DefaultMaxAttempts is the number of times a task is tried before it is marked failed
+	DefaultMaxAttempts = 5
+	// DefaultPollInterval is how often idle workers check for new tasks
+	DefaultPollInterval = time.Second
+	// DefaultRetryDelay is the base delay before a failed task is retried
+	DefaultRetryDelay = 10 * time.Second
+
+	// maxRetryShift caps the doublings of RetryDelay, so backoff can't overflow
+	maxRetryShift = 16
+)
+
+// ErrClaimLost is returned when completing or failing a task whose claim expired
+// and was taken over by another worker
+var ErrClaimLost = errors.New("queue: task claim expired and was taken by another worker")
+
+// errClaimRaced is returned by claim when another worker claimed the selected
+// task between the select and the update
+var errClaimRaced = errors.New("queue: task was claimed concurrently")
+
+// Task states
+const (
+	StatePending = "pending"
+	StateDone    = "done"
+	StateFailed  = "failed"
+)
+
+// Task represents a unit of work in the queue
+type Task struct {
+	ID        int64
+	Kind      string // Application-defined task type, e.g. "export-likes"
+	Payload   []byte // Application-defined task data, typically JSON
+	Attempts  int    // Number of times the task has been claimed, including the current attempt
+	LastError string // Error from the most recent failed attempt
+
+	// claimedUntil is the visibility deadline set by Claim or Extend, identifying
+	// the claim; nil if unclaimed. It's shared by copies of the task, so a handler
+	// extending its copy keeps the worker's claim current.
+	claimedUntil *atomic.Int64
+}
+
+// Handler processes a claimed task. Returning an error schedules a retry.
+// Handlers that may run longer than the visibility timeout should call
+// Queue.Extend periodically to keep their claim.
+type Handler func(ctx context.Context, task Task) error
+
+// Queue is a durable work queue backed by a SQLite database
+type Queue struct {
+	DB                *sql.DB
+	VisibilityTimeout time.Duration // How long a claimed task stays hidden before it is retried
+	MaxAttempts       int           // Attempts before a task is marked failed
+	PollInterval      time.Duration // How often idle workers poll for tasks
+	RetryDelay        time.Duration // Base delay before retrying, doubled per attempt
+	Logger            *log.Logger   // Receives per-task events such as lost claims in Run; nil disables logging
+
+	// DeadLetters, if set, receives a DeadLetter for each task marked failed,
+	// under "deadletter/<id>" keys, so failures can be inspected or replayed
+	// from the same backend as the client's other state. Write errors are
+	// logged rather than returned, since the task's state is already recorded.
+	DeadLetters constellation.KVStore
+}
+
+// DeadLetter is the record of a failed task written to Queue.DeadLetters
+type DeadLetter struct {
+	Task
+	FailedAt time.Time
+}
+
+// New creates a queue with default settings and ensures its table exists
+func New(ctx context.Context, db *sql.DB) (*Queue, error) {
+	q := &Queue{
+		DB:                db,
+		VisibilityTimeout: DefaultVisibilityTimeout,
+		MaxAttempts:       DefaultMaxAttempts,
+		PollInterval:      DefaultPollInterval,
+		RetryDelay:        DefaultRetryDelay,
+	}
+
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS constellation_tasks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind TEXT NOT NULL,
+		payload BLOB NOT NULL,
+		state TEXT NOT NULL DEFAULT 'pending',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		visible_at INTEGER NOT NULL,
+		created_at INTEGER NOT NULL
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create queue table: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS constellation_tasks_ready
+		ON constellation_tasks (state, visible_at)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create queue index: %w", err)
+	}
+
+	return q, nil
+}
+
+// Enqueue adds a task to the queue and returns its ID
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload []byte) (int64, error) {
+	if payload == nil {
+		// A nil slice is stored as NULL, which the payload column rejects
+		payload = []byte{}
+	}
+	now := time.Now().UnixNano()
+	result, err := q.DB.ExecContext(ctx,
+		`INSERT INTO constellation_tasks (kind, payload, visible_at, created_at) VALUES (?, ?, ?, ?)`,
+		kind, payload, now, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue task: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+// Claim takes the next available task, hiding it from other workers for the
+// visibility timeout. It returns nil if no task is available. Tasks whose
+// claims expired MaxAttempts times without completing or failing, such as ones
+// that crash the process, are marked failed instead of being claimed again.
+func (q *Queue) Claim(ctx context.Context) (*Task, error) {
+	for {
+		task, err := q.claim(ctx)
+		// Losing a race means another worker took the task, not that the queue
+		// is empty, so look for the next one
+		if !errors.Is(err, errClaimRaced) {
+			return task, err
+		}
+	}
+}
+
+// claim makes one attempt at claiming the next available task, returning
+// errClaimRaced if another worker claimed it first
+func (q *Queue) claim(ctx context.Context) (*Task, error) {
+	tx, err := q.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var task Task
+	var visibleAt int64
+	var abandoned []Task
+	for {
+		err = tx.QueryRowContext(ctx,
+			`SELECT id, kind, payload, attempts, last_error, visible_at FROM constellation_tasks
+			WHERE state = ? AND visible_at <= ? ORDER BY visible_at, id LIMIT 1`,
+			StatePending, now.UnixNano(),
+		).Scan(&task.ID, &task.Kind, &task.Payload, &task.Attempts, &task.LastError, &visibleAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			if err := tx.Commit(); err != nil {
+				return nil, fmt.Errorf("failed to commit claim: %w", err)
+			}
+			q.deadLetter(ctx, abandoned...)
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to select task: %w", err)
+		}
+		if task.Attempts < q.MaxAttempts {
+			break
+		}
+
+		task.LastError = fmt.Sprintf("claim expired after %d attempts", task.Attempts)
+		_, err = tx.ExecContext(ctx,
+			`UPDATE constellation_tasks SET state = ?, last_error = ?
+			WHERE id = ? AND visible_at = ?`,
+			StateFailed, task.LastError, task.ID, visibleAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to mark abandoned task %d failed: %w", task.ID, err)
+		}
+		abandoned = append(abandoned, task)
+	}
+
+	// Guard against another process claiming the same task concurrently
+	claimedUntil := now.Add(q.VisibilityTimeout).UnixNano()
+	result, err := tx.ExecContext(ctx,
+		`UPDATE constellation_tasks SET attempts = attempts + 1, visible_at = ?
+		WHERE id = ? AND visible_at = ?`,
+		claimedUntil, task.ID, visibleAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim task: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim task: %w", err)
+	}
+
+	// Keep the abandoned tasks failed even if the claim itself was lost
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit claim: %w", err)
+	}
+	q.deadLetter(ctx, abandoned...)
+	if affected == 0 {
+		return nil, errClaimRaced
+	}
+
+	task.Attempts++
+	task.claimedUntil = new(atomic.Int64)
+	task.claimedUntil.Store(claimedUntil)
+	return &task, nil
+}
+
+// Extend renews a claimed task's claim for another visibility timeout, so a
+// long-running handler isn't taken over by another worker. It returns
+// ErrClaimLost if the claim already expired and another worker took the task.
+func (q *Queue) Extend(ctx context.Context, task *Task) error {
+	if task.claimedUntil == nil {
+		return fmt.Errorf("failed to extend task %d: task was not claimed", task.ID)
+	}
+	claimedUntil := time.Now().Add(q.VisibilityTimeout).UnixNano()
+	if err := q.update(ctx, task, `visible_at = ?`, claimedUntil); err != nil {
+		return fmt.Errorf("failed to extend task %d: %w", task.ID, err)
+	}
+	task.claimedUntil.Store(claimedUntil)
+	return nil
+}
+
+// Complete marks a claimed task as done. It returns ErrClaimLost if the claim
+// expired and another worker has since claimed the task.
+func (q *Queue) Complete(ctx context.Context, task *Task) error {
+	if err := q.update(ctx, task, `state = ?`, StateDone); err != nil {
+		return fmt.Errorf("failed to complete task %d: %w", task.ID, err)
+	}
+	return nil
+}
+
+// Fail records a failed attempt, scheduling a retry with exponential backoff or
+// marking the task failed once MaxAttempts is reached. Like Complete, it returns
+// ErrClaimLost if another worker has since claimed the task.
+func (q *Queue) Fail(ctx context.Context, task *Task, cause error) error {
+	state := StatePending
+	if task.Attempts >= q.MaxAttempts {
+		state = StateFailed
+	}
+
+	err := q.update(ctx, task, `state = ?, last_error = ?, visible_at = ?`,
+		state, cause.Error(), time.Now().Add(q.retryDelay(task.Attempts)).UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to record failure for task %d: %w", task.ID, err)
+	}
+	if state == StateFailed {
+		failed := *task
+		failed.LastError = cause.Error()
+		q.deadLetter(ctx, failed)
+	}
+	return nil
+}
+
+// deadLetter writes failed tasks to DeadLetters, if set
+func (q *Queue) deadLetter(ctx context.Context, tasks ...Task) {
+	if q.DeadLetters == nil {
+		return
+	}
+	for _, task := range tasks {
+		data, err := json.Marshal(DeadLetter{Task: task, FailedAt: time.Now()})
+		if err == nil {
+			err = q.DeadLetters.Put(ctx, "deadletter/"+strconv.FormatInt(task.ID, 10), data)
+		}
+		if err != nil {
+			q.logf("queue: failed to store dead letter for task %d: %v", task.ID, err)
+		}
+	}
+}
+
+// retryDelay returns the backoff after a task's attempts, doubling RetryDelay
+// per attempt after the first up to maxRetryShift times
+func (q *Queue) retryDelay(attempts int) time.Duration {
+	shift := attempts - 1
+	if shift < 0 {
+		shift = 0
+	} else if shift > maxRetryShift {
+		shift = maxRetryShift
+	}
+	return q.RetryDelay << shift
+}
+
+// release returns a claimed task to the queue immediately without counting
+// the attempt, for work interrupted by shutdown rather than by a failure
+func (q *Queue) release(ctx context.Context, task *Task) error {
+	if err := q.update(ctx, task, `attempts = attempts - 1, visible_at = ?`, time.Now().UnixNano()); err != nil {
+		return fmt.Errorf("failed to release task %d: %w", task.ID, err)
+	}
+	return nil
+}
+
+// update sets columns on task's row. Tasks from Claim are only updated while
+// their claim is current; tasks built by the caller are updated unconditionally.
+func (q *Queue) update(ctx context.Context, task *Task, set string, args ...any) error {
+	query := `UPDATE constellation_tasks SET ` + set + ` WHERE id = ?`
+	args = append(args, task.ID)
+	if task.claimedUntil != nil {
+		query += ` AND visible_at = ?`
+		args = append(args, task.claimedUntil.Load())
+	}
+
+	result, err := q.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if task.claimedUntil == nil {
+		return nil
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrClaimLost
+	}
+	return nil
+}
+
+// Counts returns the number of tasks in each state
+func (q *Queue) Counts(ctx context.Context) (map[string]int, error) {
+	rows, err := q.DB.QueryContext(ctx,
+		`SELECT state, COUNT(*) FROM constellation_tasks GROUP BY state`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var state string
+		var count int
+		if err := rows.Scan(&state, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan task count: %w", err)
+		}
+		counts[state] = count
+	}
+	return counts, rows.Err()
+}
+
+// Run processes tasks with a pool of workers until ctx is cancelled. Handler
+// errors are recorded on the task and retried, and a task whose claim was lost
+// to another worker is logged and skipped; any other queue error stops every
+// worker and is returned. Tasks interrupted by cancellation are released
+// without counting the attempt.
+func (q *Queue) Run(ctx context.Context, workers int, handler Handler) error {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := q.work(workCtx, handler); err != nil {
+				errs <- err
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// work runs a single worker loop
+func (q *Queue) work(ctx context.Context, handler Handler) error {
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		task, err := q.Claim(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		if task == nil {
+			select {
+			case <-time.After(q.PollInterval):
+			case <-ctx.Done():
+			}
+			continue
+		}
+
+		handlerErr := handler(ctx, *task)
+		switch {
+		case handlerErr != nil && ctx.Err() != nil:
+			err = q.release(withoutCancel(ctx), task)
+		case handlerErr != nil:
+			err = q.Fail(withoutCancel(ctx), task, handlerErr)
+		default:
+			err = q.Complete(withoutCancel(ctx), task)
+		}
+		if errors.Is(err, ErrClaimLost) {
+			q.logf("queue: skipping task %d (%s): %v", task.ID, task.Kind, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// logf logs a per-task event, if logging is enabled
+func (q *Queue) logf(format string, args ...any) {
+	if q.Logger != nil {
+		q.Logger.Printf(format, args...)
+	}
+}
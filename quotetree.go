@@ -0,0 +1,114 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+)
+
+// QuoteTreeLimits bounds a QuoteTree crawl. Zero values mean no limit.
+type QuoteTreeLimits struct {
+	MaxQuotesPerPost int // Quotes followed from each post; QuoteCount still reports them all
+	MaxNodes         int // Posts in the tree, including the root
+
+	// Budget, if set, caps the requests and records of the whole crawl. When it's
+	// exceeded the partial tree is returned with a BudgetExceededError.
+	Budget *Budget
+}
+
+// QuoteNode is a post in a quote tree
+type QuoteNode struct {
+	URI        string
+	Depth      int          // Hops from the root, which is at depth zero
+	QuoteCount int          // Direct quotes of this post, including any not followed
+	Quotes     []*QuoteNode // Quotes followed from this post, nil at the depth limit
+}
+
+// Size returns the number of posts in the tree rooted at n, including n
+func (n *QuoteNode) Size() int {
+	size := 1
+	for _, quote := range n.Quotes {
+		size += quote.Size()
+	}
+	return size
+}
+
+// Reach returns the total quote count of n and every post quoting it in the
+// tree, counting quotes that weren't followed
+func (n *QuoteNode) Reach() int {
+	reach := n.QuoteCount
+	for _, quote := range n.Quotes {
+		reach += quote.Reach()
+	}
+	return reach
+}
+
+// QuoteTree crawls quotes of the post at rootURI, then quotes of those quotes, up
+// to depth hops, for analyzing how a post spread. Each level is fetched with
+// DefaultParallelism concurrent workers. Nodes at the depth limit have their
+// QuoteCount but no Quotes. If the crawl fails partway, the tree built so far is
+// returned with the error.
+func (c *Client) QuoteTree(ctx context.Context, rootURI string, depth int, limits QuoteTreeLimits) (*QuoteNode, error) {
+	root := &QuoteNode{URI: rootURI}
+	seen := map[string]bool{rootURI: true}
+	nodes := 1
+	ctx = WithBudget(ctx, limits.Budget)
+
+	level := []*QuoteNode{root}
+	for len(level) > 0 {
+		children := make([][]LinkRecord, len(level))
+		errs := make([]error, len(level))
+
+		parallelEach(len(level), DefaultParallelism, func(i int) {
+			children[i], errs[i] = c.crawlQuotes(ctx, level[i], depth, limits)
+		})
+
+		if err := errors.Join(errs...); err != nil {
+			return root, err
+		}
+
+		var next []*QuoteNode
+		for i, node := range level {
+			for _, record := range children[i] {
+				uri := record.RecordURI()
+				if seen[uri] || (limits.MaxNodes > 0 && nodes >= limits.MaxNodes) {
+					continue
+				}
+				seen[uri] = true
+				nodes++
+
+				child := &QuoteNode{URI: uri, Depth: node.Depth + 1}
+				node.Quotes = append(node.Quotes, child)
+				next = append(next, child)
+			}
+		}
+		level = next
+	}
+	return root, nil
+}
+
+// crawlQuotes sets node's QuoteCount and returns the quotes to follow from it,
+// which is none at the depth limit
+func (c *Client) crawlQuotes(ctx context.Context, node *QuoteNode, depth int, limits QuoteTreeLimits) ([]LinkRecord, error) {
+	if node.Depth >= depth || limits.MaxQuotesPerPost > 0 {
+		count, err := c.QuoteCount(ctx, node.URI)
+		if err != nil {
+			return nil, err
+		}
+		node.QuoteCount = count
+		if node.Depth >= depth {
+			return nil, nil
+		}
+	}
+
+	quotes, err := c.QuotesOf(ctx, node.URI, PaginateOptions{MaxRecords: limits.MaxQuotesPerPost})
+	if err != nil {
+		return nil, err
+	}
+	if limits.MaxQuotesPerPost == 0 {
+		node.QuoteCount = len(quotes)
+	} else if len(quotes) > limits.MaxQuotesPerPost {
+		// The limit applies to each embed path, so trim the merged quotes
+		quotes = quotes[:limits.MaxQuotesPerPost]
+	}
+	return quotes, nil
+}
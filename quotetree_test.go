@@ -0,0 +1,94 @@
+package constellation_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// newQuoteServer serves a quote graph of post rkeys to the rkeys quoting them
+func newQuoteServer(t *testing.T, quotes map[string][]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var quoting []string
+		if query.Get("path") == constellation.PathEmbedRecordURI {
+			target := query.Get("target")
+			quoting = quotes[target[strings.LastIndex(target, "/")+1:]]
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/count") {
+			json.NewEncoder(w).Encode(map[string]int{"total": len(quoting)})
+			return
+		}
+		records := []constellation.LinkRecord{}
+		for _, rkey := range quoting {
+			records = append(records, constellation.LinkRecord{DID: "did:plc:q", Collection: constellation.CollectionPost, RKey: rkey})
+		}
+		json.NewEncoder(w).Encode(constellation.LinksResponse{LinkingRecords: records})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestQuoteTree tests crawling quotes of quotes with depth and per-post limits
+func TestQuoteTree(t *testing.T) {
+	server := newQuoteServer(t, map[string][]string{
+		"root": {"a", "b", "c"},
+		"a":    {"d"},
+		"d":    {"e"},
+	})
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	ctx := context.Background()
+	rootURI := "at://did:plc:q/app.bsky.feed.post/root"
+
+	tree, err := client.QuoteTree(ctx, rootURI, 2, constellation.QuoteTreeLimits{})
+	if err != nil {
+		t.Fatalf("QuoteTree failed: %v", err)
+	}
+	if tree.QuoteCount != 3 || len(tree.Quotes) != 3 {
+		t.Fatalf("Expected 3 quotes of the root, got count %d with %d nodes", tree.QuoteCount, len(tree.Quotes))
+	}
+	a := tree.Quotes[0]
+	if len(a.Quotes) != 1 || a.Quotes[0].Depth != 2 {
+		t.Fatalf("Expected one quote of a at depth 2, got %+v", a.Quotes)
+	}
+	if d := a.Quotes[0]; d.QuoteCount != 1 || d.Quotes != nil {
+		t.Errorf("Expected d to be counted but not followed at the depth limit, got %+v", d)
+	}
+	if size, reach := tree.Size(), tree.Reach(); size != 5 || reach != 5 {
+		t.Errorf("Expected size 5 and reach 5, got %d and %d", size, reach)
+	}
+
+	limited, err := client.QuoteTree(ctx, rootURI, 2, constellation.QuoteTreeLimits{MaxQuotesPerPost: 1, MaxNodes: 2})
+	if err != nil {
+		t.Fatalf("Limited QuoteTree failed: %v", err)
+	}
+	if limited.QuoteCount != 3 || len(limited.Quotes) != 1 {
+		t.Errorf("Expected 1 of 3 quotes followed, got count %d with %d nodes", limited.QuoteCount, len(limited.Quotes))
+	}
+	if limited.Size() != 2 {
+		t.Errorf("Expected the node limit to cap the tree at 2, got %d", limited.Size())
+	}
+}
+
+// TestQuoteTreeBudget tests that an exhausted budget returns the partial tree
+func TestQuoteTreeBudget(t *testing.T) {
+	server := newQuoteServer(t, map[string][]string{"root": {"a"}, "a": {"b"}})
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+
+	budget := &constellation.Budget{MaxRequests: 2}
+	tree, err := client.QuoteTree(context.Background(), "at://did:plc:q/app.bsky.feed.post/root", 5, constellation.QuoteTreeLimits{Budget: budget})
+	if err == nil {
+		t.Fatal("Expected a budget error")
+	}
+	if tree == nil || len(tree.Quotes) != 1 {
+		t.Errorf("Expected the partial tree with the root's quote, got %+v", tree)
+	}
+}
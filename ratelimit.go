@@ -0,0 +1,117 @@
+package constellation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// tokenBucket is a token bucket rate limiter
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64 // Tokens added per second
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+// RateLimiterState is a snapshot of the client's rate limiter
+type RateLimiterState struct {
+	Enabled    bool
+	Tokens     float64       // Requests that can be made immediately
+	Capacity   float64       // Maximum burst size
+	Rate       float64       // Tokens added per second
+	NextRefill time.Duration // Time until the next whole token is available; zero if one is available now
+}
+
+// refill adds tokens for the time elapsed since the last refill. Callers hold mu.
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+}
+
+// wait blocks until a token is available or ctx is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		b.refill(time.Now())
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// SetRateLimit limits the client to perSecond requests per second on average, with
+// bursts of up to burst requests. A perSecond of zero or less removes the limit.
+func (c *Client) SetRateLimit(perSecond float64, burst int) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if perSecond <= 0 {
+		c.rateLimiter = nil
+		return c
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	c.rateLimiter = &tokenBucket{
+		rate:     perSecond,
+		capacity: float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+	return c
+}
+
+// RateLimiterState returns the current state of the client's rate limiter, so
+// schedulers above the client can decide when to dispatch work
+func (c *Client) RateLimiterState() RateLimiterState {
+	c.mu.Lock()
+	limiter := c.rateLimiter
+	c.mu.Unlock()
+
+	if limiter == nil {
+		return RateLimiterState{}
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	limiter.refill(time.Now())
+	state := RateLimiterState{
+		Enabled:  true,
+		Tokens:   limiter.tokens,
+		Capacity: limiter.capacity,
+		Rate:     limiter.rate,
+	}
+	if limiter.tokens < 1 {
+		state.NextRefill = time.Duration((1 - limiter.tokens) / limiter.rate * float64(time.Second))
+	}
+	return state
+}
+
+// waitForRateLimit blocks until the client's rate limiter admits a request
+func (c *Client) waitForRateLimit(ctx context.Context) error {
+	c.mu.Lock()
+	limiter := c.rateLimiter
+	c.mu.Unlock()
+
+	if limiter == nil {
+		return nil
+	}
+	return limiter.wait(ctx)
+}
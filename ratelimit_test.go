@@ -0,0 +1,49 @@
+package constellation_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestRateLimiterState tests token consumption and refill reporting
+func TestRateLimiterState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	if client.RateLimiterState().Enabled {
+		t.Error("Expected rate limiter to be disabled by default")
+	}
+
+	client.SetRateLimit(10, 2)
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetAPIInfo(); err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+	}
+
+	state := client.RateLimiterState()
+	if !state.Enabled || state.Capacity != 2 {
+		t.Errorf("Unexpected rate limiter state: %+v", state)
+	}
+	if state.Tokens >= 1 {
+		t.Errorf("Expected burst to be used up, got %.2f tokens", state.Tokens)
+	}
+	if state.NextRefill <= 0 || state.NextRefill > 100*time.Millisecond {
+		t.Errorf("Expected next refill within 100ms, got %s", state.NextRefill)
+	}
+
+	start := time.Now()
+	if _, err := client.GetAPIInfo(); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected request to wait for a token, took %s", elapsed)
+	}
+}
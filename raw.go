@@ -0,0 +1,172 @@
+package constellation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrRawFiltered is returned by raw listing methods when the query needs
+// filtering the client would do itself, such as exclusions, opt-out lists, or
+// filters the instance doesn't support, which raw bodies would bypass
+var ErrRawFiltered = errors.New("raw responses can't be filtered client-side")
+
+// RawResponse is an undecoded API response, for passing bodies on to other
+// systems without decoding and re-encoding them
+type RawResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// GetLinksRaw is like GetLinksContext but returns the undecoded response
+// Endpoint: GET /links
+func (c *Client) GetLinksRaw(ctx context.Context, params LinksParams) (*RawResponse, error) {
+	if err := c.checkRawListing(params); err != nil {
+		return nil, err
+	}
+	return c.getRaw(ctx, "/links", c.applyCapabilities(params).queryValues(true))
+}
+
+// GetLinksCountRaw is like GetLinksCountContext but returns the undecoded
+// response. It doesn't use the count cache or fall back to counting by paging.
+// Endpoint: GET /links/count
+func (c *Client) GetLinksCountRaw(ctx context.Context, params LinksParams) (*RawResponse, error) {
+	if err := c.checkRawCount(params); err != nil {
+		return nil, err
+	}
+	return c.getRaw(ctx, "/links/count", c.applyCapabilities(params).queryValues(false))
+}
+
+// GetDistinctDIDsRaw is like GetDistinctDIDsContext but returns the undecoded
+// response
+// Endpoint: GET /links/distinct-dids
+func (c *Client) GetDistinctDIDsRaw(ctx context.Context, params LinksParams) (*RawResponse, error) {
+	if err := c.checkRawListing(params); err != nil {
+		return nil, err
+	}
+	return c.getRaw(ctx, "/links/distinct-dids", c.applyCapabilities(params).queryValues(true))
+}
+
+// GetDistinctDIDsCountRaw is like GetDistinctDIDsCountContext but returns the
+// undecoded response, without caching or fallbacks
+// Endpoint: GET /links/count/distinct-dids
+func (c *Client) GetDistinctDIDsCountRaw(ctx context.Context, params LinksParams) (*RawResponse, error) {
+	if err := c.checkRawCount(params); err != nil {
+		return nil, err
+	}
+	return c.getRaw(ctx, "/links/count/distinct-dids", c.applyCapabilities(params).queryValues(false))
+}
+
+// GetAllLinkCountsRaw is like GetAllLinkCounts but returns the undecoded response
+// Endpoint: GET /links/all/count
+func (c *Client) GetAllLinkCountsRaw(ctx context.Context, target string) (*RawResponse, error) {
+	if target == "" {
+		return nil, fmt.Errorf("target parameter is required")
+	}
+	params := url.Values{}
+	params.Add("target", queryTarget(target))
+	return c.getRaw(ctx, "/links/all/count", params)
+}
+
+// GetAllLinksRaw pages through every record linking to a target like
+// GetLinksEach, but passes fn each page's undecoded response. Only the cursor
+// and record count are decoded. MaxRecords, MaxPages, MaxDuration, and Budget
+// are honored a page at a time, so the last page may exceed MaxRecords; other
+// options are ignored. Returning ErrStopVisit from fn stops without an error.
+func (c *Client) GetAllLinksRaw(ctx context.Context, params LinksParams, opts PaginateOptions, fn func(*RawResponse) error) error {
+	if err := c.checkRawListing(params); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	ctx = WithBudget(ctx, opts.Budget)
+	records, pages := 0, 0
+	for {
+		if opts.MaxPages > 0 && pages >= opts.MaxPages {
+			return nil
+		}
+		if opts.MaxDuration > 0 && time.Since(start) >= opts.MaxDuration {
+			return nil
+		}
+		page, err := c.GetLinksRaw(ctx, params)
+		if err != nil {
+			return err
+		}
+		var meta struct {
+			Cursor         string     `json:"cursor"`
+			LinkingRecords []struct{} `json:"linking_records"`
+		}
+		codec := c.codecFor(page.Header.Get("Content-Type"))
+		if err := codec.Decode(bytes.NewReader(page.Body), &meta); err != nil {
+			return fmt.Errorf("failed to decode links response: %w", err)
+		}
+		for range meta.LinkingRecords {
+			if err := spendRecord(ctx); err != nil {
+				return err
+			}
+		}
+
+		if err := fn(page); err != nil {
+			if errors.Is(err, ErrStopVisit) {
+				return nil
+			}
+			return err
+		}
+		pages++
+		records += len(meta.LinkingRecords)
+
+		if meta.Cursor == "" || len(meta.LinkingRecords) == 0 {
+			return nil
+		}
+		if opts.MaxRecords > 0 && records >= opts.MaxRecords {
+			return nil
+		}
+		params.Cursor = meta.Cursor
+	}
+}
+
+// checkRawListing rejects listings the client would otherwise filter itself
+func (c *Client) checkRawListing(params LinksParams) error {
+	if params.Target == "" {
+		return fmt.Errorf("target parameter is required")
+	}
+	if c.excluding() {
+		return fmt.Errorf("%w: exclusions are configured", ErrRawFiltered)
+	}
+	if c.needsClientFilter(params) {
+		return fmt.Errorf("%w: the instance doesn't support the query's filters", ErrRawFiltered)
+	}
+	return nil
+}
+
+// checkRawCount rejects counts the client would otherwise compute by paging
+func (c *Client) checkRawCount(params LinksParams) error {
+	if params.Target == "" {
+		return fmt.Errorf("target parameter is required")
+	}
+	if c.needsClientFilter(params) {
+		return fmt.Errorf("%w: the instance doesn't support the query's filters", ErrRawFiltered)
+	}
+	return nil
+}
+
+// getRaw requests endpoint and reads the whole response body
+func (c *Client) getRaw(ctx context.Context, endpoint string, params url.Values) (*RawResponse, error) {
+	resp, err := c.makeRequestContext(ctx, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return &RawResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+}
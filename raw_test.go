@@ -0,0 +1,103 @@
+package constellation_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestGetLinksRaw tests returning an undecoded page with its status and headers
+func TestGetLinksRaw(t *testing.T) {
+	body := `{"total": 1, "linking_records": [{"did": "did:plc:a", "extra": {"kept": true}}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Served-By", "test")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	resp, err := client.GetLinksRaw(context.Background(), constellation.LinksParams{Target: "did:plc:b"})
+	if err != nil {
+		t.Fatalf("GetLinksRaw failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("X-Served-By") != "test" {
+		t.Errorf("Expected status and headers to be kept, got %d %v", resp.StatusCode, resp.Header)
+	}
+	if string(resp.Body) != body {
+		t.Errorf("Expected the body byte for byte, got %s", resp.Body)
+	}
+
+	count, err := client.GetLinksCountRaw(context.Background(), constellation.LinksParams{Target: "did:plc:b"})
+	if err != nil || !bytes.Equal(count.Body, []byte(body)) {
+		t.Errorf("Expected the count body, got %v", err)
+	}
+}
+
+// TestGetAllLinksRaw tests paging through raw bodies
+func TestGetAllLinksRaw(t *testing.T) {
+	server := newPagedServer(t, 25)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	var pages [][]byte
+	err := client.GetAllLinksRaw(context.Background(), params, constellation.PaginateOptions{}, func(page *constellation.RawResponse) error {
+		pages = append(pages, page.Body)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("GetAllLinksRaw failed: %v", err)
+	}
+	if len(pages) != 3 || !strings.Contains(string(pages[2]), "did:plc:user24") {
+		t.Errorf("Expected 3 pages ending with the last record, got %d", len(pages))
+	}
+
+	pages = nil
+	err = client.GetAllLinksRaw(context.Background(), params, constellation.PaginateOptions{MaxRecords: 15}, func(page *constellation.RawResponse) error {
+		pages = append(pages, page.Body)
+		return nil
+	})
+	if err != nil || len(pages) != 2 {
+		t.Errorf("Expected MaxRecords to stop after 2 pages, got %d, %v", len(pages), err)
+	}
+
+	pages = nil
+	err = client.GetAllLinksRaw(context.Background(), params, constellation.PaginateOptions{}, func(page *constellation.RawResponse) error {
+		pages = append(pages, page.Body)
+		return constellation.ErrStopVisit
+	})
+	if err != nil || len(pages) != 1 {
+		t.Errorf("Expected ErrStopVisit to stop after 1 page, got %d, %v", len(pages), err)
+	}
+}
+
+// TestRawFiltered tests that raw listings refuse queries needing client-side filtering
+func TestRawFiltered(t *testing.T) {
+	server := newPagedServer(t, 5)
+	ctx := context.Background()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	filtered := constellation.LinksParams{Target: "did:plc:example", FromDID: "did:plc:user1"}
+	if _, err := client.GetLinksRaw(ctx, filtered); !errors.Is(err, constellation.ErrRawFiltered) {
+		t.Errorf("Expected ErrRawFiltered for an emulated filter, got %v", err)
+	}
+	if _, err := client.GetLinksCountRaw(ctx, filtered); !errors.Is(err, constellation.ErrRawFiltered) {
+		t.Errorf("Expected ErrRawFiltered for an emulated count, got %v", err)
+	}
+
+	client.ExcludeDIDs(constellation.NewDIDSet("did:plc:user1"))
+	plain := constellation.LinksParams{Target: "did:plc:example"}
+	if _, err := client.GetLinksRaw(ctx, plain); !errors.Is(err, constellation.ErrRawFiltered) {
+		t.Errorf("Expected ErrRawFiltered with exclusions, got %v", err)
+	}
+	if _, err := client.GetLinksCountRaw(ctx, plain); err != nil {
+		t.Errorf("Expected counts to be unaffected by exclusions, got %v", err)
+	}
+}
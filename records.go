@@ -0,0 +1,136 @@
+package constellation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrRecordNotFound is returned by GetRecord when the PDS reports the record
+// doesn't exist, typically because it was deleted. It wraps the APIError.
+var ErrRecordNotFound = errors.New("record not found")
+
+// ATURI is a parsed at:// record URI
+type ATURI struct {
+	DID        string
+	Collection string
+	RKey       string
+}
+
+// ParseATURI parses an at://did/collection/rkey URI
+func ParseATURI(uri string) (ATURI, error) {
+	rest, ok := strings.CutPrefix(uri, "at://")
+	if !ok {
+		return ATURI{}, fmt.Errorf("not an at:// URI: %s", uri)
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return ATURI{}, fmt.Errorf("not a record URI: %s", uri)
+	}
+	return ATURI{DID: parts[0], Collection: parts[1], RKey: parts[2]}, nil
+}
+
+// String formats the URI
+func (u ATURI) String() string {
+	return "at://" + u.DID + "/" + u.Collection + "/" + u.RKey
+}
+
+// RecordURI returns the at:// URI of the linking record, building it from the
+// DID, collection, and rkey when the API didn't include one
+func (r LinkRecord) RecordURI() string {
+	if r.URI != "" {
+		return r.URI
+	}
+	return ATURI{DID: r.DID, Collection: r.Collection, RKey: r.RKey}.String()
+}
+
+// Time returns when the record was indexed, falling back to the creation time in
+// its TID record key. It returns false if neither is available.
+func (r LinkRecord) Time() (time.Time, bool) {
+	return recordTime(r)
+}
+
+// Record is a record fetched from its author's PDS
+type Record struct {
+	URI   string         `json:"uri"`
+	CID   string         `json:"cid"`
+	Value map[string]any `json:"value"`
+}
+
+// PDS returns the endpoint of the personal data server listed in the document,
+// or "" if it lists none
+func (d *DIDDocument) PDS() string {
+	for _, service := range d.Service {
+		if strings.HasSuffix(service.ID, "#atproto_pds") {
+			return service.ServiceEndpoint
+		}
+	}
+	return ""
+}
+
+// GetRecord fetches a record from its author's PDS, resolving the author's DID
+// document to find it
+func (c *Client) GetRecord(ctx context.Context, uri string) (*Record, error) {
+	parsed, err := ParseATURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := c.ResolveDID(ctx, parsed.DID)
+	if err != nil {
+		return nil, err
+	}
+	return c.getRecordFrom(ctx, doc, parsed)
+}
+
+// getRecordFrom fetches a record from the PDS listed in its author's resolved
+// DID document
+func (c *Client) getRecordFrom(ctx context.Context, doc *DIDDocument, parsed ATURI) (*Record, error) {
+	pds := doc.PDS()
+	if pds == "" {
+		return nil, fmt.Errorf("no PDS listed for %s", parsed.DID)
+	}
+
+	query := url.Values{}
+	query.Set("repo", parsed.DID)
+	query.Set("collection", parsed.Collection)
+	query.Set("rkey", parsed.RKey)
+	recordURL := strings.TrimSuffix(pds, "/") + "/xrpc/com.atproto.repo.getRecord?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", recordURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get record %s: %w", parsed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Status: resp.Status}
+		var xrpcErr struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&xrpcErr)
+		if resp.StatusCode == http.StatusNotFound || xrpcErr.Error == "RecordNotFound" {
+			return nil, fmt.Errorf("%w: %s: %w", ErrRecordNotFound, parsed, apiErr)
+		}
+		return nil, apiErr
+	}
+
+	var record Record
+	if err := json.NewDecoder(resp.Body).Decode(&record); err != nil {
+		return nil, fmt.Errorf("failed to decode record: %w", err)
+	}
+	return &record, nil
+}
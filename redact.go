@@ -0,0 +1,103 @@
+package constellation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"unicode/utf8"
+)
+
+// Redactor rewrites a target URI or DID before it's logged or used as a label
+type Redactor func(identifier string) string
+
+// redactedKeys are the log attributes whose values are identifiers
+var redactedKeys = map[string]bool{"target": true, "did": true, "uri": true}
+
+// UseRedactor redacts target URIs and DIDs in the client's own log lines and in
+// the errors and summaries it builds. Pass nil to stop redacting. Other clients
+// in the process keep their own rules.
+func (c *Client) UseRedactor(r Redactor) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.redactor = r
+	return c
+}
+
+// Redact applies the client's redactor to identifier, for callers labelling
+// their own metrics and traces consistently with the client's logs
+func (c *Client) Redact(identifier string) string {
+	c.mu.Lock()
+	r := c.redactor
+	c.mu.Unlock()
+	return r.Redact(identifier)
+}
+
+// redactArgs returns log key-value attributes with the values of identifier
+// attributes redacted
+func (c *Client) redactArgs(args []any) []any {
+	c.mu.Lock()
+	r := c.redactor
+	c.mu.Unlock()
+	if r == nil {
+		return args
+	}
+
+	redacted := append([]any(nil), args...)
+	for i := 0; i+1 < len(redacted); i += 2 {
+		if key, ok := redacted[i].(string); ok && redactedKeys[key] {
+			if value, ok := redacted[i+1].(string); ok {
+				redacted[i+1] = r.Redact(value)
+			}
+		}
+	}
+	return redacted
+}
+
+// Redact applies r to identifier. A nil Redactor and empty identifiers leave it
+// unchanged.
+func (r Redactor) Redact(identifier string) string {
+	if r == nil || identifier == "" {
+		return identifier
+	}
+	return r(identifier)
+}
+
+// Params returns a copy of p with its target and DID filter redacted, for
+// logging a query with its String or LogValue summary
+func (r Redactor) Params(p LinksParams) LinksParams {
+	p.Target = r.Redact(p.Target)
+	p.FromDID = r.Redact(p.FromDID)
+	return p
+}
+
+// Record returns a copy of record with its author's DID redacted, and so the
+// authority of its URI, for logging it with its String or LogValue summary
+func (r Redactor) Record(record LinkRecord) LinkRecord {
+	record.DID = r.Redact(record.DID)
+	return record
+}
+
+// HashRedactor replaces identifiers with the first n hex digits of their salted
+// SHA-256 hash, so log lines about the same account can still be correlated. A
+// shorter n lowers label cardinality at the cost of more collisions.
+func HashRedactor(salt string, n int) Redactor {
+	if n <= 0 || n > sha256.Size*2 {
+		n = sha256.Size * 2
+	}
+	return func(identifier string) string {
+		sum := sha256.Sum256([]byte(salt + identifier))
+		return "sha256:" + hex.EncodeToString(sum[:])[:n]
+	}
+}
+
+// TruncateRedactor keeps the first n characters of identifiers, enough to tell
+// DID methods and collections apart without identifying the account
+func TruncateRedactor(n int) Redactor {
+	return func(identifier string) string {
+		if utf8.RuneCountInString(identifier) <= n {
+			return identifier
+		}
+		runes := []rune(identifier)
+		return string(runes[:maxOf(n, 0)]) + "…"
+	}
+}
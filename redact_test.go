@@ -0,0 +1,69 @@
+package constellation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestHashRedactor tests that hashing is stable, salted, and truncated
+func TestHashRedactor(t *testing.T) {
+	redact := constellation.HashRedactor("salt", 12)
+	a, b := redact("did:plc:alice"), redact("did:plc:alice")
+	if a != b {
+		t.Errorf("Expected a stable hash, got %q and %q", a, b)
+	}
+	if !strings.HasPrefix(a, "sha256:") || len(a) != len("sha256:")+12 {
+		t.Errorf("Expected a 12 digit hash, got %q", a)
+	}
+	if other := constellation.HashRedactor("pepper", 12)("did:plc:alice"); other == a {
+		t.Error("Expected different salts to give different hashes")
+	}
+	if strings.Contains(a, "alice") {
+		t.Errorf("Expected the identifier to be hidden, got %q", a)
+	}
+}
+
+// TestTruncateRedactor tests that long identifiers are cut and short ones kept
+func TestTruncateRedactor(t *testing.T) {
+	redact := constellation.TruncateRedactor(12)
+	if got := redact("did:plc:alice"); got != "did:plc:alic…" {
+		t.Errorf("Expected truncation, got %q", got)
+	}
+	if got := redact("did:web:a"); got != "did:web:a" {
+		t.Errorf("Expected short identifiers to be kept, got %q", got)
+	}
+}
+
+// TestUseRedactor tests that redaction is per client, and that redacted copies
+// of queries and records summarize without identifiers
+func TestUseRedactor(t *testing.T) {
+	redactor := constellation.TruncateRedactor(8)
+	private := constellation.NewClient().UseRedactor(redactor)
+	public := constellation.NewClient()
+
+	if got := private.Redact("did:plc:alice"); got != "did:plc:…" {
+		t.Errorf("Expected the private client to redact, got %q", got)
+	}
+	if got := public.Redact("did:plc:alice"); got != "did:plc:alice" {
+		t.Errorf("Expected the public client not to redact, got %q", got)
+	}
+	if got := private.Redact(""); got != "" {
+		t.Errorf("Expected empty identifiers to stay empty, got %q", got)
+	}
+
+	params := constellation.LinksParams{Target: "at://did:plc:alice/app.bsky.feed.post/1", FromDID: "did:plc:bob"}
+	if got, want := redactor.Params(params).String(), "target=at://did… did=did:plc:…"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	record := constellation.LinkRecord{DID: "did:plc:alice", Collection: constellation.CollectionLike, RKey: "1"}
+	if got := redactor.Record(record).String(); strings.Contains(got, "alice") {
+		t.Errorf("Expected the record URI to be redacted, got %q", got)
+	}
+
+	private.UseRedactor(nil)
+	if got := private.Redact("did:plc:alice"); got != "did:plc:alice" {
+		t.Errorf("Expected no redaction after clearing, got %q", got)
+	}
+}
@@ -0,0 +1,248 @@
+package constellation
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RedisKV is a KVStore backed by a Redis (or Valkey, KeyDB, ...) server, so
+// caches and checkpoints can be shared between processes. It speaks the Redis
+// protocol directly and keeps a small pool of idle connections.
+type RedisKV struct {
+	Addr     string // host:port
+	Password string // Sent with AUTH when set
+	DB       int    // Selected with SELECT when nonzero
+	Prefix   string // Prepended to every key
+
+	// DialTimeout bounds connecting to the server; defaults to DefaultTimeout
+	DialTimeout time.Duration
+
+	mu   sync.Mutex
+	idle []*redisConn
+}
+
+// redisMaxIdle is the number of idle connections a RedisKV keeps open
+const redisMaxIdle = 4
+
+// NewRedisKV creates a store for the Redis server at addr
+func NewRedisKV(addr string) *RedisKV {
+	return &RedisKV{Addr: addr}
+}
+
+// Get implements KVStore
+func (r *RedisKV) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := r.do(ctx, "GET", r.Prefix+key)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, ErrNotFound
+	}
+	return reply, nil
+}
+
+// Put implements KVStore
+func (r *RedisKV) Put(ctx context.Context, key string, value []byte) error {
+	_, err := r.do(ctx, "SET", r.Prefix+key, string(value))
+	return err
+}
+
+// Delete implements KVStore
+func (r *RedisKV) Delete(ctx context.Context, key string) error {
+	_, err := r.do(ctx, "DEL", r.Prefix+key)
+	return err
+}
+
+// Close closes the idle connections
+func (r *RedisKV) Close() error {
+	r.mu.Lock()
+	idle := r.idle
+	r.idle = nil
+	r.mu.Unlock()
+
+	var errs []error
+	for _, conn := range idle {
+		errs = append(errs, conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// do runs a command on a pooled connection and returns its reply, which is nil
+// for a null reply
+func (r *RedisKV) do(ctx context.Context, args ...string) ([]byte, error) {
+	conn, err := r.conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+
+	reply, err := conn.do(ctx, args...)
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		conn.Close()
+		return nil, fmt.Errorf("redis: %w", ctxErr)
+	}
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		// The connection may be out of sync with the server; drop it
+		conn.Close()
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	r.release(conn)
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	return reply, nil
+}
+
+// conn returns an idle connection or dials a new one
+func (r *RedisKV) conn(ctx context.Context) (*redisConn, error) {
+	r.mu.Lock()
+	if n := len(r.idle); n > 0 {
+		conn := r.idle[n-1]
+		r.idle = r.idle[:n-1]
+		r.mu.Unlock()
+		return conn, nil
+	}
+	r.mu.Unlock()
+
+	timeout := r.DialTimeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	dialer := net.Dialer{Timeout: timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", r.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &redisConn{Conn: netConn, reader: bufio.NewReader(netConn)}
+	if r.Password != "" {
+		if _, err := conn.do(ctx, "AUTH", r.Password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if r.DB != 0 {
+		if _, err := conn.do(ctx, "SELECT", strconv.Itoa(r.DB)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// release returns a healthy connection to the pool
+func (r *RedisKV) release(conn *redisConn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.idle) >= redisMaxIdle {
+		conn.Close()
+		return
+	}
+	r.idle = append(r.idle, conn)
+}
+
+// redisError is an error reply from the server
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// redisConn is a connection speaking RESP, the Redis protocol
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// do writes a command and reads its reply. The connection is closed if ctx is
+// done first, which unblocks the read; it can't be reused after that.
+func (c *redisConn) do(ctx context.Context, args ...string) ([]byte, error) {
+	deadline, _ := ctx.Deadline()
+	c.SetDeadline(deadline)
+	stop := c.closeOnDone(ctx)
+	reply, err := c.roundTrip(args)
+	if stop() {
+		return nil, ctx.Err()
+	}
+	return reply, err
+}
+
+// closeOnDone closes the connection if ctx is done before the returned stop
+// function is called. stop reports whether the connection was closed.
+func (c *redisConn) closeOnDone(ctx context.Context) (stop func() bool) {
+	done := ctx.Done()
+	if done == nil {
+		return func() bool { return false }
+	}
+
+	finished := make(chan struct{})
+	closed := make(chan bool, 1)
+	go func() {
+		select {
+		case <-done:
+			c.Close()
+			closed <- true
+		case <-finished:
+			closed <- false
+		}
+	}()
+	return func() bool {
+		close(finished)
+		return <-closed
+	}
+}
+
+// roundTrip writes a command and reads its reply
+func (c *redisConn) roundTrip(args []string) ([]byte, error) {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	if _, err := c.Write(buf); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+// readReply reads one reply. Simple strings and integers are returned as their
+// text, and null bulk strings as nil.
+func (c *redisConn) readReply() ([]byte, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+', ':':
+		return []byte(body), nil
+	case '-':
+		return nil, redisError(body)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed reply %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	}
+	return nil, fmt.Errorf("unexpected reply %q", line)
+}
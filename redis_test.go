@@ -0,0 +1,187 @@
+package constellation_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+	"github.com/tanner-caffrey/constellation-go/constellationtest"
+)
+
+// newFakeRedis starts a server answering AUTH, SELECT, GET, SET, and DEL from
+// memory and returns its address
+func newFakeRedis(t *testing.T, password string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var mu sync.Mutex
+	values := make(map[string]string)
+
+	serve := func(conn net.Conn) {
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		authed := password == ""
+		for {
+			args, err := readCommand(reader)
+			if err != nil {
+				return
+			}
+
+			var reply string
+			switch cmd := strings.ToUpper(args[0]); {
+			case cmd == "AUTH":
+				authed = args[1] == password
+				reply = "+OK\r\n"
+				if !authed {
+					reply = "-WRONGPASS invalid password\r\n"
+				}
+			case !authed:
+				reply = "-NOAUTH Authentication required.\r\n"
+			case cmd == "SELECT":
+				reply = "+OK\r\n"
+			case cmd == "GET":
+				mu.Lock()
+				value, ok := values[args[1]]
+				mu.Unlock()
+				reply = "$-1\r\n"
+				if ok {
+					reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+				}
+			case cmd == "SET":
+				mu.Lock()
+				values[args[1]] = args[2]
+				mu.Unlock()
+				reply = "+OK\r\n"
+			case cmd == "DEL":
+				mu.Lock()
+				_, ok := values[args[1]]
+				delete(values, args[1])
+				mu.Unlock()
+				reply = ":0\r\n"
+				if ok {
+					reply = ":1\r\n"
+				}
+			default:
+				reply = "-ERR unknown command\r\n"
+			}
+			if _, err := io.WriteString(conn, reply); err != nil {
+				return
+			}
+		}
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// readCommand reads a command sent as an array of bulk strings
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+// TestRedisKV runs the store conformance checks against RedisKV
+func TestRedisKV(t *testing.T) {
+	store := constellation.NewRedisKV(newFakeRedis(t, "secret"))
+	store.Password = "secret"
+	store.DB = 2
+	store.Prefix = "test:"
+	defer store.Close()
+
+	constellationtest.TestKVStore(t, store)
+}
+
+// TestRedisKVAuthError tests that server errors are returned
+func TestRedisKVAuthError(t *testing.T) {
+	store := constellation.NewRedisKV(newFakeRedis(t, "secret"))
+	store.Password = "wrong"
+	defer store.Close()
+
+	_, err := store.Get(context.Background(), "key")
+	if err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("Expected an authentication error, got %v", err)
+	}
+}
+
+// TestRedisKVCancel tests that cancelling the context unblocks a read from a
+// server that never replies
+func TestRedisKVCancel(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			go io.Copy(io.Discard, conn)
+		}
+	}()
+
+	store := constellation.NewRedisKV(listener.Addr().String())
+	defer store.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := store.Get(ctx, "key")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Get didn't return after cancellation")
+	}
+}
@@ -0,0 +1,111 @@
+package constellation
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrResumeTokenMismatch is returned when a resume token is applied to parameters
+// other than the ones it was created for
+var ErrResumeTokenMismatch = errors.New("resume token does not match query parameters")
+
+// ResumeToken records a pagination position bound to the query that produced it,
+// so a long export can resume after a restart without replaying a cursor against
+// different parameters
+type ResumeToken struct {
+	Cursor     string    `json:"cursor"`
+	ParamsHash string    `json:"params_hash"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewResumeToken creates a token for resuming the query described by params at cursor
+func NewResumeToken(params LinksParams, cursor string) ResumeToken {
+	return ResumeToken{
+		Cursor:     cursor,
+		ParamsHash: paramsFingerprint(params),
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+// paramsFingerprint hashes the parameters that identify a query, excluding the
+// page size and position
+func paramsFingerprint(params LinksParams) string {
+	return hashParams(params.queryValues(false))
+}
+
+// Matches reports whether the token was created for params
+func (t ResumeToken) Matches(params LinksParams) bool {
+	return t.ParamsHash == paramsFingerprint(params)
+}
+
+// Apply returns params positioned at the token's cursor, or ErrResumeTokenMismatch
+// if the token belongs to a different query
+func (t ResumeToken) Apply(params LinksParams) (LinksParams, error) {
+	if !t.Matches(params) {
+		return params, ErrResumeTokenMismatch
+	}
+	params.Cursor = t.Cursor
+	return params, nil
+}
+
+// Encode serializes the token to an opaque URL-safe string
+func (t ResumeToken) Encode() string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeResumeToken parses a token produced by Encode
+func DecodeResumeToken(encoded string) (ResumeToken, error) {
+	var token ResumeToken
+
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return token, fmt.Errorf("invalid resume token: %w", err)
+	}
+	if err := json.Unmarshal(data, &token); err != nil {
+		return token, fmt.Errorf("invalid resume token: %w", err)
+	}
+	return token, nil
+}
+
+// SaveResumeToken writes a token to a file atomically
+func SaveResumeToken(path string, token ResumeToken) error {
+	if err := writeFileAtomic(path, []byte(token.Encode()+"\n")); err != nil {
+		return fmt.Errorf("failed to save resume token: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file beside path and renames it into
+// place, so readers never see a partial write
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadResumeToken reads a token saved with SaveResumeToken
+func LoadResumeToken(path string) (ResumeToken, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ResumeToken{}, fmt.Errorf("failed to load resume token: %w", err)
+	}
+	return DecodeResumeToken(string(data))
+}
@@ -0,0 +1,43 @@
+package constellation_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestResumeToken tests saving, loading, and applying resume tokens
+func TestResumeToken(t *testing.T) {
+	params := constellation.LinksParams{
+		Target:     "at://did:plc:example/app.bsky.feed.post/example",
+		Collection: "app.bsky.feed.like",
+		Path:       ".subject.uri",
+		Limit:      100,
+	}
+
+	path := filepath.Join(t.TempDir(), "export.resume")
+	if err := constellation.SaveResumeToken(path, constellation.NewResumeToken(params, "cursor-42")); err != nil {
+		t.Fatalf("Failed to save resume token: %v", err)
+	}
+
+	token, err := constellation.LoadResumeToken(path)
+	if err != nil {
+		t.Fatalf("Failed to load resume token: %v", err)
+	}
+
+	resumed, err := token.Apply(params)
+	if err != nil {
+		t.Fatalf("Failed to apply resume token: %v", err)
+	}
+	if resumed.Cursor != "cursor-42" {
+		t.Errorf("Expected cursor 'cursor-42', got '%s'", resumed.Cursor)
+	}
+
+	other := params
+	other.Collection = "app.bsky.feed.repost"
+	if _, err := token.Apply(other); !errors.Is(err, constellation.ErrResumeTokenMismatch) {
+		t.Errorf("Expected ErrResumeTokenMismatch for different params, got: %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package constellation
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ScrubOptions selects records to remove from stored data
+type ScrubOptions struct {
+	DIDs      []string      // Remove records authored by these DIDs, e.g. on a deletion request
+	OlderThan time.Duration // Remove records created more than this long ago; zero keeps all ages
+}
+
+// matcher returns a function reporting whether a record should be removed
+func (opts ScrubOptions) matcher() func(LinkRecord) bool {
+	dids := make(map[string]bool, len(opts.DIDs))
+	for _, did := range opts.DIDs {
+		dids[did] = true
+	}
+	cutoff := time.Now().Add(-opts.OlderThan)
+
+	return func(record LinkRecord) bool {
+		if dids[record.DID] {
+			return true
+		}
+		if opts.OlderThan > 0 {
+			if createdAt, ok := recordTime(record); ok && createdAt.Before(cutoff) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// recordTime returns when a record was indexed, falling back to its TID record key
+func recordTime(record LinkRecord) (time.Time, bool) {
+	if indexedAt, err := time.Parse(time.RFC3339, record.IndexedAt); err == nil {
+		return indexedAt, true
+	}
+	return tidTime(record.RKey)
+}
+
+// ScrubRecords returns the records not selected by opts
+func ScrubRecords(records []LinkRecord, opts ScrubOptions) []LinkRecord {
+	remove := opts.matcher()
+
+	kept := make([]LinkRecord, 0, len(records))
+	for _, record := range records {
+		if !remove(record) {
+			kept = append(kept, record)
+		}
+	}
+	return kept
+}
+
+// ScrubJSONL copies a JSON Lines export of LinkRecords from r to w, dropping the
+// records selected by opts, and returns the number of records removed
+func ScrubJSONL(r io.Reader, w io.Writer, opts ScrubOptions) (int, error) {
+	remove := opts.matcher()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	removed := 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+
+		var record LinkRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return removed, fmt.Errorf("failed to decode export line: %w", err)
+		}
+		if remove(record) {
+			removed++
+			continue
+		}
+
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return removed, fmt.Errorf("failed to write export line: %w", err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return removed, fmt.Errorf("failed to read export: %w", err)
+	}
+	return removed, nil
+}
+
+// ScrubJSONLFile rewrites a JSON Lines export file in place without the records
+// selected by opts. The file is replaced atomically, so a failure leaves the
+// original intact.
+func ScrubJSONLFile(path string, opts ScrubOptions) (int, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open export: %w", err)
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat export: %w", err)
+	}
+
+	out, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".scrub-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temporary export: %w", err)
+	}
+	defer os.Remove(out.Name())
+
+	// CreateTemp makes files readable only by their owner; keep the export's mode
+	if err := out.Chmod(info.Mode().Perm()); err != nil {
+		out.Close()
+		return 0, fmt.Errorf("failed to set export permissions: %w", err)
+	}
+
+	removed, err := ScrubJSONL(in, out, opts)
+	if err != nil {
+		out.Close()
+		return removed, err
+	}
+	if err := out.Close(); err != nil {
+		return removed, fmt.Errorf("failed to write export: %w", err)
+	}
+
+	if err := os.Rename(out.Name(), path); err != nil {
+		return removed, fmt.Errorf("failed to replace export: %w", err)
+	}
+	return removed, nil
+}
+
+// Purge removes cache entries whose key matches and returns how many were removed
+func (m *MemoryCache) Purge(match func(key string) bool) int {
+	return m.PurgeEntries(func(key string, _ []byte) bool { return match(key) })
+}
+
+// PurgeEntries removes cache entries whose key and value match and returns how
+// many were removed
+func (m *MemoryCache) PurgeEntries(match func(key string, value []byte) bool) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+	for key, elem := range m.entries {
+		if match(key, elem.Value.(*memoryEntry).data) {
+			m.order.Remove(elem)
+			delete(m.entries, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// PurgeExpired removes expired cache entries and returns how many were removed
+func (m *MemoryCache) PurgeExpired() int {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+	for key, elem := range m.entries {
+		if now.After(elem.Value.(*memoryEntry).expires) {
+			m.order.Remove(elem)
+			delete(m.entries, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// KeyMentionsDID reports whether a cache key built by CountKey, MembershipKey, or
+// LinkersKey queries a target belonging to did or filters by did, for use with
+// MemoryCache.Purge
+func KeyMentionsDID(key, did string) bool {
+	_, query, _ := strings.Cut(key, "?")
+	return queryMentionsDID(query, did)
+}
+
+// EntryMentionsDID reports whether a cache entry's key mentions did, as
+// KeyMentionsDID, or its value does. Membership and linker values list the DIDs
+// and record URIs found, so they can mention DIDs their keys don't. Use it with
+// MemoryCache.PurgeEntries.
+func EntryMentionsDID(key string, value []byte, did string) bool {
+	if KeyMentionsDID(key, did) {
+		return true
+	}
+	for _, field := range strings.Fields(string(value)) {
+		if ownedBy(field, did) {
+			return true
+		}
+	}
+	return false
+}
+
+// ManifestMentionsDID reports whether a mirrored request queried a target
+// belonging to did or filtered by did. The mirror can only add objects, so
+// deleting matching manifests is left to the store; see Mirror.
+func ManifestMentionsDID(m MirrorManifest, did string) bool {
+	return queryMentionsDID(m.Query, did)
+}
+
+// queryMentionsDID reports whether an encoded query's target or did parameter
+// belongs to did
+func queryMentionsDID(query, did string) bool {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return false
+	}
+	return ownedBy(values.Get("target"), did) || ownedBy(values.Get("did"), did)
+}
+
+// ownedBy reports whether value is did or an AT URI in its repository
+func ownedBy(value, did string) bool {
+	return value == did || strings.HasPrefix(value, "at://"+did+"/")
+}
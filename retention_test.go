@@ -0,0 +1,113 @@
+package constellation_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestScrubJSONLFile tests removing a DID's records from an export file
+func TestScrubJSONLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.jsonl")
+
+	var lines []string
+	for _, did := range []string{"did:plc:keep", "did:plc:remove", "did:plc:keep"} {
+		line, _ := json.Marshal(constellation.LinkRecord{DID: did, RKey: "3lgwdn7vd722r"})
+		lines = append(lines, string(line))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := constellation.ScrubJSONLFile(path, constellation.ScrubOptions{DIDs: []string{"did:plc:remove"}})
+	if err != nil {
+		t.Fatalf("Failed to scrub export: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Expected 1 record removed, got %d", removed)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "did:plc:remove") {
+		t.Error("Expected scrubbed DID to be absent from export")
+	}
+	if strings.Count(string(data), "did:plc:keep") != 2 {
+		t.Error("Expected other records to be kept")
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != 0o644 {
+		t.Errorf("Expected the export to keep mode 0644, got %v", info.Mode().Perm())
+	}
+}
+
+// TestScrubRecordsOlderThan tests age-based scrubbing using TID record keys
+func TestScrubRecordsOlderThan(t *testing.T) {
+	records := []constellation.LinkRecord{
+		{DID: "did:plc:old", RKey: "3lgwdn7vd722r"}, // January 2025
+		{DID: "did:plc:unknown", RKey: "self"},
+	}
+
+	kept := constellation.ScrubRecords(records, constellation.ScrubOptions{OlderThan: 24 * time.Hour})
+	if len(kept) != 1 || kept[0].DID != "did:plc:unknown" {
+		t.Errorf("Expected only the record without a timestamp to be kept, got %+v", kept)
+	}
+}
+
+// TestKeyMentionsDID tests matching cache keys against a DID
+func TestKeyMentionsDID(t *testing.T) {
+	key := constellation.CountKey(constellation.LinksParams{
+		Target: "at://did:plc:example/app.bsky.feed.post/example",
+	}, false)
+
+	if !constellation.KeyMentionsDID(key, "did:plc:example") {
+		t.Error("Expected key for a record owned by the DID to match")
+	}
+	if constellation.KeyMentionsDID(key, "did:plc:other") {
+		t.Error("Expected key not to match another DID")
+	}
+
+	member := constellation.MembershipKey(constellation.LinksParams{
+		Target:  "at://did:plc:other/app.bsky.feed.post/example",
+		FromDID: "did:plc:example",
+	})
+	if !constellation.KeyMentionsDID(member, "did:plc:example") {
+		t.Error("Expected membership key filtering by the DID to match")
+	}
+}
+
+// TestEntryMentionsDID tests matching cache values that list DIDs
+func TestEntryMentionsDID(t *testing.T) {
+	cache := constellation.NewMemoryCache(time.Minute, constellation.GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return []byte("complete\ndid:plc:example at://did:plc:example/app.bsky.graph.listitem/1\n"), nil
+	}))
+	key := constellation.LinkersKey(constellation.LinksParams{Target: "at://did:plc:other/app.bsky.graph.list/1"})
+	if _, err := cache.Get(context.Background(), key); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	if constellation.KeyMentionsDID(key, "did:plc:example") {
+		t.Error("Expected the linkers key not to mention the DID")
+	}
+	if removed := cache.PurgeEntries(func(key string, value []byte) bool {
+		return constellation.EntryMentionsDID(key, value, "did:plc:unrelated")
+	}); removed != 0 {
+		t.Errorf("Expected no entries removed for an unrelated DID, got %d", removed)
+	}
+	if removed := cache.PurgeEntries(func(key string, value []byte) bool {
+		return constellation.EntryMentionsDID(key, value, "did:plc:example")
+	}); removed != 1 {
+		t.Errorf("Expected the linkers entry to be removed, got %d", removed)
+	}
+
+	manifest := constellation.MirrorManifest{Query: "did=did%3Aplc%3Aexample&target=at%3A%2F%2Fdid%3Aplc%3Aother%2Fapp.bsky.graph.list%2F1"}
+	if !constellation.ManifestMentionsDID(manifest, "did:plc:example") {
+		t.Error("Expected the manifest to mention the DID")
+	}
+}
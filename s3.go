@@ -0,0 +1,159 @@
+package constellation
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Store is an ObjectStore for S3-compatible storage (AWS S3, R2, MinIO, and
+// others) using path-style URLs and Signature Version 4
+type S3Store struct {
+	Endpoint        string // e.g. "https://s3.us-east-1.amazonaws.com"
+	Bucket          string
+	Region          string // Defaults to "us-east-1"
+	AccessKeyID     string
+	SecretAccessKey string
+	HTTPClient      *http.Client // Defaults to http.DefaultClient
+}
+
+// PutObject uploads data to key, storing metadata as x-amz-meta-* headers
+func (s *S3Store) PutObject(ctx context.Context, key string, data []byte, metadata map[string]string) error {
+	return s.put(ctx, key, data, "application/json", metadata)
+}
+
+// PutBlob uploads everything read from r to name. Signature Version 4 signs the
+// payload hash, so the blob is buffered in memory before uploading.
+func (s *S3Store) PutBlob(ctx context.Context, name string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read blob: %w", err)
+	}
+	return s.put(ctx, name, data, "application/x-ndjson", nil)
+}
+
+// put uploads data to key with the given content type and metadata
+func (s *S3Store) put(ctx context.Context, key string, data []byte, contentType string, metadata map[string]string) error {
+	objectURL, err := url.Parse(strings.TrimSuffix(s.Endpoint, "/"))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	// Escape each segment of the key, so names with spaces, '?', '#', or '%'
+	// address the object they name, and sign the same escaped path
+	objectURL.RawPath = objectURL.EscapedPath() + "/" + awsEscapePath(s.Bucket) + "/" + awsEscapePath(key)
+	objectURL.Path += "/" + s.Bucket + "/" + key
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range metadata {
+		req.Header.Set("X-Amz-Meta-"+name, value)
+	}
+	s.sign(req, data, time.Now().UTC())
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return nil
+}
+
+// sign adds a Signature Version 4 Authorization header to req
+func (s *S3Store) sign(req *http.Request, payload []byte, now time.Time) {
+	region := s.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsEscapePath(req.URL.Path),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// awsEscapePath percent-encodes every byte of path except unreserved characters
+// and slashes, as Signature Version 4 requires
+func awsEscapePath(path string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(path); i++ {
+		b := path[i]
+		if 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z' || '0' <= b && b <= '9' ||
+			b == '-' || b == '.' || b == '_' || b == '~' || b == '/' {
+			escaped.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(&escaped, "%%%02X", b)
+	}
+	return escaped.String()
+}
+
+// sha256Hex returns the hex SHA-256 of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data under key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
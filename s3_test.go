@@ -0,0 +1,100 @@
+package constellation_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestS3StorePutObject tests the request sent to S3-compatible storage
+func TestS3StorePutObject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/corpus/sha256/ab/abc.json" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/us-west-2/s3/aws4_request") {
+			t.Errorf("Unexpected Authorization %q", auth)
+		}
+		if !strings.Contains(auth, "x-amz-meta-endpoint") {
+			t.Errorf("Expected metadata to be signed, got %q", auth)
+		}
+		if r.Header.Get("X-Amz-Meta-Endpoint") != "/links" {
+			t.Errorf("Expected endpoint metadata, got %v", r.Header)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"total": 1}` {
+			t.Errorf("Unexpected body %q", body)
+		}
+	}))
+	defer server.Close()
+
+	store := &constellation.S3Store{
+		Endpoint:        server.URL,
+		Bucket:          "corpus",
+		Region:          "us-west-2",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+	}
+	err := store.PutObject(context.Background(), "sha256/ab/abc.json", []byte(`{"total": 1}`), map[string]string{"endpoint": "/links"})
+	if err != nil {
+		t.Fatalf("Failed to put object: %v", err)
+	}
+}
+
+// TestS3StoreEscapesKey tests that key segments are escaped rather than
+// parsed as a query or fragment
+func TestS3StoreEscapesKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/corpus/exports/a b?c#d%e.jsonl" || r.URL.RawQuery != "" {
+			t.Errorf("Unexpected request path %q, query %q", r.URL.Path, r.URL.RawQuery)
+		}
+		if r.URL.EscapedPath() != "/corpus/exports/a%20b%3Fc%23d%25e.jsonl" {
+			t.Errorf("Unexpected escaped path %q", r.URL.EscapedPath())
+		}
+	}))
+	defer server.Close()
+
+	store := &constellation.S3Store{Endpoint: server.URL, Bucket: "corpus"}
+	if err := store.PutObject(context.Background(), "exports/a b?c#d%e.jsonl", nil, nil); err != nil {
+		t.Fatalf("Failed to put object: %v", err)
+	}
+}
+
+// TestS3StoreError tests that rejected uploads return an APIError
+func TestS3StoreError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "denied", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	store := &constellation.S3Store{Endpoint: server.URL, Bucket: "corpus"}
+	err := store.PutObject(context.Background(), "key", nil, nil)
+	if apiErr, ok := err.(*constellation.APIError); !ok || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected a 403 APIError, got %v", err)
+	}
+}
+
+// TestS3StorePutBlob tests uploading an export to S3-compatible storage
+func TestS3StorePutBlob(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/lake/exports/likes.jsonl" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+			t.Errorf("Unexpected request %s with %v", r.URL.Path, r.Header)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "{}\n" {
+			t.Errorf("Unexpected body %q", body)
+		}
+	}))
+	defer server.Close()
+
+	store := &constellation.S3Store{Endpoint: server.URL, Bucket: "lake", AccessKeyID: "AKID", SecretAccessKey: "secret"}
+	if err := store.PutBlob(context.Background(), "exports/likes.jsonl", strings.NewReader("{}\n")); err != nil {
+		t.Fatalf("Failed to put blob: %v", err)
+	}
+}
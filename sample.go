@@ -0,0 +1,41 @@
+package constellation
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// SampleDIDs returns a uniform random sample of n distinct DIDs linking to
+// params.Target, for surveys over likers or followers. It pages through every DID
+// once, keeping a reservoir of n, so memory stays bounded on large targets. The
+// same seed gives the same sample as long as the linking DIDs don't change.
+// Fewer than n DIDs are returned when fewer link to the target.
+func (c *Client) SampleDIDs(ctx context.Context, params LinksParams, n int, seed int64) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	sample := make([]string, 0, n)
+	seen := 0
+	err := c.GetDistinctDIDsEach(ctx, params, PaginateOptions{}, func(did string) error {
+		seen++
+		if len(sample) < n {
+			sample = append(sample, did)
+		} else if i := rng.Intn(seen); i < n {
+			sample[i] = did
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sample, nil
+}
+
+// SampleLinkingDIDs is SampleDIDs with a fresh random seed, for one-off samples
+// that don't need to be reproduced
+func (c *Client) SampleLinkingDIDs(ctx context.Context, params LinksParams, n int) ([]string, error) {
+	return c.SampleDIDs(ctx, params, n, time.Now().UnixNano())
+}
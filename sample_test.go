@@ -0,0 +1,68 @@
+package constellation_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestSampleDIDs tests that samples are sized, distinct, and reproducible by seed
+func TestSampleDIDs(t *testing.T) {
+	server := newPagedDIDServer(t, 50)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+	ctx := context.Background()
+
+	first, err := client.SampleDIDs(ctx, params, 5, 42)
+	if err != nil {
+		t.Fatalf("SampleDIDs failed: %v", err)
+	}
+	if len(first) != 5 || len(constellation.NewDIDSet(first...)) != 5 {
+		t.Fatalf("Expected 5 distinct DIDs, got %v", first)
+	}
+
+	again, err := client.SampleDIDs(ctx, params, 5, 42)
+	if err != nil {
+		t.Fatalf("SampleDIDs failed: %v", err)
+	}
+	if !reflect.DeepEqual(first, again) {
+		t.Errorf("Expected the same seed to give the same sample, got %v and %v", first, again)
+	}
+
+	other, err := client.SampleDIDs(ctx, params, 5, 7)
+	if err != nil {
+		t.Fatalf("SampleDIDs failed: %v", err)
+	}
+	if reflect.DeepEqual(first, other) {
+		t.Errorf("Expected different seeds to give different samples, got %v", other)
+	}
+
+	all, err := client.SampleDIDs(ctx, params, 100, 42)
+	if err != nil {
+		t.Fatalf("SampleDIDs failed: %v", err)
+	}
+	if len(all) != 50 {
+		t.Errorf("Expected every DID when n exceeds the total, got %d", len(all))
+	}
+}
+
+// TestSampleLinkingDIDs tests unseeded sampling across pages
+func TestSampleLinkingDIDs(t *testing.T) {
+	server := newPagedDIDServer(t, 30)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	sample, err := client.SampleLinkingDIDs(context.Background(), params, 8)
+	if err != nil {
+		t.Fatalf("SampleLinkingDIDs failed: %v", err)
+	}
+	if len(sample) != 8 || len(constellation.NewDIDSet(sample...)) != 8 {
+		t.Errorf("Expected 8 distinct DIDs, got %v", sample)
+	}
+	if client.Metrics().Requests != 3 {
+		t.Errorf("Expected every page to be sampled, got %d requests", client.Metrics().Requests)
+	}
+}
@@ -0,0 +1,76 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+)
+
+// ScoreWeights weights each engagement signal in Score
+type ScoreWeights struct {
+	Likes      float64
+	Reposts    float64
+	Quotes     float64
+	UniqueDIDs float64 // Distinct accounts liking the post
+}
+
+// DefaultScoreWeights are used by Score when all weights are zero. Reposts and
+// quotes count for more than likes since they spread the post further.
+var DefaultScoreWeights = ScoreWeights{
+	Likes:      1,
+	Reposts:    2,
+	Quotes:     3,
+	UniqueDIDs: 0.5,
+}
+
+// Score computes a weighted engagement score for a post:
+//
+//	Likes*likes + Reposts*reposts + Quotes*quotes + UniqueDIDs*distinct likers
+//
+// Counts are fetched concurrently, and only those with a non-zero weight are
+// fetched. A zero ScoreWeights uses DefaultScoreWeights.
+func (c *Client) Score(ctx context.Context, postURI string, weights ScoreWeights) (float64, error) {
+	if weights == (ScoreWeights{}) {
+		weights = DefaultScoreWeights
+	}
+
+	var likes, reposts, quotes, uniqueDIDs int
+	var fns []func() error
+	if weights.Likes != 0 {
+		fns = append(fns, func() error {
+			count, err := c.GetLinksCountContext(ctx, likesParams(postURI))
+			if err == nil {
+				likes = count.Total
+			}
+			return err
+		})
+	}
+	if weights.Reposts != 0 {
+		fns = append(fns, func() error {
+			count, err := c.GetLinksCountContext(ctx, repostsParams(postURI))
+			if err == nil {
+				reposts = count.Total
+			}
+			return err
+		})
+	}
+	if weights.Quotes != 0 {
+		fns = append(fns, func() (err error) {
+			quotes, err = c.QuoteCount(ctx, postURI)
+			return err
+		})
+	}
+	if weights.UniqueDIDs != 0 {
+		fns = append(fns, func() (err error) {
+			uniqueDIDs, err = c.GetDistinctDIDsCountContext(ctx, likesParams(postURI))
+			return err
+		})
+	}
+	if err := errors.Join(fanOut(fns...)...); err != nil {
+		return 0, err
+	}
+
+	return weights.Likes*float64(likes) +
+		weights.Reposts*float64(reposts) +
+		weights.Quotes*float64(quotes) +
+		weights.UniqueDIDs*float64(uniqueDIDs), nil
+}
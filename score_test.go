@@ -0,0 +1,83 @@
+package constellation_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// newScoreServer answers counts for a post with 10 likes from 8 accounts,
+// 4 reposts, and 3 quotes, recording the collections queried
+func newScoreServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var queried []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		key := query.Get("collection") + " " + query.Get("path")
+		if strings.HasSuffix(r.URL.Path, "/distinct-dids") {
+			key = "distinct " + key
+		}
+		mu.Lock()
+		queried = append(queried, key)
+		mu.Unlock()
+
+		totals := map[string]int{
+			"app.bsky.feed.like .subject.uri":             10,
+			"distinct app.bsky.feed.like .subject.uri":    8,
+			"app.bsky.feed.repost .subject.uri":           4,
+			"app.bsky.feed.post .embed.record.uri":        2,
+			"app.bsky.feed.post .embed.record.record.uri": 1,
+		}
+		fmt.Fprintf(w, `{"total": %d}`, totals[key])
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return queried
+	}
+}
+
+// TestScore tests weighted scores with default and custom weights
+func TestScore(t *testing.T) {
+	server, _ := newScoreServer(t)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	ctx := context.Background()
+
+	score, err := client.Score(ctx, "at://did:plc:creator/app.bsky.feed.post/1", constellation.ScoreWeights{})
+	if err != nil {
+		t.Fatalf("Failed to score: %v", err)
+	}
+	if want := 10*1 + 4*2 + 3*3 + 8*0.5; score != want {
+		t.Errorf("Expected default score %v, got %v", want, score)
+	}
+
+	score, err = client.Score(ctx, "at://did:plc:creator/app.bsky.feed.post/1", constellation.ScoreWeights{Likes: 0.5, Quotes: 1})
+	if err != nil {
+		t.Fatalf("Failed to score: %v", err)
+	}
+	if want := 10*0.5 + 3*1.0; score != want {
+		t.Errorf("Expected custom score %v, got %v", want, score)
+	}
+}
+
+// TestScoreSkipsZeroWeights tests that counts with a zero weight aren't fetched
+func TestScoreSkipsZeroWeights(t *testing.T) {
+	server, queried := newScoreServer(t)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+
+	if _, err := client.Score(context.Background(), "at://did:plc:creator/app.bsky.feed.post/1", constellation.ScoreWeights{Reposts: 1}); err != nil {
+		t.Fatalf("Failed to score: %v", err)
+	}
+	if got := queried(); len(got) != 1 || got[0] != "app.bsky.feed.repost .subject.uri" {
+		t.Errorf("Expected only the repost count to be fetched, got %v", got)
+	}
+}
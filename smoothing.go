@@ -0,0 +1,70 @@
+package constellation
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultEMAHalfLife is the half-life used by an EMA with no HalfLife set
+const DefaultEMAHalfLife = 10 * time.Minute
+
+// EMA is an exponential moving average of a count sampled at irregular
+// intervals, such as by an adaptive Watcher, together with its smoothed rate of
+// change. A sample's weight halves every HalfLife, however many samples arrive in
+// that time. The zero value is ready to use.
+type EMA struct {
+	HalfLife time.Duration
+
+	smoothed float64
+	rate     float64
+	last     float64
+	lastAt   time.Time
+	samples  int
+}
+
+// SmoothedPoint is a sample of a count with its smoothed value and rate
+type SmoothedPoint struct {
+	At       time.Time
+	Raw      float64
+	Smoothed float64
+	Rate     float64 // Smoothed change per second; zero until two samples are seen
+}
+
+// Add records value sampled at at and returns the updated smoothed point.
+// Samples at or before the previous one are ignored.
+func (e *EMA) Add(value float64, at time.Time) SmoothedPoint {
+	switch {
+	case e.samples == 0:
+		e.smoothed = value
+	case !at.After(e.lastAt):
+		return e.Point()
+	default:
+		elapsed := at.Sub(e.lastAt)
+		alpha := e.alpha(elapsed)
+		instant := (value - e.last) / elapsed.Seconds()
+		if e.samples == 1 {
+			e.rate = instant
+		} else {
+			e.rate += alpha * (instant - e.rate)
+		}
+		e.smoothed += alpha * (value - e.smoothed)
+	}
+	e.last = value
+	e.lastAt = at
+	e.samples++
+	return e.Point()
+}
+
+// Point returns the latest smoothed point
+func (e *EMA) Point() SmoothedPoint {
+	return SmoothedPoint{At: e.lastAt, Raw: e.last, Smoothed: e.smoothed, Rate: e.rate}
+}
+
+// alpha returns the weight of a sample arriving elapsed after the previous one
+func (e *EMA) alpha(elapsed time.Duration) float64 {
+	halfLife := e.HalfLife
+	if halfLife <= 0 {
+		halfLife = DefaultEMAHalfLife
+	}
+	return 1 - math.Exp(-math.Ln2*elapsed.Seconds()/halfLife.Seconds())
+}
@@ -0,0 +1,57 @@
+package constellation_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestEMA tests half-life weighting over irregular intervals and rate smoothing
+func TestEMA(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ema := constellation.EMA{HalfLife: time.Minute}
+
+	if point := ema.Add(0, start); point.Smoothed != 0 || point.Rate != 0 {
+		t.Errorf("Expected the first sample to seed the average, got %+v", point)
+	}
+
+	// A step held for one half-life moves the average halfway
+	point := ema.Add(100, start.Add(time.Minute))
+	if math.Abs(point.Smoothed-50) > 1e-9 {
+		t.Errorf("Expected 50 after one half-life, got %v", point.Smoothed)
+	}
+	if math.Abs(point.Rate-100.0/60) > 1e-9 {
+		t.Errorf("Expected the first rate to be the instant rate, got %v", point.Rate)
+	}
+
+	// Two samples 30s apart weigh the same as one sample a minute later
+	split := constellation.EMA{HalfLife: time.Minute}
+	split.Add(0, start)
+	split.Add(100, start.Add(30*time.Second))
+	if got := split.Add(100, start.Add(time.Minute)).Smoothed; math.Abs(got-50) > 1e-9 {
+		t.Errorf("Expected irregular samples to smooth to 50, got %v", got)
+	}
+
+	// Stale samples are ignored
+	if stale := ema.Add(1000, start); stale != point {
+		t.Errorf("Expected a stale sample to be ignored, got %+v", stale)
+	}
+}
+
+// TestEMASteadyRate tests that a steadily growing count has a steady rate
+func TestEMASteadyRate(t *testing.T) {
+	start := time.Now()
+	var ema constellation.EMA
+	var point constellation.SmoothedPoint
+	for i := 0; i < 20; i++ {
+		point = ema.Add(float64(i*30), start.Add(time.Duration(i)*time.Minute))
+	}
+	if math.Abs(point.Rate-0.5) > 1e-9 {
+		t.Errorf("Expected 0.5 per second, got %v", point.Rate)
+	}
+	if point.Raw != 570 || point.Smoothed >= point.Raw {
+		t.Errorf("Expected the average to lag a growing count, got %+v", point)
+	}
+}
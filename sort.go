@@ -0,0 +1,184 @@
+package constellation
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// SortOrder selects a client-side ordering for fetched records. The API's own
+// ordering isn't configurable.
+type SortOrder int
+
+const (
+	SortNone          SortOrder = iota // Keep the server's ordering
+	SortIndexedAtAsc                   // Oldest IndexedAt first
+	SortIndexedAtDesc                  // Newest IndexedAt first
+	SortRKeyAsc                        // Oldest record first, by the time in TID rkeys
+	SortRKeyDesc                       // Newest record first, by the time in TID rkeys
+)
+
+// DefaultSortWindow is the number of records buffered when sorting streamed results
+const DefaultSortWindow = 1000
+
+// SortRecords sorts records in place. The sort is stable, so records that compare
+// equal keep the server's ordering.
+func SortRecords(records []LinkRecord, order SortOrder) {
+	if order == SortNone {
+		return
+	}
+	items := make([]sortItem, len(records))
+	for i, record := range records {
+		items[i] = newSortItem(record, order, i)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].less(items[j], order)
+	})
+	for i, item := range items {
+		records[i] = item.record
+	}
+}
+
+// descending reports whether order puts newer records first
+func (o SortOrder) descending() bool {
+	return o == SortIndexedAtDesc || o == SortRKeyDesc
+}
+
+// sortItem is a record with its sort key parsed once up front, and its arrival
+// position to keep sorting stable
+type sortItem struct {
+	record LinkRecord
+	raw    string    // IndexedAt or rkey, compared as a string when not parsed
+	time   time.Time // Parsed from raw
+	parsed bool
+	seq    int
+}
+
+// newSortItem parses the key order sorts record by
+func newSortItem(record LinkRecord, order SortOrder, seq int) sortItem {
+	item := sortItem{record: record, seq: seq}
+	switch order {
+	case SortIndexedAtAsc, SortIndexedAtDesc:
+		item.raw = record.IndexedAt
+		t, err := time.Parse(time.RFC3339, record.IndexedAt)
+		item.time, item.parsed = t, err == nil
+	case SortRKeyAsc, SortRKeyDesc:
+		item.raw = record.RKey
+		item.time, item.parsed = tidTime(record.RKey)
+	}
+	return item
+}
+
+// less reports whether a sorts before b in order. Keys are compared as times
+// when both parsed, and as strings otherwise; ties keep arrival order.
+func (a sortItem) less(b sortItem, order SortOrder) bool {
+	cmp := compareStrings(a.raw, b.raw)
+	if a.parsed && b.parsed {
+		cmp = a.time.Compare(b.time)
+	}
+	if order.descending() {
+		cmp = -cmp
+	}
+	if cmp != 0 {
+		return cmp < 0
+	}
+	return a.seq < b.seq
+}
+
+// compareStrings returns -1, 0, or 1 as a sorts before, equal to, or after b
+func compareStrings(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// sortHeap is a min-heap of records in sort order
+type sortHeap struct {
+	order SortOrder
+	items []sortItem
+}
+
+func (h *sortHeap) Len() int           { return len(h.items) }
+func (h *sortHeap) Less(i, j int) bool { return h.items[i].less(h.items[j], h.order) }
+func (h *sortHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *sortHeap) Push(x any)         { h.items = append(h.items, x.(sortItem)) }
+func (h *sortHeap) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// sortWindow buffers streamed records and releases them in order once more than
+// size records are buffered. Output is exactly sorted when records arrive at most
+// size positions out of place.
+type sortWindow struct {
+	size    int
+	heap    sortHeap
+	seq     int
+	emit    func(LinkRecord) error
+	stopped bool // emit asked to stop, so nothing more may be emitted
+}
+
+// newSortWindow creates a window emitting to emit, sized by opts.SortWindow
+func newSortWindow(opts PaginateOptions, emit func(LinkRecord) error) *sortWindow {
+	size := opts.SortWindow
+	if size <= 0 {
+		size = DefaultSortWindow
+	}
+	return &sortWindow{size: size, heap: sortHeap{order: opts.Sort}, emit: emit}
+}
+
+// add buffers a record, emitting the first record in order if the window is full
+func (w *sortWindow) add(record LinkRecord) error {
+	heap.Push(&w.heap, newSortItem(record, w.heap.order, w.seq))
+	w.seq++
+	if w.heap.Len() <= w.size {
+		return nil
+	}
+	return w.emitFirst()
+}
+
+// flush emits the buffered records in order
+func (w *sortWindow) flush() error {
+	for w.heap.Len() > 0 && !w.stopped {
+		if err := w.emitFirst(); err != nil {
+			if errors.Is(err, errStopPagination) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// emitFirst removes the first buffered record in order and emits it
+func (w *sortWindow) emitFirst() error {
+	item := heap.Pop(&w.heap).(sortItem)
+	err := w.emit(item.record)
+	if errors.Is(err, errStopPagination) {
+		w.stopped = true
+	}
+	return err
+}
+
+// eachLink paginates records linking to a target, applying opts.Sort within a
+// sliding window
+func (c *Client) eachLink(ctx context.Context, params LinksParams, opts PaginateOptions, emit func(LinkRecord) error) error {
+	if opts.Sort == SortNone {
+		return paginate(ctx, opts, params.Cursor, c.linksFetcher(params), emit)
+	}
+
+	// paginate swallows the stop sentinel, so the window remembers it to keep
+	// flush from emitting after the caller has stopped
+	window := newSortWindow(opts, emit)
+	if err := paginate(ctx, opts, params.Cursor, c.linksFetcher(params), window.add); err != nil {
+		return err
+	}
+	return window.flush()
+}
@@ -0,0 +1,57 @@
+package constellation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestSortRecords tests client-side record ordering
+func TestSortRecords(t *testing.T) {
+	records := []constellation.LinkRecord{
+		{RKey: "3lgwdn7vd722r", IndexedAt: "2025-01-02T00:00:00Z"},
+		{RKey: "3kaaaaaaaaaaa", IndexedAt: "2025-01-03T00:00:00Z"},
+		{RKey: "3mzzzzzzzzzzz", IndexedAt: "2025-01-01T00:00:00Z"},
+	}
+
+	constellation.SortRecords(records, constellation.SortIndexedAtAsc)
+	if records[0].IndexedAt != "2025-01-01T00:00:00Z" || records[2].IndexedAt != "2025-01-03T00:00:00Z" {
+		t.Errorf("Expected ascending IndexedAt, got %+v", records)
+	}
+
+	constellation.SortRecords(records, constellation.SortRKeyDesc)
+	if records[0].RKey != "3mzzzzzzzzzzz" || records[2].RKey != "3kaaaaaaaaaaa" {
+		t.Errorf("Expected descending rkey time, got %+v", records)
+	}
+}
+
+// TestLinksSortWindow tests windowed sorting of streamed records
+func TestLinksSortWindow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"linking_records": [{"rkey": "3"}, {"rkey": "1"}], "cursor": "next"}`))
+			return
+		}
+		w.Write([]byte(`{"linking_records": [{"rkey": "4"}, {"rkey": "2"}]}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	opts := constellation.PaginateOptions{Sort: constellation.SortRKeyAsc, SortWindow: 2}
+
+	var rkeys string
+	err := client.GetLinksEach(context.Background(), constellation.LinksParams{Target: "did:plc:example"}, opts, func(record constellation.LinkRecord) error {
+		rkeys += record.RKey
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to stream links: %v", err)
+	}
+	if rkeys != "1234" {
+		t.Errorf("Expected records sorted within the window, got %s", rkeys)
+	}
+}
@@ -0,0 +1,114 @@
+package constellation
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// StatsReport is a snapshot of the server's statistics
+type StatsReport struct {
+	Stats
+	FetchedAt time.Time    // When the statistics were fetched
+	Growth    *StatsGrowth // Change since the previous GetStats call; nil on the first call
+	Rates     *StatsRates  // Smoothed growth rates; nil on the first call
+}
+
+// StatsRates are the growth rates of the statistics per second, exponentially
+// smoothed over every GetStats call with DefaultEMAHalfLife
+type StatsRates struct {
+	DIDs           float64
+	Targetables    float64
+	LinkingRecords float64
+}
+
+// StatsGrowth is the change in statistics between two GetStats calls
+type StatsGrowth struct {
+	DIDs           int64
+	Targetables    int64
+	LinkingRecords int64
+	Elapsed        time.Duration // Time between the two snapshots
+}
+
+// GetStats retrieves the server's statistics, including growth since the previous call
+// on this client
+func (c *Client) GetStats() (*StatsReport, error) {
+	info, err := c.GetAPIInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StatsReport{
+		Stats:     info.Stats,
+		FetchedAt: time.Now(),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if previous := c.lastStats; previous != nil {
+		report.Growth = &StatsGrowth{
+			DIDs:           report.DIDs - previous.DIDs,
+			Targetables:    report.Targetables - previous.Targetables,
+			LinkingRecords: report.LinkingRecords - previous.LinkingRecords,
+			Elapsed:        report.FetchedAt.Sub(previous.FetchedAt),
+		}
+	}
+	c.lastStats = report
+
+	dids := c.statsEMA[0].Add(float64(report.DIDs), report.FetchedAt)
+	targetables := c.statsEMA[1].Add(float64(report.Targetables), report.FetchedAt)
+	records := c.statsEMA[2].Add(float64(report.LinkingRecords), report.FetchedAt)
+	if report.Growth != nil {
+		report.Rates = &StatsRates{
+			DIDs:           dids.Rate,
+			Targetables:    targetables.Rate,
+			LinkingRecords: records.Rate,
+		}
+	}
+
+	return report, nil
+}
+
+// Humanize formats the statistics with abbreviated counts, e.g. "1.2M DIDs"
+func (s Stats) Humanize() string {
+	return fmt.Sprintf("%s DIDs, %s targetables, %s linking records",
+		HumanizeCount(s.DIDs), HumanizeCount(s.Targetables), HumanizeCount(s.LinkingRecords))
+}
+
+// Humanize formats the growth with abbreviated, signed counts
+func (g StatsGrowth) Humanize() string {
+	return fmt.Sprintf("%s DIDs, %s targetables, %s linking records in %s",
+		humanizeDelta(g.DIDs), humanizeDelta(g.Targetables), humanizeDelta(g.LinkingRecords),
+		g.Elapsed.Round(time.Second))
+}
+
+// HumanizeCount abbreviates a count with K, M, or B suffixes, e.g. 1234567 becomes "1.2M"
+func HumanizeCount(n int64) string {
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+
+	// Pick the unit after rounding, so 999_950 becomes "1.0M" rather than "1000.0K"
+	value, suffix := float64(n), ""
+	for _, unit := range []string{"K", "M", "B"} {
+		if math.Round(value*10)/10 < 1000 {
+			break
+		}
+		value, suffix = value/1000, unit
+	}
+	if suffix == "" {
+		return fmt.Sprintf("%s%d", sign, n)
+	}
+	return fmt.Sprintf("%s%.1f%s", sign, value, suffix)
+}
+
+// humanizeDelta formats a change with an explicit sign
+func humanizeDelta(n int64) string {
+	if n >= 0 {
+		return "+" + HumanizeCount(n)
+	}
+	return HumanizeCount(n)
+}
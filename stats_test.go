@@ -0,0 +1,64 @@
+package constellation_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestGetStats tests statistics retrieval and growth tracking
+func TestGetStats(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprintf(w, `{"stats": {"dids": %d, "targetables": 200, "linking_records": 300}}`, 100*calls)
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+
+	first, err := client.GetStats()
+	if err != nil {
+		t.Fatalf("Failed to get stats: %v", err)
+	}
+	if first.DIDs != 100 {
+		t.Errorf("Expected 100 DIDs, got %d", first.DIDs)
+	}
+	if first.Growth != nil {
+		t.Error("Expected no growth on first call")
+	}
+
+	second, err := client.GetStats()
+	if err != nil {
+		t.Fatalf("Failed to get stats: %v", err)
+	}
+	if second.Growth == nil || second.Growth.DIDs != 100 {
+		t.Errorf("Expected growth of 100 DIDs, got %+v", second.Growth)
+	}
+}
+
+// TestHumanizeCount tests abbreviated count formatting
+func TestHumanizeCount(t *testing.T) {
+	tests := map[int64]string{
+		0:           "0",
+		999:         "999",
+		1234:        "1.2K",
+		1234567:     "1.2M",
+		2500000000:  "2.5B",
+		-1500:       "-1.5K",
+		999_950:     "1.0M",
+		999_999:     "1.0M",
+		999_949_999: "999.9M",
+		999_950_000: "1.0B",
+	}
+
+	for n, expected := range tests {
+		if got := constellation.HumanizeCount(n); got != expected {
+			t.Errorf("HumanizeCount(%d): expected '%s', got '%s'", n, expected, got)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrStopVisit can be returned by a VisitLinks callback to stop early without
+// VisitLinks returning an error
+var ErrStopVisit = errors.New("stop visiting")
+
+// GetLinksChan paginates through every record linking to a target in a background
+// goroutine, sending records on the returned channel. Both channels are closed when
+// pagination ends; at most one error is sent on the error channel. Cancelling ctx
+// stops pagination and reports the context's error.
+func (c *Client) GetLinksChan(ctx context.Context, params LinksParams, opts PaginateOptions) (<-chan LinkRecord, <-chan error) {
+	return streamChan(ctx, func(emit func(LinkRecord) error) error {
+		return c.eachLink(ctx, params, opts, emit)
+	})
+}
+
+// streamChan runs a paginating function in a goroutine, sending items on a channel
+func streamChan[T any](ctx context.Context, run func(emit func(T) error) error) (<-chan T, <-chan error) {
+	items := make(chan T)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(items)
+
+		err := run(func(item T) error {
+			select {
+			case items <- item:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return items, errs
+}
+
+// GetLinksEach calls fn for every record linking to a target across all pages,
+// without holding more than one page (or sort window) in memory. It stops at the first error
+// returned by fn and returns that error.
+func (c *Client) GetLinksEach(ctx context.Context, params LinksParams, opts PaginateOptions, fn func(LinkRecord) error) error {
+	return c.eachLink(ctx, params, opts, fn)
+}
+
+// GetDistinctDIDsChan is GetLinksChan for the distinct DIDs linking to a target
+func (c *Client) GetDistinctDIDsChan(ctx context.Context, params LinksParams, opts PaginateOptions) (<-chan string, <-chan error) {
+	return streamChan(ctx, func(emit func(string) error) error {
+		return paginate(ctx, opts, params.Cursor, c.distinctDIDsFetcher(params), emit)
+	})
+}
+
+// GetDistinctDIDsEach is GetLinksEach for the distinct DIDs linking to a target
+func (c *Client) GetDistinctDIDsEach(ctx context.Context, params LinksParams, opts PaginateOptions, fn func(did string) error) error {
+	return paginate(ctx, opts, params.Cursor, c.distinctDIDsFetcher(params), fn)
+}
+
+// VisitLinks calls fn for every record linking to a target across all pages. It's
+// the simplest way to page through results, needing neither iterators nor
+// channels. Visiting stops when fn returns an error, which VisitLinks returns,
+// unless the error is ErrStopVisit, in which case it returns nil.
+func (c *Client) VisitLinks(ctx context.Context, params LinksParams, fn func(LinkRecord) error) error {
+	err := c.eachLink(ctx, params, PaginateOptions{}, fn)
+	if errors.Is(err, ErrStopVisit) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,135 @@
+package constellation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestGetLinksChan tests channel streaming and cancellation
+func TestGetLinksChan(t *testing.T) {
+	server := newPagedServer(t, 25)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	records, errs := client.GetLinksChan(context.Background(), params, constellation.PaginateOptions{})
+	count := 0
+	for range records {
+		count++
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("Streaming failed: %v", err)
+	}
+	if count != 25 {
+		t.Errorf("Expected 25 records, got %d", count)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	records, errs = client.GetLinksChan(ctx, params, constellation.PaginateOptions{})
+	<-records
+	cancel()
+	for range records {
+	}
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled after cancellation, got: %v", err)
+	}
+}
+
+// TestGetLinksEach tests callback iteration and stopping on callback errors
+func TestGetLinksEach(t *testing.T) {
+	server := newPagedServer(t, 25)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	count := 0
+	err := client.GetLinksEach(context.Background(), params, constellation.PaginateOptions{}, func(record constellation.LinkRecord) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to iterate links: %v", err)
+	}
+	if count != 25 {
+		t.Errorf("Expected 25 records, got %d", count)
+	}
+
+	errStop := errors.New("stop")
+	count = 0
+	err = client.GetLinksEach(context.Background(), params, constellation.PaginateOptions{}, func(record constellation.LinkRecord) error {
+		count++
+		if count == 3 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("Expected callback error, got: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected iteration to stop after 3 records, got %d", count)
+	}
+}
+
+// TestVisitLinks tests visiting every record and stopping early
+func TestVisitLinks(t *testing.T) {
+	server := newPagedServer(t, 25)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	count := 0
+	err := client.VisitLinks(context.Background(), params, func(constellation.LinkRecord) error {
+		count++
+		return nil
+	})
+	if err != nil || count != 25 {
+		t.Errorf("Expected 25 records, got %d, %v", count, err)
+	}
+
+	count = 0
+	err = client.VisitLinks(context.Background(), params, func(constellation.LinkRecord) error {
+		count++
+		if count == 12 {
+			return constellation.ErrStopVisit
+		}
+		return nil
+	})
+	if err != nil || count != 12 {
+		t.Errorf("Expected to stop after 12 records without error, got %d, %v", count, err)
+	}
+
+	errBoom := errors.New("boom")
+	err = client.VisitLinks(context.Background(), params, func(constellation.LinkRecord) error {
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Errorf("Expected the callback's error, got %v", err)
+	}
+}
+
+// TestGetDistinctDIDsEach tests the callback and channel equivalents of LinkingDIDs
+func TestGetDistinctDIDsEach(t *testing.T) {
+	server := newPagedDIDServer(t, 25)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	params := constellation.LinksParams{Target: "did:plc:example", Limit: 10}
+
+	count := 0
+	err := client.GetDistinctDIDsEach(context.Background(), params, constellation.PaginateOptions{}, func(string) error {
+		count++
+		return nil
+	})
+	if err != nil || count != 25 {
+		t.Errorf("Expected 25 DIDs, got %d, %v", count, err)
+	}
+
+	dids, errs := client.GetDistinctDIDsChan(context.Background(), params, constellation.PaginateOptions{})
+	count = 0
+	for range dids {
+		count++
+	}
+	if err := <-errs; err != nil || count != 25 {
+		t.Errorf("Expected 25 DIDs from the channel, got %d, %v", count, err)
+	}
+}
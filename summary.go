@@ -0,0 +1,131 @@
+package constellation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BacklinkSummary describes what links to a target: how many links come from
+// each collection and path, and what those links mean for known lexicons
+type BacklinkSummary struct {
+	Target string
+	Counts map[string]map[string]int64 // Links by collection, then path
+
+	// Labels describes the collection and path pairs matching a preset in
+	// DefaultRegistry, e.g. "Likes of a post, feed, or labeler". Unknown pairs
+	// have no label.
+	Labels map[string]map[string]string
+
+	redactor Redactor // The client's, for String
+}
+
+// BacklinkGroup is one collection and path linking to a summary's target
+type BacklinkGroup struct {
+	Collection string
+	Path       string
+	Count      int64
+	Label      string // Describes the links; "<collection> at <path>" for unknown lexicons
+	Preset     string // Name of the matching preset, if any
+}
+
+// BacklinkSummary returns the count of links to target from every collection
+// and path, labelled from the presets in DefaultRegistry, so one call answers
+// what kinds of things point at a record and how many of each
+func (c *Client) BacklinkSummary(ctx context.Context, target string) (*BacklinkSummary, error) {
+	counts, err := c.GetAllLinkCounts(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	redactor := c.redactor
+	c.mu.Unlock()
+
+	summary := &BacklinkSummary{
+		Target:   target,
+		redactor: redactor,
+		Counts:   make(map[string]map[string]int64, len(counts)),
+		Labels:   make(map[string]map[string]string),
+	}
+	kind := TargetKindOf(NormalizeTarget(target))
+	for collection, paths := range counts {
+		summary.Counts[collection] = make(map[string]int64, len(paths))
+		for path, count := range paths {
+			summary.Counts[collection][path] = int64(count)
+			if preset, ok := presetFor(collection, path, kind); ok {
+				if summary.Labels[collection] == nil {
+					summary.Labels[collection] = make(map[string]string)
+				}
+				summary.Labels[collection][path] = preset.Description
+			}
+		}
+	}
+	return summary, nil
+}
+
+// presetFor returns the first preset by name in DefaultRegistry for links from
+// collection at path to a kind of target
+func presetFor(collection, path string, kind TargetKind) (Preset, bool) {
+	for _, preset := range DefaultRegistry.ForCollection(collection) {
+		if preset.Path == path && preset.TargetKind == kind {
+			return preset, true
+		}
+	}
+	return Preset{}, false
+}
+
+// Total returns the count of links across all collections and paths
+func (s *BacklinkSummary) Total() int64 {
+	var total int64
+	for _, paths := range s.Counts {
+		for _, count := range paths {
+			total += count
+		}
+	}
+	return total
+}
+
+// Groups returns every collection and path linking to the target, most links
+// first, with ties ordered by collection and path
+func (s *BacklinkSummary) Groups() []BacklinkGroup {
+	kind := TargetKindOf(NormalizeTarget(s.Target))
+	var groups []BacklinkGroup
+	for collection, paths := range s.Counts {
+		for path, count := range paths {
+			group := BacklinkGroup{Collection: collection, Path: path, Count: count}
+			if preset, ok := presetFor(collection, path, kind); ok {
+				group.Preset = preset.Name
+			}
+			group.Label = s.Labels[collection][path]
+			if group.Label == "" {
+				group.Label = collection + " at " + path
+			}
+			groups = append(groups, group)
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Collection != b.Collection {
+			return a.Collection < b.Collection
+		}
+		return a.Path < b.Path
+	})
+	return groups
+}
+
+// String renders the summary as one line per group, most links first, with the
+// target redacted by the redactor of the client that built it
+func (s *BacklinkSummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d links to %s", s.Total(), s.redactor.Redact(s.Target))
+	for _, group := range s.Groups() {
+		fmt.Fprintf(&b, "\n  %d  %s", group.Count, group.Label)
+	}
+	return b.String()
+}
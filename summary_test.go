@@ -0,0 +1,61 @@
+package constellation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestBacklinkSummary tests counting and labelling links to a post
+func TestBacklinkSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"links": {
+			"app.bsky.feed.like": {".subject.uri": 12},
+			"app.bsky.feed.post": {".reply.parent.uri": 3, ".embed.record.uri": 3},
+			"com.example.bookmark": {".subject": 1}
+		}}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, constellation.DefaultTimeout)
+	target := "at://did:plc:a/app.bsky.feed.post/1"
+	summary, err := client.BacklinkSummary(context.Background(), target)
+	if err != nil {
+		t.Fatalf("Failed to summarize: %v", err)
+	}
+
+	if got := summary.Counts["app.bsky.feed.like"][".subject.uri"]; got != 12 {
+		t.Errorf("Expected 12 likes, got %d", got)
+	}
+	if got := summary.Total(); got != 19 {
+		t.Errorf("Expected 19 links, got %d", got)
+	}
+	if got := summary.Labels["app.bsky.feed.post"][".embed.record.uri"]; got != "Quotes of a post" {
+		t.Errorf("Expected the quotes label, got %q", got)
+	}
+	if _, ok := summary.Labels["com.example.bookmark"]; ok {
+		t.Error("Expected no label for an unknown lexicon")
+	}
+
+	groups := summary.Groups()
+	if len(groups) != 4 {
+		t.Fatalf("Expected 4 groups, got %d", len(groups))
+	}
+	if groups[0].Preset != "bsky.likes" || groups[0].Count != 12 {
+		t.Errorf("Expected likes first, got %+v", groups[0])
+	}
+	if groups[1].Path != ".embed.record.uri" || groups[2].Path != ".reply.parent.uri" {
+		t.Errorf("Expected ties ordered by path, got %+v, %+v", groups[1], groups[2])
+	}
+	if last := groups[3]; last.Label != "com.example.bookmark at .subject" || last.Preset != "" {
+		t.Errorf("Expected a fallback label, got %+v", last)
+	}
+
+	if s := summary.String(); !strings.HasPrefix(s, "19 links to "+target) || !strings.Contains(s, "12  Likes of a post") {
+		t.Errorf("Unexpected rendering:\n%s", s)
+	}
+}
@@ -0,0 +1,179 @@
+package constellation
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var (
+	// ErrInvalidTarget is returned for targets that aren't a well-formed AT URI,
+	// DID, or URL
+	ErrInvalidTarget = errors.New("invalid target")
+	// ErrTargetKindMismatch is returned when a query's collection and path link
+	// to a different kind of target, e.g. a DID queried at .subject.uri, which
+	// would silently match nothing
+	ErrTargetKindMismatch = errors.New("target kind does not match path")
+)
+
+// Target is a validated link target: an AT URI, a DID, or a web URL. Build one
+// with NewURITarget, NewDIDTarget, NewURLTarget, or ParseTarget; the zero value
+// is no target. URI and DID targets are stored normalized.
+type Target struct {
+	kind  TargetKind
+	value string
+}
+
+// NewURITarget returns a target for an at:// record URI. The authority must be a
+// DID, since records link to DIDs rather than handles.
+func NewURITarget(uri string) (Target, error) {
+	uri = NormalizeTarget(uri)
+	parsed, err := ParseATURI(uri)
+	if err != nil {
+		return Target{}, fmt.Errorf("%w: %v", ErrInvalidTarget, err)
+	}
+	if err := validateDID(parsed.DID); err != nil {
+		return Target{}, fmt.Errorf("%w: %s has a handle or malformed DID as its authority", ErrInvalidTarget, uri)
+	}
+	return Target{kind: TargetURI, value: uri}, nil
+}
+
+// NewDIDTarget returns a target for an account's DID
+func NewDIDTarget(did string) (Target, error) {
+	did = NormalizeTarget(did)
+	if err := validateDID(did); err != nil {
+		return Target{}, err
+	}
+	return Target{kind: TargetDID, value: did}, nil
+}
+
+// NewURLTarget returns a target for an http or https URL. The URL is kept as
+// written apart from surrounding whitespace, since records link URLs in any form
+// and the server matches them exactly; pass it through NormalizeTarget first if
+// you know records use the canonical form.
+func NewURLTarget(rawURL string) (Target, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if TargetKindOf(NormalizeTarget(rawURL)) != TargetURL {
+		return Target{}, fmt.Errorf("%w: not an http or https URL: %s", ErrInvalidTarget, rawURL)
+	}
+	if u, err := url.Parse(rawURL); err != nil || u.Host == "" {
+		return Target{}, fmt.Errorf("%w: malformed URL: %s", ErrInvalidTarget, rawURL)
+	}
+	return Target{kind: TargetURL, value: rawURL}, nil
+}
+
+// ParseTarget returns a target for s, inferring its kind from its scheme
+func ParseTarget(s string) (Target, error) {
+	switch TargetKindOf(NormalizeTarget(s)) {
+	case TargetURI:
+		return NewURITarget(s)
+	case TargetDID:
+		return NewDIDTarget(s)
+	case TargetURL:
+		return NewURLTarget(s)
+	}
+	return Target{}, fmt.Errorf("%w: %q is not an at:// URI, DID, or URL", ErrInvalidTarget, s)
+}
+
+// validateDID checks that did is syntactically a DID
+func validateDID(did string) error {
+	parts := strings.SplitN(did, ":", 3)
+	if len(parts) != 3 || parts[0] != "did" || parts[1] == "" || parts[2] == "" {
+		return fmt.Errorf("%w: malformed DID: %s", ErrInvalidTarget, did)
+	}
+	for _, c := range parts[1] {
+		if !('a' <= c && c <= 'z' || '0' <= c && c <= '9') {
+			return fmt.Errorf("%w: malformed DID method: %s", ErrInvalidTarget, did)
+		}
+	}
+	for _, c := range parts[2] {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.ContainsRune("._:%-", c)) {
+			return fmt.Errorf("%w: malformed DID: %s", ErrInvalidTarget, did)
+		}
+	}
+	if strings.HasSuffix(parts[2], ":") {
+		return fmt.Errorf("%w: malformed DID: %s", ErrInvalidTarget, did)
+	}
+	return nil
+}
+
+// Kind returns the kind of target, or TargetUnknown for the zero value
+func (t Target) Kind() TargetKind {
+	return t.kind
+}
+
+// String returns the target as it's queried
+func (t Target) String() string {
+	return t.value
+}
+
+// IsZero reports whether t is the zero value
+func (t Target) IsZero() bool {
+	return t.value == ""
+}
+
+// Params returns a query for links to t from collection at path. An empty path
+// is inferred as in LinksParams. It fails with ErrTargetKindMismatch when the
+// presets in DefaultRegistry only link collection at path to another kind of
+// target.
+func (t Target) Params(collection, path string) (LinksParams, error) {
+	if t.IsZero() {
+		return LinksParams{}, fmt.Errorf("%w: empty target", ErrInvalidTarget)
+	}
+	params := LinksParams{Target: t.value, Collection: collection, Path: path}
+	if err := params.checkKind(t.kind); err != nil {
+		return LinksParams{}, err
+	}
+	return params, nil
+}
+
+// Validate checks that params has a well-formed target and, for well-known
+// collections and paths, that the target is of the kind they link to. Queries
+// built from a Target are checked already; Validate catches the same mistakes
+// in queries built by hand.
+func (p LinksParams) Validate() error {
+	target, err := ParseTarget(p.Target)
+	if err != nil {
+		return err
+	}
+	return p.checkKind(target.Kind())
+}
+
+// checkKind reports ErrTargetKindMismatch when the presets for the collection
+// and (possibly inferred) path only link to kinds other than kind
+func (p LinksParams) checkKind(kind TargetKind) error {
+	p = p.withInferredPath()
+	if p.Collection == "" || p.Path == "" {
+		return nil
+	}
+
+	var linked []string
+	for _, preset := range DefaultRegistry.ForCollection(p.Collection) {
+		if preset.Path != p.Path {
+			continue
+		}
+		if preset.TargetKind == kind {
+			return nil
+		}
+		linked = append(linked, preset.TargetKind.String())
+	}
+	if len(linked) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s at %s links to %s, not %s",
+		ErrTargetKindMismatch, p.Collection, p.Path, linked[0], kind)
+}
+
+// String returns the name of the kind
+func (k TargetKind) String() string {
+	switch k {
+	case TargetURI:
+		return "at:// URIs"
+	case TargetDID:
+		return "DIDs"
+	case TargetURL:
+		return "URLs"
+	}
+	return "unknown targets"
+}
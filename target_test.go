@@ -0,0 +1,102 @@
+package constellation_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestParseTarget tests validating and normalizing targets of each kind
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		input string
+		kind  constellation.TargetKind
+		want  string
+	}{
+		{"at://did:plc:ABC/app.bsky.feed.post/3l", constellation.TargetURI, "at://did:plc:abc/app.bsky.feed.post/3l"},
+		{"did:plc:vc7f4oafdgxsihk4cry2xpze", constellation.TargetDID, "did:plc:vc7f4oafdgxsihk4cry2xpze"},
+		{"did:web:Example.com", constellation.TargetDID, "did:web:example.com"},
+		{" HTTPS://Example.com/a b ", constellation.TargetURL, "HTTPS://Example.com/a b"},
+	}
+	for _, tt := range tests {
+		target, err := constellation.ParseTarget(tt.input)
+		if err != nil {
+			t.Errorf("ParseTarget(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if target.Kind() != tt.kind || target.String() != tt.want {
+			t.Errorf("ParseTarget(%q) = %v %q, want %v %q", tt.input, target.Kind(), target, tt.kind, tt.want)
+		}
+	}
+
+	for _, input := range []string{
+		"",
+		"alice.bsky.social",
+		"at://alice.bsky.social/app.bsky.feed.post/3l", // Handle authority
+		"at://did:plc:abc", // Not a record
+		"did:plc:",
+		"did:PLC!:abc",
+		"did:plc:a b",
+		"https://",
+	} {
+		if _, err := constellation.ParseTarget(input); !errors.Is(err, constellation.ErrInvalidTarget) {
+			t.Errorf("Expected ErrInvalidTarget for %q, got %v", input, err)
+		}
+	}
+
+	if _, err := constellation.NewDIDTarget("at://did:plc:abc/app.bsky.feed.post/3l"); !errors.Is(err, constellation.ErrInvalidTarget) {
+		t.Errorf("Expected NewDIDTarget to reject a URI, got %v", err)
+	}
+}
+
+// TestTargetParams tests that a target only builds queries for paths linking to
+// its kind
+func TestTargetParams(t *testing.T) {
+	did, _ := constellation.NewDIDTarget("did:plc:abc")
+	post, _ := constellation.NewURITarget("at://did:plc:abc/app.bsky.feed.post/3l")
+
+	params, err := did.Params(constellation.CollectionFollow, "")
+	if err != nil {
+		t.Fatalf("Expected followers of a DID to be valid, got %v", err)
+	}
+	if params.Target != "did:plc:abc" || params.Collection != constellation.CollectionFollow {
+		t.Errorf("Unexpected params %+v", params)
+	}
+
+	if _, err := did.Params(constellation.CollectionLike, constellation.PathSubjectURI); !errors.Is(err, constellation.ErrTargetKindMismatch) {
+		t.Errorf("Expected likes of a DID to mismatch, got %v", err)
+	}
+	if _, err := post.Params(constellation.CollectionFollow, constellation.PathSubject); !errors.Is(err, constellation.ErrTargetKindMismatch) {
+		t.Errorf("Expected follows of a post to mismatch, got %v", err)
+	}
+	// List items link both DIDs (members) and URIs (lists) from different paths
+	if _, err := post.Params(constellation.CollectionListItem, constellation.PathList); err != nil {
+		t.Errorf("Expected items of a list to be valid, got %v", err)
+	}
+	// Unknown lexicons can't be checked
+	if _, err := did.Params("com.example.thing", ".subject.uri"); err != nil {
+		t.Errorf("Expected an unknown collection to pass, got %v", err)
+	}
+	if _, err := (constellation.Target{}).Params(constellation.CollectionLike, ""); !errors.Is(err, constellation.ErrInvalidTarget) {
+		t.Errorf("Expected the zero target to be rejected, got %v", err)
+	}
+}
+
+// TestLinksParamsValidate tests checking hand-built queries
+func TestLinksParamsValidate(t *testing.T) {
+	valid := constellation.LinksParams{Target: "at://did:plc:abc/app.bsky.feed.post/3l", Collection: constellation.CollectionLike}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected a valid query, got %v", err)
+	}
+
+	swapped := constellation.LinksParams{Target: "did:plc:abc", Collection: constellation.CollectionRepost, Path: constellation.PathSubjectURI}
+	if err := swapped.Validate(); !errors.Is(err, constellation.ErrTargetKindMismatch) {
+		t.Errorf("Expected a mismatch, got %v", err)
+	}
+
+	handle := constellation.LinksParams{Target: "at://alice.bsky.social/app.bsky.feed.post/3l", Collection: constellation.CollectionLike}
+	if err := handle.Validate(); !errors.Is(err, constellation.ErrInvalidTarget) {
+		t.Errorf("Expected an invalid target, got %v", err)
+	}
+}
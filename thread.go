@@ -0,0 +1,116 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+)
+
+// ThreadOptions bounds a BuildThread crawl. Zero values mean no limit.
+type ThreadOptions struct {
+	MaxDepth          int // Reply levels below the root to resolve
+	MaxNodes          int // Posts in the tree, including the root
+	MaxRepliesPerPost int // Direct replies followed from each post
+
+	// Parallelism is the number of posts whose replies are fetched concurrently.
+	// Defaults to DefaultParallelism.
+	Parallelism int
+
+	// Budget, if set, caps the requests and records of the whole crawl. When it's
+	// exceeded the partial tree is returned with a BudgetExceededError.
+	Budget *Budget
+}
+
+// ThreadNode is a post in a reply tree
+type ThreadNode struct {
+	URI     string
+	Record  LinkRecord    // The reply record; zero for the root
+	Depth   int           // Reply levels below the root, which is at depth zero
+	Replies []*ThreadNode // Direct replies, in the order the API returned them
+
+	// Truncated is set when a limit stopped this post's replies from being fully
+	// resolved, so Replies may be incomplete
+	Truncated bool
+}
+
+// Size returns the number of posts in the tree rooted at n, including n
+func (n *ThreadNode) Size() int {
+	size := 1
+	for _, reply := range n.Replies {
+		size += reply.Size()
+	}
+	return size
+}
+
+// Walk calls fn for n and every reply below it, parents before their replies,
+// stopping at the first error
+func (n *ThreadNode) Walk(fn func(*ThreadNode) error) error {
+	if err := fn(n); err != nil {
+		return err
+	}
+	for _, reply := range n.Replies {
+		if err := reply.Walk(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BuildThread reconstructs the reply tree below the post at rootURI by resolving
+// direct replies through .reply.parent.uri level by level. Unlike
+// ThreadRepliesOf, which lists a thread's replies flat, the tree keeps who replied
+// to whom. If the crawl fails partway, the tree built so far is returned with the
+// error.
+func (c *Client) BuildThread(ctx context.Context, rootURI string, opts ThreadOptions) (*ThreadNode, error) {
+	root := &ThreadNode{URI: rootURI}
+	seen := map[string]bool{rootURI: true}
+	nodes := 1
+	ctx = WithBudget(ctx, opts.Budget)
+	workers := opts.Parallelism
+	if workers <= 0 {
+		workers = DefaultParallelism
+	}
+
+	level := []*ThreadNode{root}
+	for len(level) > 0 {
+		if opts.MaxDepth > 0 && level[0].Depth >= opts.MaxDepth {
+			for _, node := range level {
+				node.Truncated = true
+			}
+			break
+		}
+
+		replies := make([][]LinkRecord, len(level))
+		errs := make([]error, len(level))
+		parallelEach(len(level), workers, func(i int) {
+			replies[i], errs[i] = c.RepliesTo(ctx, level[i].URI, PaginateOptions{MaxRecords: opts.MaxRepliesPerPost})
+		})
+		if err := errors.Join(errs...); err != nil {
+			return root, err
+		}
+
+		var next []*ThreadNode
+		for i, node := range level {
+			if opts.MaxRepliesPerPost > 0 && len(replies[i]) >= opts.MaxRepliesPerPost {
+				node.Truncated = true
+			}
+			for _, record := range replies[i] {
+				uri := record.RecordURI()
+				if seen[uri] {
+					continue
+				}
+				if opts.MaxNodes > 0 && nodes >= opts.MaxNodes {
+					node.Truncated = true
+					break
+				}
+				seen[uri] = true
+				nodes++
+
+				reply := &ThreadNode{URI: uri, Record: record, Depth: node.Depth + 1}
+				node.Replies = append(node.Replies, reply)
+				next = append(next, reply)
+			}
+		}
+		level = next
+	}
+	return root, nil
+}
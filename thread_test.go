@@ -0,0 +1,79 @@
+package constellation_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// newThreadServer serves a reply graph of post rkeys to the rkeys replying to them
+func newThreadServer(t *testing.T, replies map[string][]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("path") != constellation.PathReplyParentURI {
+			t.Errorf("Expected replies to be resolved by parent, got path %q", query.Get("path"))
+		}
+		target := query.Get("target")
+		records := []constellation.LinkRecord{}
+		for _, rkey := range replies[target[strings.LastIndex(target, "/")+1:]] {
+			records = append(records, constellation.LinkRecord{DID: "did:plc:r", Collection: constellation.CollectionPost, RKey: rkey})
+		}
+		json.NewEncoder(w).Encode(constellation.LinksResponse{LinkingRecords: records})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestBuildThread tests reconstructing a reply tree with depth and size limits
+func TestBuildThread(t *testing.T) {
+	server := newThreadServer(t, map[string][]string{
+		"root": {"a", "b"},
+		"a":    {"c", "d"},
+		"c":    {"e"},
+	})
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	ctx := context.Background()
+	rootURI := "at://did:plc:r/app.bsky.feed.post/root"
+
+	tree, err := client.BuildThread(ctx, rootURI, constellation.ThreadOptions{})
+	if err != nil {
+		t.Fatalf("BuildThread failed: %v", err)
+	}
+	if tree.Size() != 6 {
+		t.Errorf("Expected 6 posts, got %d", tree.Size())
+	}
+	var order []string
+	tree.Walk(func(node *constellation.ThreadNode) error {
+		order = append(order, node.URI[strings.LastIndex(node.URI, "/")+1:])
+		return nil
+	})
+	if got := strings.Join(order, ","); got != "root,a,c,e,d,b" {
+		t.Errorf("Expected parents before replies, got %s", got)
+	}
+	if e := tree.Replies[0].Replies[0].Replies[0]; e.Depth != 3 || e.Record.RKey != "e" {
+		t.Errorf("Expected e at depth 3, got %+v", e)
+	}
+
+	shallow, err := client.BuildThread(ctx, rootURI, constellation.ThreadOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("BuildThread failed: %v", err)
+	}
+	if shallow.Size() != 3 || !shallow.Replies[0].Truncated {
+		t.Errorf("Expected the depth limit to stop after the first level, got size %d", shallow.Size())
+	}
+
+	small, err := client.BuildThread(ctx, rootURI, constellation.ThreadOptions{MaxNodes: 4, Parallelism: 1})
+	if err != nil {
+		t.Fatalf("BuildThread failed: %v", err)
+	}
+	if small.Size() != 4 || !small.Replies[0].Truncated {
+		t.Errorf("Expected the node limit to cap the tree at 4, got %d", small.Size())
+	}
+}
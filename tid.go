@@ -0,0 +1,43 @@
+package constellation
+
+import (
+	"strings"
+	"time"
+)
+
+// tidAlphabet is the base32-sortable alphabet used by AT Protocol TIDs
+const tidAlphabet = "234567abcdefghijklmnopqrstuvwxyz"
+
+// tidTime decodes the timestamp embedded in a TID record key.
+// It returns false if rkey is not a valid TID.
+func tidTime(rkey string) (time.Time, bool) {
+	if len(rkey) != 13 {
+		return time.Time{}, false
+	}
+
+	var value uint64
+	for i := 0; i < len(rkey); i++ {
+		index := strings.IndexByte(tidAlphabet, rkey[i])
+		if index < 0 || (i == 0 && index >= 16) {
+			return time.Time{}, false
+		}
+		value = value<<5 | uint64(index)
+	}
+
+	micros := int64(value >> 10)
+	return time.UnixMicro(micros).UTC(), true
+}
+
+// atURIRKey returns the record key of an at:// URI, or "" if the URI has none
+func atURIRKey(uri string) string {
+	rest, ok := strings.CutPrefix(uri, "at://")
+	if !ok {
+		return ""
+	}
+
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 {
+		return ""
+	}
+	return parts[2]
+}
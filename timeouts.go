@@ -0,0 +1,116 @@
+package constellation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Errors reported when a split timeout expires
+var (
+	ErrHeaderTimeout = errors.New("timed out waiting for response headers")
+	ErrBodyTimeout   = errors.New("timed out reading response body")
+)
+
+// Timeouts bounds the phases of a request separately, so a slow download of a
+// large page isn't cut off by the limit meant to catch an unresponsive server
+type Timeouts struct {
+	// Header bounds connecting, sending the request, and receiving the response
+	// headers. Zero means no limit.
+	Header time.Duration
+
+	// Body bounds reading the response body, measured from when the headers
+	// arrive. Zero means no limit.
+	Body time.Duration
+}
+
+// UseTimeouts applies split timeouts to every request. HTTPClient.Timeout covers
+// the whole request including the body, so it's cleared to let Body govern
+// reads; other timeouts, such as an adaptive timeout, still apply.
+func (c *Client) UseTimeouts(timeouts Timeouts) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.timeouts = timeouts
+	if c.HTTPClient != nil {
+		c.HTTPClient.Timeout = 0
+	}
+	return c
+}
+
+// phaseTimer enforces split timeouts on one request by cancelling its context
+// with the expired phase's error as the cause
+type phaseTimer struct {
+	timeouts Timeouts
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+	timer    *time.Timer
+}
+
+// startTimeouts returns req bound to a context enforcing the client's split
+// timeouts, and the timer enforcing them, or nil if none are set
+func (c *Client) startTimeouts(req *http.Request) (*http.Request, *phaseTimer) {
+	c.mu.Lock()
+	timeouts := c.timeouts
+	c.mu.Unlock()
+
+	if timeouts == (Timeouts{}) {
+		return req, nil
+	}
+
+	ctx, cancel := context.WithCancelCause(req.Context())
+	t := &phaseTimer{timeouts: timeouts, ctx: ctx, cancel: cancel}
+	if timeouts.Header > 0 {
+		t.timer = time.AfterFunc(timeouts.Header, func() { cancel(ErrHeaderTimeout) })
+	}
+	return req.WithContext(ctx), t
+}
+
+// headers is called when the request returns. It stops the header timer,
+// translates a header timeout into ErrHeaderTimeout, and starts the body timer.
+func (t *phaseTimer) headers(resp *http.Response, err error) error {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	if err != nil {
+		if errors.Is(context.Cause(t.ctx), ErrHeaderTimeout) {
+			err = ErrHeaderTimeout
+		}
+		t.cancel(nil)
+		return err
+	}
+
+	if t.timeouts.Body > 0 {
+		t.timer = time.AfterFunc(t.timeouts.Body, func() { t.cancel(ErrBodyTimeout) })
+	} else {
+		t.timer = nil
+	}
+	resp.Body = &timedBody{ReadCloser: resp.Body, timer: t}
+	return nil
+}
+
+// timedBody reports body timeouts as ErrBodyTimeout and releases the timer on Close
+type timedBody struct {
+	io.ReadCloser
+	timer *phaseTimer
+}
+
+func (b *timedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && errors.Is(context.Cause(b.timer.ctx), ErrBodyTimeout) {
+		err = fmt.Errorf("%w after %s", ErrBodyTimeout, b.timer.timeouts.Body)
+	}
+	return n, err
+}
+
+func (b *timedBody) Close() error {
+	if b.timer.timer != nil {
+		b.timer.timer.Stop()
+	}
+	err := b.ReadCloser.Close()
+	b.timer.cancel(nil)
+	return err
+}
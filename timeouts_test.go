@@ -0,0 +1,75 @@
+package constellation_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// newSlowServer returns a server that waits headerDelay before sending headers
+// and bodyDelay between the two halves of the body
+func newSlowServer(t *testing.T, headerDelay, bodyDelay time.Duration) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(headerDelay):
+		case <-r.Context().Done():
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"total": `))
+		w.(http.Flusher).Flush()
+		select {
+		case <-time.After(bodyDelay):
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte(`7}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+var timeoutParams = constellation.LinksParams{Target: "at://a", Collection: constellation.CollectionLike}
+
+// TestHeaderTimeout tests that an unresponsive server hits the header timeout
+func TestHeaderTimeout(t *testing.T) {
+	server := newSlowServer(t, time.Second, 0)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second).
+		UseTimeouts(constellation.Timeouts{Header: 50 * time.Millisecond})
+
+	if _, err := client.GetLinksCount(timeoutParams); !errors.Is(err, constellation.ErrHeaderTimeout) {
+		t.Errorf("Expected ErrHeaderTimeout, got %v", err)
+	}
+}
+
+// TestSlowBodyWithinBodyTimeout tests that a slow body isn't cut off by the
+// header timeout or the HTTP client's overall timeout
+func TestSlowBodyWithinBodyTimeout(t *testing.T) {
+	server := newSlowServer(t, 0, 150*time.Millisecond)
+	client := constellation.NewClientWithConfig(server.URL, 50*time.Millisecond).
+		UseTimeouts(constellation.Timeouts{Header: 50 * time.Millisecond, Body: time.Second})
+
+	count, err := client.GetLinksCount(timeoutParams)
+	if err != nil {
+		t.Fatalf("Expected the slow body to be read, got %v", err)
+	}
+	if count.Total != 7 {
+		t.Errorf("Expected total 7, got %d", count.Total)
+	}
+}
+
+// TestBodyTimeout tests that a stalled body hits the body timeout
+func TestBodyTimeout(t *testing.T) {
+	server := newSlowServer(t, 0, time.Second)
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second).
+		UseTimeouts(constellation.Timeouts{Body: 50 * time.Millisecond})
+
+	if _, err := client.GetLinksCount(timeoutParams); !errors.Is(err, constellation.ErrBodyTimeout) {
+		t.Errorf("Expected ErrBodyTimeout, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package constellation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrValueTypeMismatch is returned by TypedLinks and TypedLinksEach for records
+// whose collection has a registered value type other than the requested one
+var ErrValueTypeMismatch = errors.New("value type doesn't match the registered type")
+
+// valueTypes maps collection NSIDs to the registered Go type for their records
+var (
+	valueTypesMu sync.RWMutex
+	valueTypes   = make(map[string]reflect.Type)
+)
+
+// RegisterValueType registers the Go type used to decode record values of a
+// collection, typically a custom app lexicon. Pass a pointer to a zero value:
+//
+//	constellation.RegisterValueType("com.example.vote", &Vote{})
+func RegisterValueType(collection string, prototype any) {
+	t := reflect.TypeOf(prototype)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	valueTypesMu.Lock()
+	defer valueTypesMu.Unlock()
+	valueTypes[collection] = t
+}
+
+// DecodeValue decodes the record's value into the type registered for its
+// collection and returns a pointer to it. Values of unregistered collections are
+// returned unchanged as map[string]any.
+func (r LinkRecord) DecodeValue() (any, error) {
+	t, ok := registeredValueType(r.Collection)
+	if !ok {
+		return r.Value, nil
+	}
+
+	value := reflect.New(t).Interface()
+	if err := decodeValueInto(r, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// registeredValueType returns the type registered for collection
+func registeredValueType(collection string) (reflect.Type, bool) {
+	valueTypesMu.RLock()
+	defer valueTypesMu.RUnlock()
+	t, ok := valueTypes[collection]
+	return t, ok
+}
+
+// DecodeValueAs decodes the record's value into T regardless of registration
+func DecodeValueAs[T any](record LinkRecord) (T, error) {
+	var value T
+	err := decodeValueInto(record, &value)
+	return value, err
+}
+
+// decodeValueInto converts the record's generic value into dest via JSON
+func decodeValueInto(record LinkRecord, dest any) error {
+	data, err := json.Marshal(record.Value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s value: %w", record.Collection, err)
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("failed to decode %s value: %w", record.Collection, err)
+	}
+	return nil
+}
+
+// decodeTyped decodes the record's value as T, failing with ErrValueTypeMismatch
+// if its collection has a different registered type. Interface and map types
+// skip the check, since they can hold any value.
+func decodeTyped[T any](record LinkRecord) (T, error) {
+	want := reflect.TypeOf((*T)(nil)).Elem()
+	if want.Kind() == reflect.Pointer {
+		want = want.Elem()
+	}
+	if t, ok := registeredValueType(record.Collection); ok && want.Kind() != reflect.Interface && want.Kind() != reflect.Map && t != want {
+		var zero T
+		return zero, fmt.Errorf("%w: %s records decode as %v, not %v", ErrValueTypeMismatch, record.Collection, t, want)
+	}
+	return DecodeValueAs[T](record)
+}
+
+// TypedLinksEach is GetLinksEach with each record's value decoded as T. Decoding
+// failures, including ErrValueTypeMismatch, are passed to fn with the zero value;
+// returning them stops iteration.
+func TypedLinksEach[T any](ctx context.Context, c *Client, params LinksParams, opts PaginateOptions, fn func(T, error) error) error {
+	return c.GetLinksEach(ctx, params, opts, func(record LinkRecord) error {
+		return fn(decodeTyped[T](record))
+	})
+}
+
+// DecodedRecord is a linking record with its value decoded by DecodeValue
+type DecodedRecord struct {
+	Record LinkRecord
+	Value  any // Pointer to the registered type, or map[string]any if unregistered
+}
+
+// DecodedLinksEach is GetLinksEach with each record's value decoded by
+// DecodeValue, into the type registered for its collection, so results mixing
+// collections decode each into its own type. Decoding failures are passed to fn
+// with a nil Value; returning them stops iteration.
+func (c *Client) DecodedLinksEach(ctx context.Context, params LinksParams, opts PaginateOptions, fn func(DecodedRecord, error) error) error {
+	return c.GetLinksEach(ctx, params, opts, func(record LinkRecord) error {
+		value, err := record.DecodeValue()
+		return fn(DecodedRecord{Record: record, Value: value}, err)
+	})
+}
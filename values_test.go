@@ -0,0 +1,142 @@
+package constellation_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// vote is a custom lexicon record used in tests
+type vote struct {
+	Subject string `json:"subject"`
+	Score   int    `json:"score"`
+}
+
+// TestRegisterValueType tests decoding values of registered collections
+func TestRegisterValueType(t *testing.T) {
+	constellation.RegisterValueType("com.example.vote", &vote{})
+
+	record := constellation.LinkRecord{
+		Collection: "com.example.vote",
+		Value:      map[string]any{"subject": "at://did:plc:example/com.example.post/1", "score": 5},
+	}
+
+	value, err := record.DecodeValue()
+	if err != nil {
+		t.Fatalf("Failed to decode value: %v", err)
+	}
+	v, ok := value.(*vote)
+	if !ok {
+		t.Fatalf("Expected *vote, got %T", value)
+	}
+	if v.Score != 5 {
+		t.Errorf("Expected score 5, got %d", v.Score)
+	}
+
+	record.Collection = "com.example.unknown"
+	value, err = record.DecodeValue()
+	if err != nil {
+		t.Fatalf("Failed to decode value: %v", err)
+	}
+	if _, ok := value.(map[string]any); !ok {
+		t.Errorf("Expected unregistered value to stay a map, got %T", value)
+	}
+}
+
+// TestTypedLinksEach tests streaming records with decoded values
+func TestTypedLinksEach(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total": 2, "linking_records": [
+			{"collection": "com.example.vote", "value": {"score": 1}},
+			{"collection": "com.example.vote", "value": {"score": "high"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	var scores []int
+	decodeErrors := 0
+	err := constellation.TypedLinksEach(context.Background(), client, constellation.LinksParams{Target: "at://a"}, constellation.PaginateOptions{},
+		func(v vote, err error) error {
+			if err != nil {
+				decodeErrors++
+				return nil
+			}
+			scores = append(scores, v.Score)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Failed to stream: %v", err)
+	}
+	if len(scores) != 1 || scores[0] != 1 || decodeErrors != 1 {
+		t.Errorf("Expected one decoded vote and one decode error, got %v and %d", scores, decodeErrors)
+	}
+}
+
+// newMixedValueServer serves a vote and an unregistered record
+func newMixedValueServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total": 2, "linking_records": [
+			{"collection": "com.example.vote", "value": {"score": 3}},
+			{"collection": "com.example.note", "value": {"text": "hi"}}
+		]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestTypedLinksEachMismatch tests that T is checked against registered types
+func TestTypedLinksEachMismatch(t *testing.T) {
+	constellation.RegisterValueType("com.example.vote", &vote{})
+	client := constellation.NewClientWithConfig(newMixedValueServer(t).URL, 5*time.Second)
+
+	type note struct {
+		Text string `json:"text"`
+	}
+	var errs []error
+	err := constellation.TypedLinksEach(context.Background(), client, constellation.LinksParams{Target: "at://a"}, constellation.PaginateOptions{},
+		func(n note, err error) error {
+			errs = append(errs, err)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Failed to stream: %v", err)
+	}
+	if len(errs) != 2 || !errors.Is(errs[0], constellation.ErrValueTypeMismatch) || errs[1] != nil {
+		t.Errorf("Expected a mismatch for the vote only, got %v", errs)
+	}
+}
+
+// TestDecodedLinksEach tests decoding each record into its registered type
+func TestDecodedLinksEach(t *testing.T) {
+	constellation.RegisterValueType("com.example.vote", &vote{})
+	client := constellation.NewClientWithConfig(newMixedValueServer(t).URL, 5*time.Second)
+
+	var values []any
+	err := client.DecodedLinksEach(context.Background(), constellation.LinksParams{Target: "at://a"}, constellation.PaginateOptions{},
+		func(record constellation.DecodedRecord, err error) error {
+			if err != nil {
+				return err
+			}
+			values = append(values, record.Value)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Failed to stream: %v", err)
+	}
+	if len(values) != 2 {
+		t.Fatalf("Expected 2 values, got %d", len(values))
+	}
+	if v, ok := values[0].(*vote); !ok || v.Score != 3 {
+		t.Errorf("Expected a *vote, got %#v", values[0])
+	}
+	if _, ok := values[1].(map[string]any); !ok {
+		t.Errorf("Expected the unregistered value to stay a map, got %T", values[1])
+	}
+}
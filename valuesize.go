@@ -0,0 +1,99 @@
+package constellation
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// DefaultValueSampleSize is the default number of records sampled by SampleValueSizes
+const DefaultValueSampleSize = 200
+
+// ValueSizeOptions controls value size sampling
+type ValueSizeOptions struct {
+	SampleSize int // Records to sample; defaults to DefaultValueSampleSize
+
+	// Hydrate fetches values missing from the API response from each record's
+	// PDS. This costs a request per sampled record.
+	Hydrate bool
+}
+
+// ValueSizeStats describes the distribution of encoded Value sizes, in bytes, in
+// a sample of a query's records
+type ValueSizeStats struct {
+	Sampled int // Records whose value size was measured
+	Missing int // Sampled records without a value
+
+	Min  int
+	Max  int
+	Mean float64
+	P50  int
+	P90  int
+	P99  int
+
+	Total          int   // Total records reported for the query
+	EstimatedBytes int64 // Mean size times Total, a rough budget for a full export
+}
+
+// SampleValueSizes measures the encoded size of record values for a query, to
+// help choose page sizes and memory budgets before a bulk job. The sample is
+// taken from the first pages of results, so it reflects the most recent records.
+func (c *Client) SampleValueSizes(ctx context.Context, params LinksParams, opts ValueSizeOptions) (*ValueSizeStats, error) {
+	sampleSize := opts.SampleSize
+	if sampleSize <= 0 {
+		sampleSize = DefaultValueSampleSize
+	}
+
+	var sizes []int
+	stats := &ValueSizeStats{}
+	err := c.GetLinksEach(ctx, params, PaginateOptions{MaxRecords: sampleSize}, func(record LinkRecord) error {
+		value := record.Value
+		if value == nil && opts.Hydrate {
+			fetched, err := c.GetRecord(ctx, record.RecordURI())
+			if err == nil {
+				value = fetched.Value
+			} else if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+		if value == nil {
+			stats.Missing++
+			return nil
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		sizes = append(sizes, len(data))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := c.GetLinksCountContext(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	stats.Total = count.Total
+
+	if len(sizes) == 0 {
+		return stats, nil
+	}
+
+	stats.Sampled = len(sizes)
+	stats.Min, stats.Max = sizes[0], sizes[0]
+	sum := 0
+	for _, size := range sizes {
+		stats.Min = minOf(stats.Min, size)
+		stats.Max = maxOf(stats.Max, size)
+		sum += size
+	}
+	stats.Mean = float64(sum) / float64(len(sizes))
+	stats.P50 = percentile(sizes, 50)
+	stats.P90 = percentile(sizes, 90)
+	stats.P99 = percentile(sizes, 99)
+	stats.EstimatedBytes = int64(stats.Mean * float64(stats.Total))
+
+	return stats, nil
+}
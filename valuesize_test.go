@@ -0,0 +1,42 @@
+package constellation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestSampleValueSizes tests value size statistics over a sample of records
+func TestSampleValueSizes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/links/count":
+			w.Write([]byte(`{"total": 100}`))
+		default:
+			w.Write([]byte(`{"linking_records": [
+				{"did": "did:plc:a", "value": {"a": 1}},
+				{"did": "did:plc:b", "value": {"text": "hello world"}},
+				{"did": "did:plc:c"}
+			]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	stats, err := client.SampleValueSizes(context.Background(), constellation.LinksParams{Target: "did:plc:example"}, constellation.ValueSizeOptions{})
+	if err != nil {
+		t.Fatalf("Failed to sample value sizes: %v", err)
+	}
+
+	// {"a":1} is 7 bytes and {"text":"hello world"} is 22 bytes
+	if stats.Sampled != 2 || stats.Missing != 1 || stats.Min != 7 || stats.Max != 22 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.P50 != 7 || stats.Total != 100 || stats.EstimatedBytes != 1450 {
+		t.Errorf("Unexpected distribution or estimate: %+v", stats)
+	}
+}
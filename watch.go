@@ -0,0 +1,391 @@
+package constellation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWatchMinInterval is the fastest a watched target is polled
+	DefaultWatchMinInterval = 15 * time.Second
+	// DefaultWatchMaxInterval is the slowest a watched target is polled
+	DefaultWatchMaxInterval = 15 * time.Minute
+	// MinWatchInterval is the floor applied to MinInterval, so a tiny interval
+	// can't make Run spin; rate limiting requests is left to the client
+	MinWatchInterval = 10 * time.Millisecond
+)
+
+// WatchEvent reports a change in a watched target's link count
+type WatchEvent struct {
+	Params   LinksParams
+	Previous int
+	Total    int
+	At       time.Time
+
+	// Smoothed is an exponential moving average of the count and Rate its
+	// smoothed change per second, over every poll rather than only changes
+	Smoothed float64
+	Rate     float64
+}
+
+// Delta returns the change in the count
+func (e WatchEvent) Delta() int {
+	return e.Total - e.Previous
+}
+
+// Watcher polls link counts for a set of targets and reports changes. Polling
+// adapts to activity: a target's interval halves when its count changes and
+// doubles when it doesn't, within [MinInterval, MaxInterval], so quiet targets
+// cost few requests while active ones stay fresh. A MinInterval below
+// MinWatchInterval is raised to it, and a MaxInterval below MinInterval is
+// raised to MinInterval; zero bounds take their defaults.
+type Watcher struct {
+	MinInterval time.Duration
+	MaxInterval time.Duration
+
+	// Parallelism is the number of due targets polled at once, defaulting to
+	// DefaultParallelism
+	Parallelism int
+
+	// SmoothingHalfLife is the half-life of each target's smoothed count and rate,
+	// defaulting to DefaultEMAHalfLife
+	SmoothingHalfLife time.Duration
+
+	// OnChange is called from Run when a target's count changes. The first poll of
+	// a target establishes its baseline and isn't reported. Callbacks aren't called
+	// concurrently, even when targets are polled in parallel.
+	OnChange func(WatchEvent)
+
+	// OnIdentityChange is called from Run when a DID added with WatchIdentity
+	// changes its handle, signing keys, or PDS
+	OnIdentityChange func(IdentityEvent)
+
+	client  *Client
+	mu      sync.Mutex
+	targets map[string]*watchedTarget
+	wake    chan struct{}
+}
+
+// IdentityEvent reports a change in a watched account's DID document
+type IdentityEvent struct {
+	DID      string
+	Previous *DIDDocument
+	Current  *DIDDocument
+	At       time.Time
+
+	HandleChanged bool
+	KeysChanged   bool // Signing keys were rotated
+	PDSChanged    bool
+}
+
+// watchedTarget is the polling state of one watched query or identity
+type watchedTarget struct {
+	params   LinksParams // Count query, for count targets
+	did      string      // Watched DID, for identity targets
+	total    int
+	ema      EMA
+	doc      *DIDDocument
+	polled   bool
+	interval time.Duration
+	next     time.Time
+}
+
+// NewWatcher creates a watcher polling with the client between the default bounds
+func NewWatcher(c *Client) *Watcher {
+	return &Watcher{
+		MinInterval: DefaultWatchMinInterval,
+		MaxInterval: DefaultWatchMaxInterval,
+		client:      c,
+		targets:     make(map[string]*watchedTarget),
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Add starts watching the count for params. New targets are polled immediately.
+func (w *Watcher) Add(params LinksParams) {
+	w.mu.Lock()
+	key := CountKey(params, false)
+	if _, ok := w.targets[key]; !ok {
+		minInterval, _ := w.bounds()
+		w.targets[key] = &watchedTarget{params: params, interval: minInterval, ema: EMA{HalfLife: w.SmoothingHalfLife}}
+	}
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// WatchIdentity starts watching did's DID document for handle, key, and PDS
+// changes, which often matter to the same consumers as its backlinks. Identities
+// are polled adaptively like counts.
+func (w *Watcher) WatchIdentity(did string) {
+	w.mu.Lock()
+	key := identityKey(did)
+	if _, ok := w.targets[key]; !ok {
+		minInterval, _ := w.bounds()
+		w.targets[key] = &watchedTarget{did: did, interval: minInterval}
+	}
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// UnwatchIdentity stops watching did's DID document
+func (w *Watcher) UnwatchIdentity(did string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.targets, identityKey(did))
+}
+
+// identityKey returns the target key for a watched identity
+func identityKey(did string) string {
+	return "identity:" + did
+}
+
+// Remove stops watching params
+func (w *Watcher) Remove(params LinksParams) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.targets, CountKey(params, false))
+}
+
+// Interval returns the current polling interval for params, or zero if it isn't watched
+func (w *Watcher) Interval(params LinksParams) time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if target, ok := w.targets[CountKey(params, false)]; ok {
+		return target.interval
+	}
+	return 0
+}
+
+// Smoothed returns the latest smoothed count and rate for params, and false if it
+// isn't watched or hasn't been polled yet
+func (w *Watcher) Smoothed(params LinksParams) (SmoothedPoint, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	target, ok := w.targets[CountKey(params, false)]
+	if !ok || !target.polled {
+		return SmoothedPoint{}, false
+	}
+	return target.ema.Point(), true
+}
+
+// bounds returns MinInterval and MaxInterval, defaulted and clamped to sane
+// values. The caller must hold w.mu.
+func (w *Watcher) bounds() (minInterval, maxInterval time.Duration) {
+	minInterval, maxInterval = w.MinInterval, w.MaxInterval
+	if minInterval <= 0 {
+		minInterval = DefaultWatchMinInterval
+	}
+	minInterval = maxOf(minInterval, MinWatchInterval)
+	if maxInterval <= 0 {
+		maxInterval = DefaultWatchMaxInterval
+	}
+	return minInterval, maxOf(maxInterval, minInterval)
+}
+
+// Run polls due targets until ctx is done, returning the context's error
+func (w *Watcher) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.wake:
+		case <-timer.C:
+		}
+
+		w.pollDue(ctx, w.due(time.Now()))
+
+		timer.Reset(time.Until(w.nextDue()))
+	}
+}
+
+// pollDue polls targets in parallel, then reports their changes in order
+func (w *Watcher) pollDue(ctx context.Context, targets []*watchedTarget) {
+	parallelism := w.Parallelism
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+
+	events := make([]*WatchEvent, len(targets))
+	identityEvents := make([]*IdentityEvent, len(targets))
+	parallelEach(len(targets), minOf(parallelism, len(targets)), func(i int) {
+		if targets[i].did != "" {
+			identityEvents[i] = w.pollIdentity(ctx, targets[i])
+		} else {
+			events[i] = w.poll(ctx, targets[i])
+		}
+	})
+
+	for i := range targets {
+		if events[i] != nil && w.OnChange != nil {
+			w.OnChange(*events[i])
+		}
+		if identityEvents[i] != nil && w.OnIdentityChange != nil {
+			w.OnIdentityChange(*identityEvents[i])
+		}
+	}
+}
+
+// due returns the targets whose next poll time has passed
+func (w *Watcher) due(now time.Time) []*watchedTarget {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var due []*watchedTarget
+	for _, target := range w.targets {
+		if !target.next.After(now) {
+			due = append(due, target)
+		}
+	}
+	return due
+}
+
+// nextDue returns the earliest next poll time, or MaxInterval from now if nothing
+// is watched
+func (w *Watcher) nextDue() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, maxInterval := w.bounds()
+	next := time.Now().Add(maxInterval)
+	for _, target := range w.targets {
+		if target.next.Before(next) {
+			next = target.next
+		}
+	}
+	return next
+}
+
+// poll fetches a target's count and reschedules it, returning the change to
+// report, if any
+func (w *Watcher) poll(ctx context.Context, target *watchedTarget) *WatchEvent {
+	count, err := w.client.GetLinksCountContext(ctx, target.params)
+	if ctx.Err() != nil {
+		return nil
+	}
+	now := time.Now()
+
+	w.mu.Lock()
+	minInterval, maxInterval := w.bounds()
+	var event *WatchEvent
+	var point SmoothedPoint
+	if err == nil {
+		point = target.ema.Add(float64(count.Total), now)
+	}
+	switch {
+	case err != nil:
+		// Back off on errors as for a quiet target
+		target.interval = minOf(target.interval*2, maxInterval)
+	case target.polled && count.Total != target.total:
+		event = &WatchEvent{
+			Params:   target.params,
+			Previous: target.total,
+			Total:    count.Total,
+			At:       now,
+			Smoothed: point.Smoothed,
+			Rate:     point.Rate,
+		}
+		target.total = count.Total
+		target.interval = maxOf(target.interval/2, minInterval)
+	case target.polled:
+		target.interval = minOf(target.interval*2, maxInterval)
+	default:
+		target.total = count.Total
+		target.polled = true
+	}
+	target.next = now.Add(target.interval)
+	w.mu.Unlock()
+
+	if err != nil {
+		w.client.warn("watch poll failed", "target", target.params.Target, "error", err)
+	}
+	return event
+}
+
+// pollIdentity resolves a watched DID document and reschedules it, returning the
+// change to report, if any
+func (w *Watcher) pollIdentity(ctx context.Context, target *watchedTarget) *IdentityEvent {
+	doc, err := w.client.ResolveDID(ctx, target.did)
+	if ctx.Err() != nil {
+		return nil
+	}
+	now := time.Now()
+
+	w.mu.Lock()
+	minInterval, maxInterval := w.bounds()
+	var event *IdentityEvent
+	switch {
+	case err != nil:
+		target.interval = minOf(target.interval*2, maxInterval)
+	case target.polled:
+		event = compareIdentities(target.did, target.doc, doc, now)
+		target.doc = doc
+		if event != nil {
+			target.interval = maxOf(target.interval/2, minInterval)
+		} else {
+			target.interval = minOf(target.interval*2, maxInterval)
+		}
+	default:
+		target.doc = doc
+		target.polled = true
+	}
+	target.next = now.Add(target.interval)
+	w.mu.Unlock()
+
+	if err != nil {
+		w.client.warn("identity poll failed", "did", target.did, "error", err)
+	}
+	return event
+}
+
+// compareIdentities returns an event describing the differences between two DID
+// documents, or nil if nothing watched changed
+func compareIdentities(did string, previous, current *DIDDocument, at time.Time) *IdentityEvent {
+	event := &IdentityEvent{
+		DID:           did,
+		Previous:      previous,
+		Current:       current,
+		At:            at,
+		HandleChanged: previous.Handle() != current.Handle(),
+		KeysChanged:   !sameKeys(previous.VerificationMethod, current.VerificationMethod),
+		PDSChanged:    previous.PDS() != current.PDS(),
+	}
+	if !event.HandleChanged && !event.KeysChanged && !event.PDSChanged {
+		return nil
+	}
+	return event
+}
+
+// sameKeys reports whether two key lists hold the same keys, ignoring order
+func sameKeys(a, b []VerificationMethod) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	keys := make(map[string]int, len(a))
+	for _, method := range a {
+		keys[method.ID+" "+method.PublicKeyMultibase]++
+	}
+	for _, method := range b {
+		key := method.ID + " " + method.PublicKeyMultibase
+		if keys[key] == 0 {
+			return false
+		}
+		keys[key]--
+	}
+	return true
+}
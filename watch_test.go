@@ -0,0 +1,182 @@
+package constellation_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestWatcherAdaptsToActivity tests change reporting and interval adaptation
+func TestWatcherAdaptsToActivity(t *testing.T) {
+	var mu sync.Mutex
+	polls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := r.URL.Query().Get("target")
+		mu.Lock()
+		polls[target]++
+		n := polls[target]
+		mu.Unlock()
+
+		total := 1
+		if target == "active" {
+			total = n // Grows on every poll
+		}
+		fmt.Fprintf(w, `{"total": %d}`, total)
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	watcher := constellation.NewWatcher(client)
+	watcher.MinInterval = 10 * time.Millisecond
+	watcher.MaxInterval = 80 * time.Millisecond
+
+	var events []constellation.WatchEvent
+	var eventsMu sync.Mutex
+	watcher.OnChange = func(event constellation.WatchEvent) {
+		eventsMu.Lock()
+		events = append(events, event)
+		eventsMu.Unlock()
+	}
+
+	active := constellation.LinksParams{Target: "active"}
+	quiet := constellation.LinksParams{Target: "quiet"}
+	watcher.Add(active)
+	watcher.Add(quiet)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	watcher.Run(ctx)
+
+	if got := watcher.Interval(active); got != 10*time.Millisecond {
+		t.Errorf("Expected active target at the minimum interval, got %v", got)
+	}
+	if got := watcher.Interval(quiet); got != 80*time.Millisecond {
+		t.Errorf("Expected quiet target at the maximum interval, got %v", got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if polls["active"] <= polls["quiet"] {
+		t.Errorf("Expected the active target to be polled more often, got %v", polls)
+	}
+
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	if len(events) == 0 || events[0].Params.Target != "active" || events[0].Delta() != 1 {
+		t.Errorf("Expected change events for the active target, got %+v", events)
+	}
+	if len(events) > 0 {
+		if last := events[len(events)-1]; last.Rate <= 0 || last.Smoothed <= 0 || last.Smoothed > float64(last.Total) {
+			t.Errorf("Expected a positive smoothed rate trailing the count, got %+v", last)
+		}
+	}
+	if point, ok := watcher.Smoothed(quiet); !ok || point.Rate != 0 || point.Smoothed != 1 {
+		t.Errorf("Expected a flat smoothed count for the quiet target, got %+v, %v", point, ok)
+	}
+}
+
+// TestWatcherIdentityChange tests reporting handle and key rotations
+func TestWatcherIdentityChange(t *testing.T) {
+	var mu sync.Mutex
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		polls++
+		n := polls
+		mu.Unlock()
+
+		handle, key := "alice.test", "zOld"
+		if n > 1 {
+			handle, key = "alice.example", "zNew"
+		}
+		fmt.Fprintf(w, `{"id": "did:plc:alice", "alsoKnownAs": ["at://%s"], "verificationMethod": [{"id": "did:plc:alice#atproto", "publicKeyMultibase": %q}]}`, handle, key)
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.PLCDirectory = server.URL
+	watcher := constellation.NewWatcher(client)
+	watcher.MinInterval = 10 * time.Millisecond
+	watcher.MaxInterval = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan constellation.IdentityEvent, 1)
+	watcher.OnIdentityChange = func(event constellation.IdentityEvent) {
+		select {
+		case events <- event:
+		default:
+		}
+		cancel()
+	}
+	watcher.WatchIdentity("did:plc:alice")
+
+	go watcher.Run(ctx)
+	select {
+	case event := <-events:
+		if !event.HandleChanged || !event.KeysChanged || event.PDSChanged {
+			t.Errorf("Expected handle and key changes only, got %+v", event)
+		}
+		if event.Previous.Handle() != "alice.test" || event.Current.Handle() != "alice.example" {
+			t.Errorf("Unexpected handles: %s -> %s", event.Previous.Handle(), event.Current.Handle())
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected an identity change event")
+	}
+}
+
+// TestWatcherBounds tests that invalid intervals are clamped rather than making
+// Run poll in a tight loop, and that due targets are polled in parallel
+func TestWatcherBounds(t *testing.T) {
+	var mu sync.Mutex
+	polls, inFlight, maxInFlight := 0, 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		polls++
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		w.Write([]byte(`{"total": 1}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	watcher := constellation.NewWatcher(client)
+	watcher.MinInterval = -time.Second
+	watcher.MaxInterval = 0
+	watcher.Parallelism = 3
+
+	targets := []string{"a", "b", "c"}
+	for _, target := range targets {
+		watcher.Add(constellation.LinksParams{Target: target})
+	}
+	if got := watcher.Interval(constellation.LinksParams{Target: "a"}); got != constellation.DefaultWatchMinInterval {
+		t.Errorf("Expected a negative MinInterval to take the default, got %v", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	watcher.Run(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if polls != len(targets) {
+		t.Errorf("Expected one poll per target within the minimum interval, got %d", polls)
+	}
+	if maxInFlight != len(targets) {
+		t.Errorf("Expected due targets to be polled in parallel, got at most %d at once", maxInFlight)
+	}
+}
@@ -0,0 +1,84 @@
+package constellation
+
+import "strings"
+
+// Well-known Bluesky collections
+const (
+	CollectionPost           = "app.bsky.feed.post"
+	CollectionLike           = "app.bsky.feed.like"
+	CollectionRepost         = "app.bsky.feed.repost"
+	CollectionThreadgate     = "app.bsky.feed.threadgate"
+	CollectionPostgate       = "app.bsky.feed.postgate"
+	CollectionFeedGenerator  = "app.bsky.feed.generator"
+	CollectionFollow         = "app.bsky.graph.follow"
+	CollectionBlock          = "app.bsky.graph.block"
+	CollectionList           = "app.bsky.graph.list"
+	CollectionListItem       = "app.bsky.graph.listitem"
+	CollectionListBlock      = "app.bsky.graph.listblock"
+	CollectionStarterPack    = "app.bsky.graph.starterpack"
+	CollectionLabelerService = "app.bsky.labeler.service"
+)
+
+// Well-known paths to links within Bluesky records
+const (
+	PathSubject               = ".subject"                  // Follows, blocks, and list items: the subject DID
+	PathSubjectURI            = ".subject.uri"              // Likes and reposts: the subject record
+	PathReplyParentURI        = ".reply.parent.uri"         // Posts: the post being replied to
+	PathReplyRootURI          = ".reply.root.uri"           // Posts: the root of the thread
+	PathEmbedRecordURI        = ".embed.record.uri"         // Posts: a quoted record
+	PathEmbedMediaURI         = ".embed.record.record.uri"  // Posts: a quoted record alongside media
+	PathEmbedExternalURI      = ".embed.external.uri"       // Posts: a link card
+	PathEmbedMediaExternalURI = ".embed.media.external.uri" // Posts: a link card alongside a quoted record
+	PathList                  = ".list"                     // List items and starter packs: the list
+	PathPost                  = ".post"                     // Threadgates and postgates: the gated post
+	PathDetachedEmbeddingURIs = ".detachedEmbeddingUris[]"  // Postgates: quotes detached from the gated post
+
+	PathFacetLinkURI = ".facets[].features[app.bsky.richtext.facet#link].uri" // Posts: a link in the text
+)
+
+// TargetKind classifies link targets, which determines the path a collection
+// links them from
+type TargetKind int
+
+const (
+	TargetUnknown TargetKind = iota
+	TargetURI                // An AT URI, e.g. a post
+	TargetDID                // An account
+	TargetURL                // A web URL
+)
+
+// TargetKindOf infers the kind of target from its scheme
+func TargetKindOf(target string) TargetKind {
+	switch {
+	case strings.HasPrefix(target, "at://"):
+		return TargetURI
+	case strings.HasPrefix(target, "did:"):
+		return TargetDID
+	case strings.HasPrefix(target, "https://"), strings.HasPrefix(target, "http://"):
+		return TargetURL
+	}
+	return TargetUnknown
+}
+
+// DefaultPathFor returns the path collection links a kind of target from, and
+// false if the collection isn't well-known or the path is ambiguous. Paths come
+// from the presets in DefaultRegistry, so registering a preset pack extends
+// inference; a collection linking a kind from several paths, such as posts
+// linking other posts as replies and quotes, is never inferred. Presets named
+// outside their collection's namespace, like whtwnd.comments for posts linking
+// web pages from link cards, describe one app's usage and don't count.
+func DefaultPathFor(collection string, kind TargetKind) (string, bool) {
+	return DefaultRegistry.pathFor(collection, kind)
+}
+
+// withInferredPath fills in an empty Path from the collection and target when
+// there is a single well-known choice
+func (p LinksParams) withInferredPath() LinksParams {
+	if p.Path != "" || p.Collection == "" {
+		return p
+	}
+	if path, ok := DefaultPathFor(p.Collection, TargetKindOf(p.Target)); ok {
+		p.Path = path
+	}
+	return p
+}
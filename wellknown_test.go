@@ -0,0 +1,79 @@
+package constellation_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestDefaultPathFor tests path lookup for well-known collections
+func TestDefaultPathFor(t *testing.T) {
+	tests := []struct {
+		collection string
+		kind       constellation.TargetKind
+		want       string
+		ok         bool
+	}{
+		{constellation.CollectionLike, constellation.TargetURI, constellation.PathSubjectURI, true},
+		{constellation.CollectionFollow, constellation.TargetDID, constellation.PathSubject, true},
+		{constellation.CollectionListItem, constellation.TargetURI, constellation.PathList, true},
+		{constellation.CollectionLike, constellation.TargetDID, "", false},
+		{constellation.CollectionPost, constellation.TargetURI, "", false},
+		{constellation.CollectionPost, constellation.TargetURL, "", false},
+		{"com.example.unknown", constellation.TargetURI, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := constellation.DefaultPathFor(tt.collection, tt.kind)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("DefaultPathFor(%s, %d) = %q, %v; want %q, %v", tt.collection, tt.kind, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// TestTargetKindOf tests classifying targets by scheme
+func TestTargetKindOf(t *testing.T) {
+	tests := map[string]constellation.TargetKind{
+		"at://did:plc:a/app.bsky.feed.post/1": constellation.TargetURI,
+		"did:plc:a":                           constellation.TargetDID,
+		"https://example.com":                 constellation.TargetURL,
+		"example":                             constellation.TargetUnknown,
+	}
+	for target, want := range tests {
+		if got := constellation.TargetKindOf(target); got != want {
+			t.Errorf("TargetKindOf(%q) = %d, want %d", target, got, want)
+		}
+	}
+}
+
+// TestPathInference tests that an empty path is filled in for well-known
+// collections and left alone otherwise
+func TestPathInference(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Query().Get("path")
+		w.Write([]byte(`{"total": 0}`))
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	tests := []struct {
+		params constellation.LinksParams
+		want   string
+	}{
+		{constellation.LinksParams{Target: "did:plc:a", Collection: constellation.CollectionFollow}, ".subject"},
+		{constellation.LinksParams{Target: "at://did:plc:a/app.bsky.feed.post/1", Collection: constellation.CollectionLike}, ".subject.uri"},
+		{constellation.LinksParams{Target: "at://did:plc:a/app.bsky.feed.post/1", Collection: constellation.CollectionLike, Path: ".custom"}, ".custom"},
+		{constellation.LinksParams{Target: "at://did:plc:a/app.bsky.feed.post/1", Collection: constellation.CollectionPost}, ""},
+	}
+	for _, tt := range tests {
+		if _, err := client.GetLinksCount(tt.params); err != nil {
+			t.Fatalf("Failed to get count: %v", err)
+		}
+		if path != tt.want {
+			t.Errorf("Expected path %q for %+v, got %q", tt.want, tt.params, path)
+		}
+	}
+}
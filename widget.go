@@ -0,0 +1,132 @@
+package constellation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultWidgetLikers is the default number of recent likers included in a widget
+	DefaultWidgetLikers = 5
+	// DefaultWidgetMaxAge is the default Cache-Control max-age for widget responses
+	DefaultWidgetMaxAge = 60 * time.Second
+)
+
+// Widget is a compact engagement summary for a post, suited to embedding like and
+// comment widgets on pages that mirror Bluesky posts
+type Widget struct {
+	URI          string        `json:"uri"`
+	Likes        int           `json:"likes"`
+	Reposts      int           `json:"reposts"`
+	Quotes       int           `json:"quotes"`
+	RecentLikers []WidgetActor `json:"recentLikers"`
+}
+
+// WidgetActor identifies an account shown in a widget
+type WidgetActor struct {
+	DID    string `json:"did"`
+	Handle string `json:"handle,omitempty"`
+}
+
+// WidgetOptions controls widget contents and the caching of served widgets
+type WidgetOptions struct {
+	RecentLikers int           // Number of recent likers to include; defaults to DefaultWidgetLikers
+	MaxAge       time.Duration // Cache-Control max-age; defaults to DefaultWidgetMaxAge
+
+	// ResolveHandle, if set, replaces Client.VerifiedHandle for looking up liker
+	// handles. Likers whose handles fail to resolve or verify are listed by DID
+	// alone, so an account can't display a handle it doesn't control.
+	ResolveHandle func(ctx context.Context, did string) (string, error)
+}
+
+// GetWidget builds the engagement widget for a post, fetching the counts, recent
+// likers, and their handles concurrently
+func (c *Client) GetWidget(ctx context.Context, postURI string, opts WidgetOptions) (*Widget, error) {
+	if postURI == "" {
+		return nil, fmt.Errorf("post URI is required")
+	}
+	if opts.RecentLikers <= 0 {
+		opts.RecentLikers = DefaultWidgetLikers
+	}
+	resolve := opts.ResolveHandle
+	if resolve == nil {
+		resolve = c.VerifiedHandle
+	}
+
+	widget := &Widget{URI: postURI, RecentLikers: []WidgetActor{}}
+	var likes *LinksResponse
+	errs := fanOut(
+		func() error {
+			count, err := c.GetLinksCountContext(ctx, likesParams(postURI))
+			if err == nil {
+				widget.Likes = count.Total
+			}
+			return err
+		},
+		func() error {
+			count, err := c.GetLinksCountContext(ctx, repostsParams(postURI))
+			if err == nil {
+				widget.Reposts = count.Total
+			}
+			return err
+		},
+		func() (err error) {
+			widget.Quotes, err = c.QuoteCount(ctx, postURI)
+			return err
+		},
+		func() (err error) {
+			params := likesParams(postURI)
+			params.Limit = opts.RecentLikers
+			likes, err = c.GetLinksContext(ctx, params)
+			return err
+		},
+	)
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	widget.RecentLikers = make([]WidgetActor, len(likes.LinkingRecords))
+	parallelEach(len(likes.LinkingRecords), DefaultParallelism, func(i int) {
+		did := likes.LinkingRecords[i].DID
+		widget.RecentLikers[i] = WidgetActor{DID: did}
+		if handle, err := resolve(ctx, did); err == nil {
+			widget.RecentLikers[i].Handle = handle
+		}
+	})
+
+	return widget, nil
+}
+
+// WidgetHandler returns an HTTP handler serving widgets as JSON for the post given
+// in the "uri" query parameter, with Cache-Control headers so CDNs and browsers
+// can absorb embed traffic
+func (c *Client) WidgetHandler(opts WidgetOptions) http.Handler {
+	maxAge := opts.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultWidgetMaxAge
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		postURI := r.URL.Query().Get("uri")
+		if postURI == "" {
+			http.Error(w, "uri parameter is required", http.StatusBadRequest)
+			return
+		}
+
+		widget, err := c.GetWidget(r.Context(), postURI, opts)
+		if err != nil {
+			c.warn("widget request failed", "uri", postURI, "error", err)
+			http.Error(w, "failed to load engagement counts", http.StatusBadGateway)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		json.NewEncoder(w).Encode(widget)
+	})
+}
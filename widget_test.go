@@ -0,0 +1,101 @@
+package constellation_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanner-caffrey/constellation-go"
+)
+
+// TestWidgetHandler tests the widget payload and caching headers
+func TestWidgetHandler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/links/count":
+			totals := map[string]int{".subject.uri": 0, ".embed.record.uri": 1, ".embed.record.record.uri": 2}
+			if collection := r.URL.Query().Get("collection"); collection == "app.bsky.feed.like" {
+				totals[".subject.uri"] = 10
+			} else if collection == "app.bsky.feed.repost" {
+				totals[".subject.uri"] = 3
+			}
+			fmt.Fprintf(w, `{"total": %d}`, totals[r.URL.Query().Get("path")])
+		case r.URL.Path == "/links":
+			w.Write([]byte(`{"total": 10, "linking_records": [{"did": "did:plc:alice"}, {"did": "did:plc:bob"}]}`))
+		case r.URL.Path == "/did:plc:alice":
+			w.Write([]byte(`{"id": "did:plc:alice", "alsoKnownAs": ["at://alice.test"]}`))
+		case r.URL.Path == "/did:plc:bob":
+			// Bob claims Alice's handle, which doesn't resolve back to him
+			w.Write([]byte(`{"id": "did:plc:bob", "alsoKnownAs": ["at://alice.test"]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	client.PLCDirectory = server.URL
+	client.HTTPClient.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Host == "alice.test" && r.URL.Path == "/.well-known/atproto-did" {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("did:plc:alice\n"))}, nil
+		}
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/widget?uri=at://did:plc:example/app.bsky.feed.post/1", nil)
+	client.WidgetHandler(constellation.WidgetOptions{MaxAge: time.Minute}).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "public, max-age=60" {
+		t.Errorf("Expected Cache-Control public, max-age=60, got %q", got)
+	}
+
+	var widget constellation.Widget
+	if err := json.NewDecoder(rec.Body).Decode(&widget); err != nil {
+		t.Fatalf("Failed to decode widget: %v", err)
+	}
+	if widget.Likes != 10 || widget.Reposts != 3 || widget.Quotes != 3 {
+		t.Errorf("Unexpected counts: %+v", widget)
+	}
+	if len(widget.RecentLikers) != 2 || widget.RecentLikers[0].Handle != "alice.test" || widget.RecentLikers[1].Handle != "" {
+		t.Errorf("Unexpected recent likers: %+v", widget.RecentLikers)
+	}
+}
+
+// TestWidgetHandlerMissingURI tests that the uri parameter is required
+func TestWidgetHandlerMissingURI(t *testing.T) {
+	rec := httptest.NewRecorder()
+	constellation.NewClient().WidgetHandler(constellation.WidgetOptions{}).ServeHTTP(rec, httptest.NewRequest("GET", "/widget", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
+
+// TestWidgetHandlerUpstreamError tests that upstream failures aren't echoed to
+// the caller
+func TestWidgetHandlerUpstreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "internal detail", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := constellation.NewClientWithConfig(server.URL, 5*time.Second)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/widget?uri=at://did:plc:example/app.bsky.feed.post/1", nil)
+	client.WidgetHandler(constellation.WidgetOptions{}).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("Expected status 502, got %d", rec.Code)
+	}
+	if body := rec.Body.String(); strings.Contains(body, server.URL) || strings.Contains(body, "internal detail") {
+		t.Errorf("Expected a generic error, got %q", body)
+	}
+}